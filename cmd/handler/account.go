@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	transactionProto "github.com/justIGreK/MoneyKeeper-Transaction/pkg/go/transaction"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type AccountServiceServer struct {
+	transactionProto.UnimplementedAccountServiceServer
+	AccountSRV AccountService
+}
+
+type AccountService interface {
+	CreateAccount(ctx context.Context, account models.CreateAccount) (string, error)
+	GetAccount(ctx context.Context, accountID, userID string) (*models.Account, error)
+	GetAccounts(ctx context.Context, userID string) ([]models.Account, error)
+	UpdateAccount(ctx context.Context, updates models.UpdateAccount) (*models.Account, error)
+	DeleteAccount(ctx context.Context, accountID, userID string) error
+	Transfer(ctx context.Context, transfer models.CreateTransfer) (*models.Transfer, error)
+	GetAccountBalance(ctx context.Context, accountID, userID, asOf string) (*models.AccountBalance, error)
+}
+
+func (s *AccountServiceServer) CreateAccount(ctx context.Context, req *transactionProto.CreateAccountRequest) (*transactionProto.CreateAccountResponse, error) {
+	id, err := s.AccountSRV.CreateAccount(ctx, models.CreateAccount{
+		UserID:         req.UserId,
+		Name:           req.Name,
+		Type:           req.Type,
+		Currency:       req.Currency,
+		OpeningBalance: req.OpeningBalance,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &transactionProto.CreateAccountResponse{AccountId: id}, nil
+}
+
+func (s *AccountServiceServer) GetAccount(ctx context.Context, req *transactionProto.GetAccountRequest) (*transactionProto.GetAccountResponse, error) {
+	account, err := s.AccountSRV.GetAccount(ctx, req.AccountId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return &transactionProto.GetAccountResponse{Account: convertToProtoAccount(*account)}, nil
+}
+
+func (s *AccountServiceServer) GetAccountList(ctx context.Context, req *transactionProto.GetAccountListRequest) (*transactionProto.GetAccountListResponse, error) {
+	accounts, err := s.AccountSRV.GetAccounts(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	protoAccounts := make([]*transactionProto.Account, len(accounts))
+	for i, a := range accounts {
+		protoAccounts[i] = convertToProtoAccount(a)
+	}
+	return &transactionProto.GetAccountListResponse{Accounts: protoAccounts}, nil
+}
+
+func (s *AccountServiceServer) UpdateAccount(ctx context.Context, req *transactionProto.UpdateAccountRequest) (*transactionProto.GetAccountResponse, error) {
+	updates := models.UpdateAccount{
+		ID:     req.AccountId,
+		UserID: req.UserId,
+	}
+	if req.Name != nil {
+		updates.Name = &req.Name.Value
+	}
+	if req.Type != nil {
+		updates.Type = &req.Type.Value
+	}
+	account, err := s.AccountSRV.UpdateAccount(ctx, updates)
+	if err != nil {
+		return nil, err
+	}
+	return &transactionProto.GetAccountResponse{Account: convertToProtoAccount(*account)}, nil
+}
+
+func (s *AccountServiceServer) DeleteAccount(ctx context.Context, req *transactionProto.GetAccountRequest) (*emptypb.Empty, error) {
+	if err := s.AccountSRV.DeleteAccount(ctx, req.AccountId, req.UserId); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *AccountServiceServer) Transfer(ctx context.Context, req *transactionProto.TransferRequest) (*transactionProto.TransferResponse, error) {
+	transfer := models.CreateTransfer{
+		UserID:        req.UserId,
+		FromAccountID: req.FromAccountId,
+		ToAccountID:   req.ToAccountId,
+		Amount:        req.Amount,
+		Note:          req.Note,
+	}
+	if req.Date != nil {
+		transfer.Date = &req.Date.Value
+	}
+	result, err := s.AccountSRV.Transfer(ctx, transfer)
+	if err != nil {
+		return nil, err
+	}
+	return &transactionProto.TransferResponse{
+		TransferId:   result.ID,
+		OutgoingTxId: result.OutgoingTxID,
+		IncomingTxId: result.IncomingTxID,
+	}, nil
+}
+
+func (s *AccountServiceServer) GetAccountBalance(ctx context.Context, req *transactionProto.GetAccountBalanceRequest) (*transactionProto.GetAccountBalanceResponse, error) {
+	balance, err := s.AccountSRV.GetAccountBalance(ctx, req.AccountId, req.UserId, req.AsOf)
+	if err != nil {
+		return nil, err
+	}
+	return &transactionProto.GetAccountBalanceResponse{
+		AccountId: balance.AccountID,
+		Currency:  balance.Currency,
+		AsOf:      balance.AsOf.Format(DateTimeformat),
+		Balance:   balance.Balance,
+	}, nil
+}
+
+func convertToProtoAccount(a models.Account) *transactionProto.Account {
+	return &transactionProto.Account{
+		Id:             a.ID,
+		UserId:         a.UserID,
+		Name:           a.Name,
+		Type:           a.Type,
+		Currency:       a.Currency,
+		OpeningBalance: a.OpeningBalance,
+		CreatedAt:      a.CreatedAt.Format(DateTimeformat),
+	}
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"context"
+	"io"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	transactionProto "github.com/justIGreK/MoneyKeeper-Transaction/pkg/go/transaction"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const attachmentChunkSize = 64 * 1024
+
+type AttachmentServiceServer struct {
+	transactionProto.UnimplementedAttachmentServiceServer
+	AttachmentSRV AttachmentService
+}
+
+type AttachmentService interface {
+	UploadAttachment(ctx context.Context, upload models.UploadAttachment, src io.Reader) (*models.Attachment, error)
+	OpenAttachment(ctx context.Context, attachmentID, userID string) (*models.Attachment, io.ReadCloser, error)
+	GetAttachments(ctx context.Context, txID, userID string) ([]models.Attachment, error)
+	DeleteAttachment(ctx context.Context, attachmentID, userID string) error
+}
+
+func (s *AttachmentServiceServer) UploadAttachment(stream transactionProto.AttachmentService_UploadAttachmentServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	info := first.GetInfo()
+	if info == nil {
+		return status.Error(codes.InvalidArgument, "first message must carry the attachment info")
+	}
+	attachment, err := s.AttachmentSRV.UploadAttachment(stream.Context(), models.UploadAttachment{
+		UserID:      info.UserId,
+		TxID:        info.TxId,
+		FileName:    info.FileName,
+		ContentType: info.ContentType,
+		SHA256:      info.Sha256,
+	}, &chunkReader{stream: stream})
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(&transactionProto.UploadAttachmentResponse{Attachment: convertToProtoAttachment(*attachment)})
+}
+
+func (s *AttachmentServiceServer) DownloadAttachment(req *transactionProto.DownloadAttachmentRequest, stream transactionProto.AttachmentService_DownloadAttachmentServer) error {
+	attachment, content, err := s.AttachmentSRV.OpenAttachment(stream.Context(), req.AttachmentId, req.UserId)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+	err = stream.Send(&transactionProto.DownloadAttachmentResponse{
+		Data: &transactionProto.DownloadAttachmentResponse_Info{Info: convertToProtoAttachment(*attachment)},
+	})
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, attachmentChunkSize)
+	for {
+		n, err := io.ReadFull(content, buf)
+		if n > 0 {
+			chunk := &transactionProto.DownloadAttachmentResponse{
+				Data: &transactionProto.DownloadAttachmentResponse_Chunk{Chunk: buf[:n]},
+			}
+			if err := stream.Send(chunk); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *AttachmentServiceServer) ListAttachments(ctx context.Context, req *transactionProto.ListAttachmentsRequest) (*transactionProto.ListAttachmentsResponse, error) {
+	attachments, err := s.AttachmentSRV.GetAttachments(ctx, req.TxId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	resp := &transactionProto.ListAttachmentsResponse{
+		Attachments: make([]*transactionProto.Attachment, len(attachments)),
+	}
+	for i, a := range attachments {
+		resp.Attachments[i] = convertToProtoAttachment(a)
+	}
+	return resp, nil
+}
+
+func (s *AttachmentServiceServer) DeleteAttachment(ctx context.Context, req *transactionProto.DeleteAttachmentRequest) (*emptypb.Empty, error) {
+	if err := s.AttachmentSRV.DeleteAttachment(ctx, req.AttachmentId, req.UserId); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func convertToProtoAttachment(a models.Attachment) *transactionProto.Attachment {
+	return &transactionProto.Attachment{
+		Id:          a.ID,
+		UserId:      a.UserID,
+		TxId:        a.TxID,
+		FileName:    a.FileName,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		Sha256:      a.SHA256,
+		UploadedAt:  a.UploadedAt.Format(DateTimeformat),
+	}
+}
+
+// chunkReader exposes the chunks of an upload stream as an io.Reader.
+type chunkReader struct {
+	stream transactionProto.AttachmentService_UploadAttachmentServer
+	buf    []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		if req.GetInfo() != nil {
+			return 0, status.Error(codes.InvalidArgument, "attachment info must only be sent once")
+		}
+		r.buf = req.GetChunk()
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
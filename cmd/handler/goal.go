@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	transactionProto "github.com/justIGreK/MoneyKeeper-Transaction/pkg/go/transaction"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type GoalServiceServer struct {
+	transactionProto.UnimplementedGoalServiceServer
+	GoalSRV GoalService
+}
+
+type GoalService interface {
+	CreateGoal(ctx context.Context, create models.CreateGoal) (string, error)
+	GetGoal(ctx context.Context, goalID, userID string) (*models.GoalProgress, error)
+	GetGoals(ctx context.Context, userID string) ([]models.GoalProgress, error)
+	UpdateGoal(ctx context.Context, update models.CreateGoal) (*models.GoalProgress, error)
+	DeleteGoal(ctx context.Context, goalID, userID string) error
+	AddContribution(ctx context.Context, contribution models.GoalContribution) (string, error)
+}
+
+func (s *GoalServiceServer) CreateGoal(ctx context.Context, req *transactionProto.CreateGoalRequest) (*transactionProto.CreateGoalResponse, error) {
+	id, err := s.GoalSRV.CreateGoal(ctx, models.CreateGoal{
+		UserID:    req.UserId,
+		Name:      req.Name,
+		Target:    req.Target,
+		Deadline:  req.Deadline,
+		AccountID: req.AccountId,
+		Tag:       req.Tag,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &transactionProto.CreateGoalResponse{GoalId: id}, nil
+}
+
+func (s *GoalServiceServer) GetGoal(ctx context.Context, req *transactionProto.GetGoalRequest) (*transactionProto.GetGoalResponse, error) {
+	goal, err := s.GoalSRV.GetGoal(ctx, req.GoalId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return &transactionProto.GetGoalResponse{Goal: convertToProtoGoal(*goal)}, nil
+}
+
+func (s *GoalServiceServer) GetGoalList(ctx context.Context, req *transactionProto.GetGoalListRequest) (*transactionProto.GetGoalListResponse, error) {
+	goals, err := s.GoalSRV.GetGoals(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	resp := &transactionProto.GetGoalListResponse{Goals: make([]*transactionProto.Goal, len(goals))}
+	for i, g := range goals {
+		resp.Goals[i] = convertToProtoGoal(g)
+	}
+	return resp, nil
+}
+
+func (s *GoalServiceServer) UpdateGoal(ctx context.Context, req *transactionProto.UpdateGoalRequest) (*transactionProto.GetGoalResponse, error) {
+	goal, err := s.GoalSRV.UpdateGoal(ctx, models.CreateGoal{
+		ID:        req.GoalId,
+		UserID:    req.UserId,
+		Name:      req.Name,
+		Target:    req.Target,
+		Deadline:  req.Deadline,
+		AccountID: req.AccountId,
+		Tag:       req.Tag,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &transactionProto.GetGoalResponse{Goal: convertToProtoGoal(*goal)}, nil
+}
+
+func (s *GoalServiceServer) DeleteGoal(ctx context.Context, req *transactionProto.GetGoalRequest) (*emptypb.Empty, error) {
+	if err := s.GoalSRV.DeleteGoal(ctx, req.GoalId, req.UserId); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *GoalServiceServer) AddContribution(ctx context.Context, req *transactionProto.AddContributionRequest) (*transactionProto.AddContributionResponse, error) {
+	contribution := models.GoalContribution{
+		GoalID:        req.GoalId,
+		UserID:        req.UserId,
+		Amount:        req.Amount,
+		Note:          req.Note,
+		FromAccountID: req.FromAccountId,
+	}
+	if req.Date != nil {
+		contribution.Date = &req.Date.Value
+	}
+	id, err := s.GoalSRV.AddContribution(ctx, contribution)
+	if err != nil {
+		return nil, err
+	}
+	return &transactionProto.AddContributionResponse{TxId: id}, nil
+}
+
+func convertToProtoGoal(g models.GoalProgress) *transactionProto.Goal {
+	return &transactionProto.Goal{
+		Id:        g.ID,
+		UserId:    g.UserID,
+		Name:      g.Name,
+		Target:    g.Target,
+		Deadline:  g.Deadline.Format(Dateformat),
+		AccountId: g.AccountID,
+		Tag:       g.Tag,
+		CreatedAt: g.CreatedAt.Format(DateTimeformat),
+		Progress: &transactionProto.GoalProgress{
+			Saved:           g.Saved,
+			Remaining:       g.Remaining,
+			RequiredMonthly: g.RequiredMonthly,
+			Expected:        g.Expected,
+			OnTrack:         g.OnTrack,
+			Achieved:        g.Achieved,
+		},
+	}
+}
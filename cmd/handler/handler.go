@@ -8,15 +8,81 @@ import (
 type Handler struct {
 	server      grpc.ServiceRegistrar
 	transaction TransactionService
+	shared      SharedExpenseService
+	account     AccountService
+	attachment  AttachmentService
+	webhook     WebhookService
+	rule        RuleService
+	payee       PayeeService
+	goal        GoalService
+	statement   StatementService
+	privacy     PrivacyService
+	orphan      OrphanService
+	ledger      LedgerService
 }
 
-func NewHandler(grpcServer grpc.ServiceRegistrar, txSRV TransactionService) *Handler {
-	return &Handler{server: grpcServer, transaction: txSRV}
+func NewHandler(grpcServer grpc.ServiceRegistrar, txSRV TransactionService, sharedSRV SharedExpenseService, accountSRV AccountService, attachmentSRV AttachmentService, webhookSRV WebhookService, ruleSRV RuleService, payeeSRV PayeeService, goalSRV GoalService, statementSRV StatementService, privacySRV PrivacyService, orphanSRV OrphanService, ledgerSRV LedgerService) *Handler {
+	return &Handler{server: grpcServer, transaction: txSRV, shared: sharedSRV, account: accountSRV, attachment: attachmentSRV, webhook: webhookSRV, rule: ruleSRV, payee: payeeSRV, goal: goalSRV, statement: statementSRV, privacy: privacySRV, orphan: orphanSRV, ledger: ledgerSRV}
 }
 func (h *Handler) RegisterServices() {
 	h.registerTxService(h.server, h.transaction)
+	h.registerSharedExpenseService(h.server, h.shared)
+	h.registerAccountService(h.server, h.account)
+	h.registerAttachmentService(h.server, h.attachment)
+	h.registerWebhookService(h.server, h.webhook)
+	h.registerRuleService(h.server, h.rule)
+	h.registerPayeeService(h.server, h.payee)
+	h.registerGoalService(h.server, h.goal)
+	h.registerStatementService(h.server, h.statement)
+	h.registerPrivacyService(h.server, h.privacy)
+	h.registerOrphanService(h.server, h.orphan)
+	h.registerLedgerService(h.server, h.ledger)
 }
 
 func (h *Handler) registerTxService(server grpc.ServiceRegistrar, tx TransactionService) {
 	transactionProto.RegisterTransactionServiceServer(server, &TransactionServiceServer{TxSRV: tx})
 }
+
+func (h *Handler) registerSharedExpenseService(server grpc.ServiceRegistrar, shared SharedExpenseService) {
+	transactionProto.RegisterSharedExpenseServiceServer(server, &SharedExpenseServiceServer{SharedSRV: shared})
+}
+
+func (h *Handler) registerAccountService(server grpc.ServiceRegistrar, account AccountService) {
+	transactionProto.RegisterAccountServiceServer(server, &AccountServiceServer{AccountSRV: account})
+}
+
+func (h *Handler) registerAttachmentService(server grpc.ServiceRegistrar, attachment AttachmentService) {
+	transactionProto.RegisterAttachmentServiceServer(server, &AttachmentServiceServer{AttachmentSRV: attachment})
+}
+
+func (h *Handler) registerWebhookService(server grpc.ServiceRegistrar, webhook WebhookService) {
+	transactionProto.RegisterWebhookServiceServer(server, &WebhookServiceServer{WebhookSRV: webhook})
+}
+
+func (h *Handler) registerRuleService(server grpc.ServiceRegistrar, rule RuleService) {
+	transactionProto.RegisterRuleServiceServer(server, &RuleServiceServer{RuleSRV: rule})
+}
+
+func (h *Handler) registerPayeeService(server grpc.ServiceRegistrar, payee PayeeService) {
+	transactionProto.RegisterPayeeServiceServer(server, &PayeeServiceServer{PayeeSRV: payee})
+}
+
+func (h *Handler) registerGoalService(server grpc.ServiceRegistrar, goal GoalService) {
+	transactionProto.RegisterGoalServiceServer(server, &GoalServiceServer{GoalSRV: goal})
+}
+
+func (h *Handler) registerStatementService(server grpc.ServiceRegistrar, statement StatementService) {
+	transactionProto.RegisterStatementServiceServer(server, &StatementServiceServer{StatementSRV: statement})
+}
+
+func (h *Handler) registerPrivacyService(server grpc.ServiceRegistrar, privacy PrivacyService) {
+	transactionProto.RegisterPrivacyServiceServer(server, &PrivacyServiceServer{PrivacySRV: privacy})
+}
+
+func (h *Handler) registerOrphanService(server grpc.ServiceRegistrar, orphan OrphanService) {
+	transactionProto.RegisterOrphanServiceServer(server, &OrphanServiceServer{OrphanSRV: orphan})
+}
+
+func (h *Handler) registerLedgerService(server grpc.ServiceRegistrar, ledger LedgerService) {
+	transactionProto.RegisterLedgerServiceServer(server, &LedgerServiceServer{LedgerSRV: ledger})
+}
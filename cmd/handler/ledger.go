@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	transactionProto "github.com/justIGreK/MoneyKeeper-Transaction/pkg/go/transaction"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type LedgerServiceServer struct {
+	transactionProto.UnimplementedLedgerServiceServer
+	LedgerSRV LedgerService
+}
+
+type LedgerService interface {
+	CreateLedger(ctx context.Context, create models.CreateLedger) (*models.Ledger, error)
+	GetLedgers(ctx context.Context, userID string) ([]models.Ledger, error)
+	InviteMember(ctx context.Context, invite models.InviteMember) (*models.Ledger, error)
+	AcceptInvite(ctx context.Context, key models.LedgerKey) (*models.Ledger, error)
+	RemoveMember(ctx context.Context, remove models.RemoveMember) error
+	GetLedgerTransactions(ctx context.Context, key models.LedgerKey, timeframe models.CreateTimeFrame) ([]models.Transaction, error)
+}
+
+func (s *LedgerServiceServer) CreateLedger(ctx context.Context, req *transactionProto.CreateLedgerRequest) (*transactionProto.Ledger, error) {
+	ledger, err := s.LedgerSRV.CreateLedger(ctx, models.CreateLedger{UserID: req.UserId, Name: req.Name})
+	if err != nil {
+		return nil, err
+	}
+	return convertToProtoLedger(*ledger), nil
+}
+
+func (s *LedgerServiceServer) ListLedgers(ctx context.Context, req *transactionProto.ListLedgersRequest) (*transactionProto.ListLedgersResponse, error) {
+	ledgers, err := s.LedgerSRV.GetLedgers(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	resp := &transactionProto.ListLedgersResponse{Ledgers: make([]*transactionProto.Ledger, len(ledgers))}
+	for i, l := range ledgers {
+		resp.Ledgers[i] = convertToProtoLedger(l)
+	}
+	return resp, nil
+}
+
+func (s *LedgerServiceServer) InviteMember(ctx context.Context, req *transactionProto.InviteMemberRequest) (*transactionProto.Ledger, error) {
+	ledger, err := s.LedgerSRV.InviteMember(ctx, models.InviteMember{
+		LedgerID: req.LedgerId,
+		UserID:   req.UserId,
+		MemberID: req.MemberId,
+		Role:     req.Role,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return convertToProtoLedger(*ledger), nil
+}
+
+func (s *LedgerServiceServer) AcceptInvite(ctx context.Context, req *transactionProto.AcceptInviteRequest) (*transactionProto.Ledger, error) {
+	ledger, err := s.LedgerSRV.AcceptInvite(ctx, models.LedgerKey{ID: req.LedgerId, UserID: req.UserId})
+	if err != nil {
+		return nil, err
+	}
+	return convertToProtoLedger(*ledger), nil
+}
+
+func (s *LedgerServiceServer) RemoveMember(ctx context.Context, req *transactionProto.RemoveMemberRequest) (*emptypb.Empty, error) {
+	err := s.LedgerSRV.RemoveMember(ctx, models.RemoveMember{LedgerID: req.LedgerId, UserID: req.UserId, MemberID: req.MemberId})
+	if err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *LedgerServiceServer) ListLedgerTransactions(ctx context.Context, req *transactionProto.ListLedgerTransactionsRequest) (*transactionProto.ListLedgerTransactionsResponse, error) {
+	txs, err := s.LedgerSRV.GetLedgerTransactions(ctx, models.LedgerKey{ID: req.LedgerId, UserID: req.UserId},
+		models.CreateTimeFrame{StartDate: req.StartDate, EndDate: req.EndDate})
+	if err != nil {
+		return nil, err
+	}
+	return &transactionProto.ListLedgerTransactionsResponse{Transactions: convertToProtoTxs(txs)}, nil
+}
+
+func convertToProtoLedger(l models.Ledger) *transactionProto.Ledger {
+	ledger := &transactionProto.Ledger{
+		Id:        l.ID,
+		Name:      l.Name,
+		OwnerId:   l.OwnerID,
+		Members:   make([]*transactionProto.LedgerMember, len(l.Members)),
+		Invites:   make([]*transactionProto.LedgerInvite, len(l.Invites)),
+		CreatedAt: l.CreatedAt.Format(DateTimeformat),
+	}
+	for i, m := range l.Members {
+		ledger.Members[i] = &transactionProto.LedgerMember{
+			UserId:   m.UserID,
+			Role:     m.Role,
+			JoinedAt: m.JoinedAt.Format(DateTimeformat),
+		}
+	}
+	for i, inv := range l.Invites {
+		ledger.Invites[i] = &transactionProto.LedgerInvite{
+			UserId:    inv.UserID,
+			Role:      inv.Role,
+			InvitedBy: inv.InvitedBy,
+			CreatedAt: inv.CreatedAt.Format(DateTimeformat),
+		}
+	}
+	return ledger
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	transactionProto "github.com/justIGreK/MoneyKeeper-Transaction/pkg/go/transaction"
+)
+
+type OrphanServiceServer struct {
+	transactionProto.UnimplementedOrphanServiceServer
+	OrphanSRV OrphanService
+}
+
+type OrphanService interface {
+	ReconcileOrphans(ctx context.Context, req models.ReconcileOrphans) (*models.OrphanReport, error)
+}
+
+func (s *OrphanServiceServer) ReconcileOrphans(ctx context.Context, req *transactionProto.ReconcileOrphansRequest) (*transactionProto.OrphanReport, error) {
+	report, err := s.OrphanSRV.ReconcileOrphans(ctx, models.ReconcileOrphans{Confirm: req.Confirm, Policy: req.Policy})
+	if err != nil {
+		return nil, err
+	}
+	resp := &transactionProto.OrphanReport{
+		DryRun:       report.DryRun,
+		Policy:       report.Policy,
+		CheckedUsers: int32(report.CheckedUsers),
+		Unverified:   int32(report.Unverified),
+		Orphans:      make([]*transactionProto.OrphanedUser, len(report.Orphans)),
+		StartedAt:    report.StartedAt.Format(DateTimeformat),
+		FinishedAt:   report.FinishedAt.Format(DateTimeformat),
+	}
+	for i, o := range report.Orphans {
+		resp.Orphans[i] = &transactionProto.OrphanedUser{
+			UserId:       o.UserID,
+			Transactions: o.Transactions,
+			Action:       o.Action,
+			Archive:      o.Archive,
+			Error:        o.Error,
+			FirstSeen:    o.FirstSeen.Format(DateTimeformat),
+		}
+	}
+	return resp, nil
+}
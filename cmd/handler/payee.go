@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	transactionProto "github.com/justIGreK/MoneyKeeper-Transaction/pkg/go/transaction"
+)
+
+type PayeeServiceServer struct {
+	transactionProto.UnimplementedPayeeServiceServer
+	PayeeSRV PayeeService
+}
+
+type PayeeService interface {
+	GetPayees(ctx context.Context, userID string) ([]models.Payee, error)
+	MergePayees(ctx context.Context, req models.MergePayees) (*models.Payee, int64, error)
+	GetPayeeSummary(ctx context.Context, userID string, timeframe models.CreateTimeFrame) ([]models.PayeeTotal, error)
+}
+
+func (s *PayeeServiceServer) ListPayees(ctx context.Context, req *transactionProto.ListPayeesRequest) (*transactionProto.ListPayeesResponse, error) {
+	payees, err := s.PayeeSRV.GetPayees(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	resp := &transactionProto.ListPayeesResponse{Payees: make([]*transactionProto.Payee, len(payees))}
+	for i, p := range payees {
+		resp.Payees[i] = convertToProtoPayee(p)
+	}
+	return resp, nil
+}
+
+func (s *PayeeServiceServer) MergePayees(ctx context.Context, req *transactionProto.MergePayeesRequest) (*transactionProto.MergePayeesResponse, error) {
+	payee, moved, err := s.PayeeSRV.MergePayees(ctx, models.MergePayees{
+		UserID:    req.UserId,
+		TargetID:  req.TargetId,
+		SourceIDs: req.SourceIds,
+		Name:      req.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &transactionProto.MergePayeesResponse{
+		Payee:             convertToProtoPayee(*payee),
+		TransactionsMoved: moved,
+	}, nil
+}
+
+func (s *PayeeServiceServer) GetPayeeSummary(ctx context.Context, req *transactionProto.GetPayeeSummaryRequest) (*transactionProto.GetPayeeSummaryResponse, error) {
+	totals, err := s.PayeeSRV.GetPayeeSummary(ctx, req.UserId, models.CreateTimeFrame{StartDate: req.StartDate, EndDate: req.EndDate})
+	if err != nil {
+		return nil, err
+	}
+	resp := &transactionProto.GetPayeeSummaryResponse{
+		Payees: make([]*transactionProto.PayeeTotal, len(totals)),
+	}
+	for i, t := range totals {
+		resp.Payees[i] = &transactionProto.PayeeTotal{
+			PayeeId: t.PayeeID,
+			Name:    t.Name,
+			Total:   t.Total,
+			Count:   int32(t.Count),
+		}
+		resp.Total += t.Total
+	}
+	return resp, nil
+}
+
+func convertToProtoPayee(p models.Payee) *transactionProto.Payee {
+	return &transactionProto.Payee{
+		Id:        p.ID,
+		UserId:    p.UserID,
+		Name:      p.Name,
+		Aliases:   p.Aliases,
+		CreatedAt: p.CreatedAt.Format(DateTimeformat),
+	}
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	transactionProto "github.com/justIGreK/MoneyKeeper-Transaction/pkg/go/transaction"
+)
+
+type PrivacyServiceServer struct {
+	transactionProto.UnimplementedPrivacyServiceServer
+	PrivacySRV PrivacyService
+}
+
+type PrivacyService interface {
+	ExportUserData(ctx context.Context, userID string, w io.Writer) error
+	EraseUserData(ctx context.Context, userID string) (*models.Erasure, error)
+}
+
+func (s *PrivacyServiceServer) ExportUserData(req *transactionProto.ExportUserDataRequest, stream transactionProto.PrivacyService_ExportUserDataServer) error {
+	w := bufio.NewWriterSize(&chunkWriter{stream: stream}, attachmentChunkSize)
+	if err := s.PrivacySRV.ExportUserData(stream.Context(), req.UserId, w); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+func (s *PrivacyServiceServer) EraseUserData(ctx context.Context, req *transactionProto.EraseUserDataRequest) (*transactionProto.ErasureReceipt, error) {
+	erasure, err := s.PrivacySRV.EraseUserData(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	receipt := &transactionProto.ErasureReceipt{
+		UserId:    erasure.UserID,
+		StartedAt: erasure.StartedAt.Format(time.RFC3339Nano),
+		Steps:     make([]*transactionProto.ErasureStep, len(erasure.Steps)),
+		Digest:    erasure.Digest,
+	}
+	if erasure.CompletedAt != nil {
+		receipt.CompletedAt = erasure.CompletedAt.Format(time.RFC3339Nano)
+	}
+	for i, step := range erasure.Steps {
+		receipt.Steps[i] = &transactionProto.ErasureStep{
+			Store:       step.Store,
+			Deleted:     step.Deleted,
+			CompletedAt: step.CompletedAt.Format(time.RFC3339Nano),
+		}
+	}
+	return receipt, nil
+}
+
+// chunkWriter sends everything written to it as export chunks.
+type chunkWriter struct {
+	stream transactionProto.PrivacyService_ExportUserDataServer
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	if err := w.stream.Send(&transactionProto.ExportUserDataResponse{Chunk: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	transactionProto "github.com/justIGreK/MoneyKeeper-Transaction/pkg/go/transaction"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type RuleServiceServer struct {
+	transactionProto.UnimplementedRuleServiceServer
+	RuleSRV RuleService
+}
+
+type RuleService interface {
+	CreateRule(ctx context.Context, create models.CreateRule) (string, error)
+	GetRules(ctx context.Context, userID string) ([]models.Rule, error)
+	UpdateRule(ctx context.Context, update models.CreateRule) (*models.Rule, error)
+	DeleteRule(ctx context.Context, ruleID, userID string) error
+	ReapplyRules(ctx context.Context, req models.ReapplyRules, timeframe models.CreateTimeFrame) (*models.ReapplyResult, error)
+}
+
+func (s *RuleServiceServer) CreateRule(ctx context.Context, req *transactionProto.CreateRuleRequest) (*transactionProto.CreateRuleResponse, error) {
+	id, err := s.RuleSRV.CreateRule(ctx, models.CreateRule{
+		UserID:     req.UserId,
+		Name:       req.Name,
+		Priority:   int(req.Priority),
+		Enabled:    req.Enabled == nil || req.Enabled.Value,
+		Conditions: convertFromProtoConditions(req.Conditions),
+		Actions:    convertFromProtoActions(req.Actions),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &transactionProto.CreateRuleResponse{RuleId: id}, nil
+}
+
+func (s *RuleServiceServer) GetRuleList(ctx context.Context, req *transactionProto.GetRuleListRequest) (*transactionProto.GetRuleListResponse, error) {
+	rules, err := s.RuleSRV.GetRules(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	resp := &transactionProto.GetRuleListResponse{Rules: make([]*transactionProto.Rule, len(rules))}
+	for i, r := range rules {
+		resp.Rules[i] = convertToProtoRule(r)
+	}
+	return resp, nil
+}
+
+func (s *RuleServiceServer) UpdateRule(ctx context.Context, req *transactionProto.UpdateRuleRequest) (*transactionProto.GetRuleResponse, error) {
+	rule, err := s.RuleSRV.UpdateRule(ctx, models.CreateRule{
+		ID:         req.RuleId,
+		UserID:     req.UserId,
+		Name:       req.Name,
+		Priority:   int(req.Priority),
+		Enabled:    req.Enabled == nil || req.Enabled.Value,
+		Conditions: convertFromProtoConditions(req.Conditions),
+		Actions:    convertFromProtoActions(req.Actions),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &transactionProto.GetRuleResponse{Rule: convertToProtoRule(*rule)}, nil
+}
+
+func (s *RuleServiceServer) DeleteRule(ctx context.Context, req *transactionProto.DeleteRuleRequest) (*emptypb.Empty, error) {
+	if err := s.RuleSRV.DeleteRule(ctx, req.RuleId, req.UserId); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *RuleServiceServer) ReapplyRules(ctx context.Context, req *transactionProto.ReapplyRulesRequest) (*transactionProto.ReapplyRulesResponse, error) {
+	result, err := s.RuleSRV.ReapplyRules(ctx,
+		models.ReapplyRules{UserID: req.UserId, DryRun: req.DryRun, OverwriteManual: req.OverwriteManual},
+		models.CreateTimeFrame{StartDate: req.StartDate, EndDate: req.EndDate})
+	if err != nil {
+		return nil, err
+	}
+	resp := &transactionProto.ReapplyRulesResponse{
+		Scanned: int32(result.Scanned),
+		Applied: result.Applied,
+		Updated: int32(result.Updated),
+		Error:   result.Error,
+		Changes: make([]*transactionProto.RuleChange, len(result.Changes)),
+	}
+	for i, c := range result.Changes {
+		resp.Changes[i] = &transactionProto.RuleChange{
+			TxId:        c.TxID,
+			Name:        c.Name,
+			Date:        c.Date.Format(DateTimeformat),
+			OldCategory: c.OldCategory,
+			NewCategory: c.NewCategory,
+			OldTags:     c.OldTags,
+			NewTags:     c.NewTags,
+			RuleIds:     c.RuleIDs,
+		}
+	}
+	return resp, nil
+}
+
+func convertFromProtoConditions(c *transactionProto.RuleConditions) models.RuleConditions {
+	if c == nil {
+		return models.RuleConditions{}
+	}
+	conditions := models.RuleConditions{
+		NameContains: c.NameContains,
+		NamePattern:  c.NamePattern,
+		AccountID:    c.AccountId,
+	}
+	if c.MinAmount != nil {
+		conditions.MinAmount = &c.MinAmount.Value
+	}
+	if c.MaxAmount != nil {
+		conditions.MaxAmount = &c.MaxAmount.Value
+	}
+	for _, d := range c.Weekdays {
+		conditions.Weekdays = append(conditions.Weekdays, int(d))
+	}
+	return conditions
+}
+
+func convertFromProtoActions(a *transactionProto.RuleActions) models.RuleActions {
+	if a == nil {
+		return models.RuleActions{}
+	}
+	return models.RuleActions{Category: a.Category, Tags: a.Tags}
+}
+
+func convertToProtoRule(r models.Rule) *transactionProto.Rule {
+	conditions := &transactionProto.RuleConditions{
+		NameContains: r.Conditions.NameContains,
+		NamePattern:  r.Conditions.NamePattern,
+		AccountId:    r.Conditions.AccountID,
+	}
+	if r.Conditions.MinAmount != nil {
+		conditions.MinAmount = wrapperspb.Double(*r.Conditions.MinAmount)
+	}
+	if r.Conditions.MaxAmount != nil {
+		conditions.MaxAmount = wrapperspb.Double(*r.Conditions.MaxAmount)
+	}
+	for _, d := range r.Conditions.Weekdays {
+		conditions.Weekdays = append(conditions.Weekdays, int32(d))
+	}
+	return &transactionProto.Rule{
+		Id:         r.ID,
+		UserId:     r.UserID,
+		Name:       r.Name,
+		Priority:   int32(r.Priority),
+		Enabled:    r.Enabled,
+		Conditions: conditions,
+		Actions:    &transactionProto.RuleActions{Category: r.Actions.Category, Tags: r.Actions.Tags},
+		CreatedAt:  r.CreatedAt.Format(DateTimeformat),
+	}
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	transactionProto "github.com/justIGreK/MoneyKeeper-Transaction/pkg/go/transaction"
+)
+
+type SharedExpenseServiceServer struct {
+	transactionProto.UnimplementedSharedExpenseServiceServer
+	SharedSRV SharedExpenseService
+}
+
+type SharedExpenseService interface {
+	CreateGroup(ctx context.Context, create models.CreateGroup) (*models.Group, error)
+	GetGroup(ctx context.Context, groupID, userID string) (*models.Group, error)
+	AddGroupMember(ctx context.Context, add models.AddGroupMember) (*models.Group, error)
+	AddSharedExpense(ctx context.Context, expense models.CreateSharedExpense) (string, error)
+	GetSharedExpenses(ctx context.Context, groupID, userID string) ([]models.SharedExpense, error)
+	GetBalances(ctx context.Context, groupID, userID string) ([]models.Balance, []models.Debt, error)
+	SettleUp(ctx context.Context, groupID, userID string) ([]models.Settlement, error)
+}
+
+var splitTypes = map[transactionProto.SplitType]string{
+	transactionProto.SplitType_SPLIT_TYPE_EQUAL:      models.SplitEqual,
+	transactionProto.SplitType_SPLIT_TYPE_PERCENTAGE: models.SplitPercentage,
+	transactionProto.SplitType_SPLIT_TYPE_EXACT:      models.SplitExact,
+}
+
+func (s *SharedExpenseServiceServer) CreateGroup(ctx context.Context, req *transactionProto.CreateGroupRequest) (*transactionProto.GroupResponse, error) {
+	group, err := s.SharedSRV.CreateGroup(ctx, models.CreateGroup{
+		UserID:    req.UserId,
+		Name:      req.Name,
+		MemberIDs: req.MemberIds,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &transactionProto.GroupResponse{Group: convertGroupToProto(group)}, nil
+}
+
+func (s *SharedExpenseServiceServer) GetGroup(ctx context.Context, req *transactionProto.GroupRequest) (*transactionProto.GroupResponse, error) {
+	group, err := s.SharedSRV.GetGroup(ctx, req.GroupId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return &transactionProto.GroupResponse{Group: convertGroupToProto(group)}, nil
+}
+
+func (s *SharedExpenseServiceServer) AddGroupMember(ctx context.Context, req *transactionProto.AddGroupMemberRequest) (*transactionProto.GroupResponse, error) {
+	group, err := s.SharedSRV.AddGroupMember(ctx, models.AddGroupMember{
+		GroupID:  req.GroupId,
+		UserID:   req.UserId,
+		MemberID: req.MemberId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &transactionProto.GroupResponse{Group: convertGroupToProto(group)}, nil
+}
+
+func convertGroupToProto(group *models.Group) *transactionProto.Group {
+	return &transactionProto.Group{
+		Id:        group.ID,
+		Name:      group.Name,
+		OwnerId:   group.OwnerID,
+		MemberIds: group.Members,
+		CreatedAt: group.CreatedAt.Format(DateTimeformat),
+	}
+}
+
+func (s *SharedExpenseServiceServer) CreateSharedExpense(ctx context.Context, req *transactionProto.CreateSharedExpenseRequest) (*transactionProto.CreateSharedExpenseResponse, error) {
+	expense := models.CreateSharedExpense{
+		GroupID:      req.GroupId,
+		UserID:       req.UserId,
+		PayerID:      req.PayerId,
+		Name:         req.Name,
+		Total:        req.Total,
+		SplitType:    splitTypes[req.SplitType],
+		Participants: make([]models.CreateParticipant, len(req.Participants)),
+	}
+	for i, p := range req.Participants {
+		expense.Participants[i] = models.CreateParticipant{UserID: p.UserId, Value: p.Value}
+	}
+	if req.Date != nil {
+		expense.Date = &req.Date.Value
+	}
+	id, err := s.SharedSRV.AddSharedExpense(ctx, expense)
+	if err != nil {
+		return nil, err
+	}
+	return &transactionProto.CreateSharedExpenseResponse{ExpenseId: id}, nil
+}
+
+func (s *SharedExpenseServiceServer) GetSharedExpenses(ctx context.Context, req *transactionProto.GroupRequest) (*transactionProto.GetSharedExpensesResponse, error) {
+	expenses, err := s.SharedSRV.GetSharedExpenses(ctx, req.GroupId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	resp := &transactionProto.GetSharedExpensesResponse{
+		Expenses: make([]*transactionProto.SharedExpense, len(expenses)),
+	}
+	for i, e := range expenses {
+		resp.Expenses[i] = &transactionProto.SharedExpense{
+			Id:        e.ID,
+			GroupId:   e.GroupID,
+			PayerId:   e.PayerID,
+			Name:      e.Name,
+			Total:     e.Total,
+			SplitType: protoSplitType(e.SplitType),
+			Shares:    make([]*transactionProto.Share, len(e.Shares)),
+			Date:      e.Date.Format(DateTimeformat),
+		}
+		for j, share := range e.Shares {
+			resp.Expenses[i].Shares[j] = &transactionProto.Share{UserId: share.UserID, Amount: share.Amount}
+		}
+	}
+	return resp, nil
+}
+
+func (s *SharedExpenseServiceServer) GetBalances(ctx context.Context, req *transactionProto.GroupRequest) (*transactionProto.GetBalancesResponse, error) {
+	balances, debts, err := s.SharedSRV.GetBalances(ctx, req.GroupId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	resp := &transactionProto.GetBalancesResponse{
+		Balances: make([]*transactionProto.Balance, len(balances)),
+		Debts:    make([]*transactionProto.Debt, len(debts)),
+	}
+	for i, b := range balances {
+		resp.Balances[i] = &transactionProto.Balance{UserId: b.UserID, Net: b.Net}
+	}
+	for i, d := range debts {
+		resp.Debts[i] = &transactionProto.Debt{FromUserId: d.FromUserID, ToUserId: d.ToUserID, Amount: d.Amount}
+	}
+	return resp, nil
+}
+
+func (s *SharedExpenseServiceServer) SettleUp(ctx context.Context, req *transactionProto.GroupRequest) (*transactionProto.SettleUpResponse, error) {
+	settlements, err := s.SharedSRV.SettleUp(ctx, req.GroupId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	resp := &transactionProto.SettleUpResponse{
+		Settlements: make([]*transactionProto.Settlement, len(settlements)),
+	}
+	for i, st := range settlements {
+		resp.Settlements[i] = &transactionProto.Settlement{
+			Id:         st.ID,
+			GroupId:    st.GroupID,
+			FromUserId: st.FromUserID,
+			ToUserId:   st.ToUserID,
+			Amount:     st.Amount,
+			Date:       st.Date.Format(DateTimeformat),
+		}
+	}
+	return resp, nil
+}
+
+func protoSplitType(splitType string) transactionProto.SplitType {
+	for k, v := range splitTypes {
+		if v == splitType {
+			return k
+		}
+	}
+	return transactionProto.SplitType_SPLIT_TYPE_UNSPECIFIED
+}
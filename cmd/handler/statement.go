@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	transactionProto "github.com/justIGreK/MoneyKeeper-Transaction/pkg/go/transaction"
+)
+
+type StatementServiceServer struct {
+	transactionProto.UnimplementedStatementServiceServer
+	StatementSRV StatementService
+}
+
+type StatementService interface {
+	GenerateStatement(ctx context.Context, req models.GenerateStatement) (*models.Document, error)
+}
+
+func (s *StatementServiceServer) GenerateStatement(ctx context.Context, req *transactionProto.GenerateStatementRequest) (*transactionProto.GenerateStatementResponse, error) {
+	doc, err := s.StatementSRV.GenerateStatement(ctx, models.GenerateStatement{
+		UserID: req.UserId,
+		Month:  req.Month,
+		Format: req.Format,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &transactionProto.GenerateStatementResponse{
+		Name:        doc.Name,
+		ContentType: doc.ContentType,
+		Data:        doc.Data,
+	}, nil
+}
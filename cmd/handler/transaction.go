@@ -3,10 +3,12 @@ package handler
 import (
 	"context"
 	"errors"
+	"strconv"
 
 	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
 	transactionProto "github.com/justIGreK/MoneyKeeper-Transaction/pkg/go/transaction"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 type TransactionServiceServer struct {
@@ -21,6 +23,13 @@ type TransactionService interface {
 	GetTXByTimeFrame(ctx context.Context, userID string, timeframe models.CreateTimeFrame) ([]models.Transaction, error)
 	UpdateTx(ctx context.Context, updates models.UpdateTransaction) (*models.Transaction, error)
 	DeleteTx(ctx context.Context, userID, txID string) error
+	GetSpendingSummary(ctx context.Context, userID string, timeframe models.CreateTimeFrame) ([]models.CategoryTotal, error)
+	GetCashFlowStatement(ctx context.Context, req models.CashFlowRequest, timeframe models.CreateTimeFrame) (*models.CashFlowStatement, error)
+	SuggestCategory(ctx context.Context, req models.SuggestCategory) ([]models.CategorySuggestion, error)
+	FindDuplicates(ctx context.Context, req models.FindDuplicates, timeframe models.CreateTimeFrame) ([]models.DuplicateCluster, error)
+	MergeTransactions(ctx context.Context, req models.MergeTransactions) (*models.Transaction, error)
+	ListAnomalies(ctx context.Context, req models.ListAnomalies, timeframe models.CreateTimeFrame) ([]models.Transaction, error)
+	ForecastSpending(ctx context.Context, req models.ForecastSpending, timeframe models.CreateTimeFrame) (*models.SpendingForecast, error)
 }
 
 const (
@@ -30,10 +39,18 @@ const (
 
 func (s *TransactionServiceServer) CreateTransaction(ctx context.Context, req *transactionProto.CreateTransactionRequest) (*transactionProto.CreateTransactionResponse, error) {
 	tx := models.CreateTransaction{
-		Category: req.Category,
-		UserID:   req.UserId,
-		Name:     req.Name,
-		Cost:     float64(req.Cost),
+		Category:  req.Category,
+		UserID:    req.UserId,
+		Name:      req.Name,
+		Cost:      decimal32(req.Cost),
+		Splits:    convertFromProtoSplits(req.Splits),
+		AccountID: req.AccountId,
+		Tags:      req.Tags,
+		Note:      req.Note,
+		LedgerID:  req.LedgerId,
+	}
+	if req.Date != nil {
+		tx.Date = &req.Date.Value
 	}
 	id, err := s.TxSRV.AddTransaction(ctx, tx)
 	if err != nil {
@@ -56,14 +73,7 @@ func (s *TransactionServiceServer) GetTransaction(ctx context.Context, req *tran
 	}
 
 	return &transactionProto.GetTransactionResponse{
-		Transaction: &transactionProto.Transaction{
-			Id:       tx.ID,
-			UserId:   tx.UserID,
-			Category: tx.Category,
-			Name:     tx.Name,
-			Cost:     float32(tx.Cost),
-			Date:     tx.Date.Format(DateTimeformat),
-		},
+		Transaction: convertToProtoTx(*tx),
 	}, nil
 
 }
@@ -91,21 +101,72 @@ func (s *TransactionServiceServer) DeleteTransaction(ctx context.Context, req *t
 func convertToProtoTxs(txs []models.Transaction) []*transactionProto.Transaction {
 	protoTxs := make([]*transactionProto.Transaction, len(txs))
 	for i, b := range txs {
-		protoTxs[i] = &transactionProto.Transaction{
-			Id:       b.ID,
-			UserId:   b.UserID,
-			Category: b.Category,
-			Name:     b.Name,
-			Cost:     float32(b.Cost),
-			Date:     b.Date.Format(DateTimeformat),
-		}
+		protoTxs[i] = convertToProtoTx(b)
 	}
 	return protoTxs
 }
 
+func convertToProtoTx(tx models.Transaction) *transactionProto.Transaction {
+	protoTx := &transactionProto.Transaction{
+		Id:         tx.ID,
+		UserId:     tx.UserID,
+		Category:   tx.Category,
+		Name:       tx.Name,
+		Cost:       float32(tx.Cost),
+		Date:       tx.Date.Format(DateTimeformat),
+		Splits:     convertToProtoSplits(tx.Splits),
+		AccountId:  tx.AccountID,
+		Kind:       tx.Kind,
+		TransferId: tx.TransferID,
+		Tags:       tx.Tags,
+		Note:       tx.Note,
+		PayeeId:    tx.PayeeID,
+		GoalId:     tx.GoalID,
+		LedgerId:   tx.LedgerID,
+	}
+	if tx.Anomaly != nil {
+		protoTx.Anomaly = &transactionProto.Anomaly{
+			Score:   tx.Anomaly.Score,
+			Median:  tx.Anomaly.Median,
+			Mad:     tx.Anomaly.MAD,
+			Samples: int32(tx.Anomaly.Samples),
+			Reasons: tx.Anomaly.Reasons,
+			Flagged: tx.Anomaly.Flagged,
+		}
+	}
+	return protoTx
+}
+
+func convertToProtoSplits(splits []models.Split) []*transactionProto.Split {
+	protoSplits := make([]*transactionProto.Split, len(splits))
+	for i, split := range splits {
+		protoSplits[i] = &transactionProto.Split{
+			Category: split.Category,
+			Amount:   split.Amount,
+			Note:     split.Note,
+		}
+	}
+	return protoSplits
+}
+
+func convertFromProtoSplits(protoSplits []*transactionProto.Split) []models.Split {
+	if len(protoSplits) == 0 {
+		return nil
+	}
+	splits := make([]models.Split, len(protoSplits))
+	for i, split := range protoSplits {
+		splits[i] = models.Split{
+			Category: split.Category,
+			Amount:   split.Amount,
+			Note:     split.Note,
+		}
+	}
+	return splits
+}
+
 func (s *TransactionServiceServer) GetTXByTimeFrame(ctx context.Context, req *transactionProto.GetTXByTimeFrameRequest) (*transactionProto.GetTransactionListResponse, error) {
 
-	txs, err := s.TxSRV.GetTXByTimeFrame(ctx, req.UserId, models.CreateTimeFrame{req.StartDate, req.EndDate})
+	txs, err := s.TxSRV.GetTXByTimeFrame(ctx, req.UserId, models.CreateTimeFrame{StartDate: req.StartDate, EndDate: req.EndDate})
 	if err != nil {
 		return nil, err
 	}
@@ -138,25 +199,205 @@ func (s *TransactionServiceServer) UpdateTransaction(ctx context.Context, req *t
 	if req.Time != nil {
 		updates.Time = &req.Time.Value
 	}
+	if req.Splits != nil {
+		splits := convertFromProtoSplits(req.Splits.Splits)
+		updates.Splits = &splits
+	}
+	if req.AccountId != nil {
+		updates.AccountID = &req.AccountId.Value
+	}
+	if req.Note != nil {
+		updates.Note = &req.Note.Value
+	}
 	tx, err := s.TxSRV.UpdateTx(ctx, updates)
 	if err != nil {
 		return nil, err
 	}
 	return &transactionProto.GetTransactionResponse{
-		Transaction: &transactionProto.Transaction{
-			Id:       tx.ID,
-			UserId:   tx.UserID,
-			Category: tx.Category,
-			Name:     tx.Name,
-			Cost:     float32(tx.Cost),
-			Date:     tx.Date.Format(DateTimeformat),
-		},
+		Transaction: convertToProtoTx(*tx),
 	}, nil
 }
 
 func (s *TransactionServiceServer) validateUpdateTx(req *transactionProto.UpdateTransactionRequest) error {
-	if req.Name == nil && req.Cost == nil && req.Category == nil && req.Date == nil && req.Time == nil {
+	if req.Name == nil && req.Cost == nil && req.Category == nil && req.Date == nil && req.Time == nil && req.Splits == nil && req.AccountId == nil && req.Note == nil {
 		return errors.New("no new updates")
 	}
 	return nil
 }
+
+func (s *TransactionServiceServer) GetSpendingSummary(ctx context.Context, req *transactionProto.GetTXByTimeFrameRequest) (*transactionProto.GetSpendingSummaryResponse, error) {
+	totals, err := s.TxSRV.GetSpendingSummary(ctx, req.UserId, models.CreateTimeFrame{StartDate: req.StartDate, EndDate: req.EndDate})
+	if err != nil {
+		return nil, err
+	}
+	resp := &transactionProto.GetSpendingSummaryResponse{
+		Categories: make([]*transactionProto.CategoryTotal, len(totals)),
+	}
+	for i, t := range totals {
+		resp.Categories[i] = &transactionProto.CategoryTotal{
+			Category: t.Category,
+			Total:    t.Total,
+			Count:    int32(t.Count),
+		}
+		resp.Total += t.Total
+	}
+	return resp, nil
+}
+
+func (s *TransactionServiceServer) GetCashFlowStatement(ctx context.Context, req *transactionProto.GetCashFlowStatementRequest) (*transactionProto.GetCashFlowStatementResponse, error) {
+	flowReq := models.CashFlowRequest{
+		UserID:    req.UserId,
+		AccountID: req.AccountId,
+	}
+	if req.OpeningBalance != nil {
+		flowReq.OpeningBalance = &req.OpeningBalance.Value
+	}
+	statement, err := s.TxSRV.GetCashFlowStatement(ctx, flowReq, models.CreateTimeFrame{StartDate: req.StartDate, EndDate: req.EndDate})
+	if err != nil {
+		return nil, err
+	}
+	resp := &transactionProto.GetCashFlowStatementResponse{
+		AccountId:      statement.AccountID,
+		OpeningBalance: statement.OpeningBalance,
+		ClosingBalance: statement.ClosingBalance,
+		Inflow:         statement.Inflow,
+		Outflow:        statement.Outflow,
+		Entries:        make([]*transactionProto.CashFlowEntry, len(statement.Entries)),
+	}
+	for i, e := range statement.Entries {
+		resp.Entries[i] = &transactionProto.CashFlowEntry{
+			Transaction: convertToProtoTx(e.Transaction),
+			Amount:      e.Amount,
+			Balance:     e.Balance,
+		}
+	}
+	return resp, nil
+}
+
+func (s *TransactionServiceServer) SuggestCategory(ctx context.Context, req *transactionProto.SuggestCategoryRequest) (*transactionProto.SuggestCategoryResponse, error) {
+	suggestions, err := s.TxSRV.SuggestCategory(ctx, models.SuggestCategory{
+		UserID: req.UserId,
+		Name:   req.Name,
+		Cost:   req.Cost,
+		Limit:  int(req.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &transactionProto.SuggestCategoryResponse{
+		Suggestions: make([]*transactionProto.CategorySuggestion, len(suggestions)),
+	}
+	for i, sg := range suggestions {
+		resp.Suggestions[i] = &transactionProto.CategorySuggestion{Category: sg.Category, Confidence: sg.Confidence}
+	}
+	return resp, nil
+}
+
+func (s *TransactionServiceServer) FindDuplicates(ctx context.Context, req *transactionProto.FindDuplicatesRequest) (*transactionProto.FindDuplicatesResponse, error) {
+	clusters, err := s.TxSRV.FindDuplicates(ctx, models.FindDuplicates{
+		UserID:          req.UserId,
+		AmountTolerance: req.AmountTolerance,
+		WindowMinutes:   int(req.WindowMinutes),
+		NameSimilarity:  req.NameSimilarity,
+	}, models.CreateTimeFrame{StartDate: req.StartDate, EndDate: req.EndDate})
+	if err != nil {
+		return nil, err
+	}
+	resp := &transactionProto.FindDuplicatesResponse{
+		Clusters: make([]*transactionProto.DuplicateCluster, len(clusters)),
+	}
+	for i, c := range clusters {
+		resp.Clusters[i] = &transactionProto.DuplicateCluster{
+			Transactions: convertToProtoTxs(c.Transactions),
+			Similarity:   c.Similarity,
+		}
+	}
+	return resp, nil
+}
+
+func (s *TransactionServiceServer) MergeTransactions(ctx context.Context, req *transactionProto.MergeTransactionsRequest) (*transactionProto.GetTransactionResponse, error) {
+	tx, err := s.TxSRV.MergeTransactions(ctx, models.MergeTransactions{
+		UserID:       req.UserId,
+		KeepID:       req.KeepId,
+		MergeIDs:     req.MergeIds,
+		CombineTags:  req.CombineTags,
+		CombineNotes: req.CombineNotes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &transactionProto.GetTransactionResponse{
+		Transaction: convertToProtoTx(*tx),
+	}, nil
+}
+
+func (s *TransactionServiceServer) ListAnomalies(ctx context.Context, req *transactionProto.ListAnomaliesRequest) (*transactionProto.GetTransactionListResponse, error) {
+	txs, err := s.TxSRV.ListAnomalies(ctx, models.ListAnomalies{
+		UserID:   req.UserId,
+		MinScore: req.MinScore,
+		Limit:    int(req.Limit),
+	}, models.CreateTimeFrame{StartDate: req.StartDate, EndDate: req.EndDate})
+	if err != nil {
+		return nil, err
+	}
+	return &transactionProto.GetTransactionListResponse{
+		Transactions: convertToProtoTxs(txs),
+	}, nil
+}
+
+func (s *TransactionServiceServer) ForecastSpending(ctx context.Context, req *transactionProto.ForecastSpendingRequest) (*transactionProto.ForecastSpendingResponse, error) {
+	result, err := s.TxSRV.ForecastSpending(ctx, models.ForecastSpending{
+		UserID:        req.UserId,
+		AsOf:          req.AsOf,
+		LookbackWeeks: int(req.LookbackWeeks),
+	}, models.CreateTimeFrame{StartDate: req.StartDate, EndDate: req.EndDate})
+	if err != nil {
+		return nil, err
+	}
+	resp := &transactionProto.ForecastSpendingResponse{
+		StartDate:  result.StartDate.Format(Dateformat),
+		EndDate:    result.EndDate.Format(Dateformat),
+		AsOf:       result.AsOf.Format(DateTimeformat),
+		Categories: make([]*transactionProto.CategoryForecast, len(result.Categories)),
+		Recurring:  make([]*transactionProto.RecurringItem, len(result.Recurring)),
+		Total:      convertToProtoForecast(result.Total),
+	}
+	for i, c := range result.Categories {
+		resp.Categories[i] = convertToProtoForecast(c)
+	}
+	for i, r := range result.Recurring {
+		resp.Recurring[i] = &transactionProto.RecurringItem{
+			Name:     r.Name,
+			Category: r.Category,
+			Amount:   r.Amount,
+			Due:      r.Due.Format(DateTimeformat),
+		}
+	}
+	return resp, nil
+}
+
+func convertToProtoForecast(f models.CategoryForecast) *transactionProto.CategoryForecast {
+	forecast := &transactionProto.CategoryForecast{
+		Category:  f.Category,
+		Spent:     f.Spent,
+		Recurring: f.Recurring,
+		Projected: f.Projected,
+		Low:       f.Low,
+		High:      f.High,
+	}
+	if f.Budget != nil {
+		forecast.Budget = wrapperspb.Double(*f.Budget)
+		forecast.OverBudget = f.Projected > *f.Budget
+	}
+	return forecast
+}
+
+// decimal32 widens a float32 to the float64 with the same shortest decimal
+// form, so 12.34 stays 12.34 instead of 12.3400001525878906.
+func decimal32(v float32) float64 {
+	f, err := strconv.ParseFloat(strconv.FormatFloat(float64(v), 'f', -1, 32), 64)
+	if err != nil {
+		return float64(v)
+	}
+	return f
+}
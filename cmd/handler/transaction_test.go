@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+func TestDecimal32(t *testing.T) {
+	for _, v := range []float32{0, 12.34, 0.1, 99999.99, 1e9} {
+		want := v
+		if got := decimal32(v); float32(got) != want {
+			t.Errorf("decimal32(%v) = %v does not round-trip", v, got)
+		}
+	}
+	if got := decimal32(12.34); got != 12.34 {
+		t.Errorf("decimal32(12.34) = %v", got)
+	}
+}
+
+func TestSplitConversionRoundTrip(t *testing.T) {
+	if got := convertFromProtoSplits(nil); got != nil {
+		t.Fatalf("convertFromProtoSplits(nil) = %v", got)
+	}
+	splits := []models.Split{
+		{Category: "food", Amount: 12.5, Note: "lunch"},
+		{Category: "home", Amount: 7.49},
+	}
+	got := convertFromProtoSplits(convertToProtoSplits(splits))
+	if !reflect.DeepEqual(got, splits) {
+		t.Fatalf("round trip = %+v, want %+v", got, splits)
+	}
+}
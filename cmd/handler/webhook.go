@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	transactionProto "github.com/justIGreK/MoneyKeeper-Transaction/pkg/go/transaction"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type WebhookServiceServer struct {
+	transactionProto.UnimplementedWebhookServiceServer
+	WebhookSRV WebhookService
+}
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, create models.CreateWebhook) (*models.Webhook, error)
+	GetWebhooks(ctx context.Context, userID string) ([]models.Webhook, error)
+	DeleteWebhook(ctx context.Context, webhookID, userID string) error
+	GetDeliveries(ctx context.Context, filter models.DeliveryFilter) ([]models.WebhookDelivery, error)
+	RetryDelivery(ctx context.Context, deliveryID, userID string) error
+}
+
+func (s *WebhookServiceServer) CreateWebhook(ctx context.Context, req *transactionProto.CreateWebhookRequest) (*transactionProto.CreateWebhookResponse, error) {
+	webhook, err := s.WebhookSRV.CreateWebhook(ctx, models.CreateWebhook{
+		UserID:     req.UserId,
+		URL:        req.Url,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &transactionProto.CreateWebhookResponse{
+		Webhook: convertToProtoWebhook(*webhook),
+		Secret:  webhook.Secret,
+	}, nil
+}
+
+func (s *WebhookServiceServer) GetWebhookList(ctx context.Context, req *transactionProto.GetWebhookListRequest) (*transactionProto.GetWebhookListResponse, error) {
+	webhooks, err := s.WebhookSRV.GetWebhooks(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	resp := &transactionProto.GetWebhookListResponse{
+		Webhooks: make([]*transactionProto.Webhook, len(webhooks)),
+	}
+	for i, w := range webhooks {
+		resp.Webhooks[i] = convertToProtoWebhook(w)
+	}
+	return resp, nil
+}
+
+func (s *WebhookServiceServer) DeleteWebhook(ctx context.Context, req *transactionProto.DeleteWebhookRequest) (*emptypb.Empty, error) {
+	if err := s.WebhookSRV.DeleteWebhook(ctx, req.WebhookId, req.UserId); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *WebhookServiceServer) GetDeliveryLog(ctx context.Context, req *transactionProto.GetDeliveryLogRequest) (*transactionProto.GetDeliveryLogResponse, error) {
+	deliveries, err := s.WebhookSRV.GetDeliveries(ctx, models.DeliveryFilter{
+		UserID:    req.UserId,
+		WebhookID: req.WebhookId,
+		Status:    req.Status,
+		Limit:     int(req.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &transactionProto.GetDeliveryLogResponse{
+		Deliveries: make([]*transactionProto.Delivery, len(deliveries)),
+	}
+	for i, d := range deliveries {
+		resp.Deliveries[i] = &transactionProto.Delivery{
+			Id:           d.ID,
+			WebhookId:    d.WebhookID,
+			EventId:      d.EventID,
+			EventType:    d.EventType,
+			Status:       d.Status,
+			Attempts:     int32(d.Attempts),
+			ResponseCode: int32(d.ResponseCode),
+			LastError:    d.LastError,
+			CreatedAt:    d.CreatedAt.Format(DateTimeformat),
+		}
+		if d.Status == models.DeliveryPending {
+			resp.Deliveries[i].NextAttemptAt = d.NextAttemptAt.Format(DateTimeformat)
+		}
+		if d.DeliveredAt != nil {
+			resp.Deliveries[i].DeliveredAt = d.DeliveredAt.Format(DateTimeformat)
+		}
+	}
+	return resp, nil
+}
+
+func (s *WebhookServiceServer) RetryDelivery(ctx context.Context, req *transactionProto.RetryDeliveryRequest) (*emptypb.Empty, error) {
+	if err := s.WebhookSRV.RetryDelivery(ctx, req.DeliveryId, req.UserId); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func convertToProtoWebhook(w models.Webhook) *transactionProto.Webhook {
+	return &transactionProto.Webhook{
+		Id:         w.ID,
+		UserId:     w.UserID,
+		Url:        w.URL,
+		EventTypes: w.EventTypes,
+		CreatedAt:  w.CreatedAt.Format(DateTimeformat),
+	}
+}
@@ -2,39 +2,242 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net"
+	"net/http"
 
 	"github.com/justIGreK/MoneyKeeper-Transaction/cmd/handler"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/attachment"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/auth"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/budget"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/config"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/events"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/gateway"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/metrics"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/orphan"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/payee"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/ratelimit"
 	"github.com/justIGreK/MoneyKeeper-Transaction/internal/repository"
 	"github.com/justIGreK/MoneyKeeper-Transaction/internal/service"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/statement"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/tracing"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/webhook"
 	"github.com/justIGreK/MoneyKeeper-Transaction/pkg/client"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 )
 
 func main() {
 	ctx := context.Background()
+	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+	m := metrics.New(cfg.Metrics.Categories)
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config(cfg.Tracing))
+	if err != nil {
+		log.Fatalf("failed to configure tracing: %v", err)
+	}
+	defer shutdownTracing(ctx)
+	// log.Fatalf skips deferred calls, so buffered spans are flushed first.
+	fatalf := func(format string, args ...interface{}) {
+		shutdownTracing(ctx)
+		log.Fatalf(format, args...)
+	}
 
-	user, err := client.NewUserClient("localhost:50052")
+	userClient, err := client.NewUserClient("localhost:50052", grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
 	if err != nil {
-		log.Fatal(err)
+		fatalf("failed to create user client: %v", err)
 	}
+	user := client.NewCachedUserClient(m.InstrumentUserClient(userClient), client.DefaultCacheConfig())
+	m.RegisterUserCache(user.Stats)
 	db := repository.CreateMongoClient(ctx)
-	txRepo := repository.NewTransactionRepository(db)
-	txSRV := service.NewTransactionService(txRepo, user)
+	if err := repository.EnsureIndexes(ctx, db); err != nil {
+		fatalf("failed to create MongoDB indexes: %v", err)
+	}
+	txRepo := repository.NewInstrumentedTransactionRepo(repository.NewTransactionRepository(db), m)
+	access := auth.NewPolicy(cfg.Auth.Enabled, cfg.Auth.PrivilegedRoles)
+	accountRepo := repository.NewAccountRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	ruleRepo := repository.NewRuleRepository(db)
+	categoryModelRepo := repository.NewCategoryModelRepository(db)
+	payeeSRV := service.NewPayeeService(repository.NewPayeeRepository(db), txRepo,
+		payee.NewNormalizer(cfg.Payees.Prefixes, cfg.Payees.CitySuffixes), user, access)
+	var budgets service.BudgetProvider = budget.None{}
+	if cfg.Budgets.File != "" {
+		budgets, err = budget.LoadFile(cfg.Budgets.File)
+		if err != nil {
+			fatalf("failed to load budgets: %v", err)
+		}
+	}
+	quota := service.TransactionQuota{PerDay: cfg.Quotas.TransactionsPerDay}
+	ledgerSRV := service.NewLedgerService(repository.NewLedgerRepository(db), txRepo, user, access)
+	txSRV := service.NewTransactionService(txRepo, accountRepo, attachmentRepo, ruleRepo, categoryModelRepo, payeeSRV, ledgerSRV, budgets, user, access,
+		service.AnomalyConfig(cfg.Anomalies), quota)
+	ruleSRV := service.NewRuleService(ruleRepo, txRepo, categoryModelRepo, user, access)
+	accountSRV := service.NewAccountService(accountRepo, txRepo, user, access, quota)
+	goalSRV := service.NewGoalService(repository.NewGoalRepository(db), txRepo, accountRepo, user, access, quota)
+	statementSRV := service.NewStatementService(txRepo, user, access)
+	privacySRV := service.NewPrivacyService(repository.NewPrivacyRepository(db), txRepo, attachmentRepo, budgets, access)
+	orphanSRV := service.NewOrphanService(txRepo, repository.NewOrphanSightingRepository(db), privacySRV, orphan.NewFileArchiver(cfg.Orphans.ArchiveDir),
+		user, access, cfg.Orphans.Policy, cfg.Orphans.ConfirmAfter)
+	sharedSRV := service.NewSharedExpenseService(repository.NewSharedExpenseRepository(db), user, access)
+	attachmentSRV := service.NewAttachmentService(attachmentRepo, txRepo, user, access, service.AttachmentLimits{
+		MaxSize:      cfg.Attachments.MaxSize,
+		ContentTypes: cfg.Attachments.ContentTypes,
+		Quota:        cfg.Attachments.Quota,
+	})
+	publisher, err := events.New(events.Config{
+		Publisher:    cfg.Events.Publisher,
+		File:         cfg.Events.File,
+		NATSURL:      cfg.Events.NATSURL,
+		NATSSubject:  cfg.Events.NATSSubject,
+		KafkaBrokers: cfg.Events.KafkaBrokers,
+		KafkaTopic:   cfg.Events.KafkaTopic,
+	})
+	if err != nil {
+		fatalf("failed to configure event publisher: %v", err)
+	}
+	webhookRepo := repository.NewWebhookRepository(db)
+	var webhookSecrets service.WebhookSecrets
+	if cfg.Webhooks.SecretKey != "" {
+		secrets, err := webhook.NewSecretBox(cfg.Webhooks.SecretKey)
+		if err != nil {
+			fatalf("failed to configure webhooks: %v", err)
+		}
+		webhookSecrets = secrets
+		go webhook.NewWorker(webhookRepo, secrets, webhook.WorkerConfig{
+			Interval:    cfg.Webhooks.Interval,
+			BatchSize:   cfg.Webhooks.BatchSize,
+			Timeout:     cfg.Webhooks.Timeout,
+			MaxAttempts: cfg.Webhooks.MaxAttempts,
+			BaseBackoff: cfg.Webhooks.BaseBackoff,
+			MaxBackoff:  cfg.Webhooks.MaxBackoff,
+		}).Run(ctx)
+	} else {
+		log.Println("WEBHOOK_SECRET_KEY is not set, webhooks are disabled")
+	}
+	webhookSRV := service.NewWebhookService(webhookRepo, webhookSecrets, webhook.Guard{}, user, access)
+	publishers := []events.EventPublisher{webhook.NewDispatcher(webhookRepo)}
+	if publisher != nil {
+		publishers = append(publishers, publisher)
+	}
+	relayPublisher := events.Multi(publishers...)
+	defer relayPublisher.Close()
+	// The relay runs with EVENTS_PUBLISHER=none too: it still feeds webhooks
+	// and trims published events, so the outbox does not grow forever.
+	relay := events.NewRelay(repository.NewOutboxRepository(db), relayPublisher, events.RelayConfig{
+		Interval:    cfg.Events.RelayInterval,
+		BatchSize:   cfg.Events.BatchSize,
+		Retention:   cfg.Events.Retention,
+		MaxAttempts: cfg.Events.MaxAttempts,
+		BaseBackoff: cfg.Events.BaseBackoff,
+		MaxBackoff:  cfg.Events.MaxBackoff,
+	})
+	go relay.Run(ctx)
+	go attachment.NewSweeper(attachmentSRV, attachment.SweeperConfig{
+		Interval:  cfg.Attachments.SweepInterval,
+		Grace:     cfg.Attachments.SweepGrace,
+		BatchSize: cfg.Attachments.SweepBatchSize,
+	}).Run(ctx)
+	if cfg.Statements.SchedulerEnabled {
+		go statement.NewScheduler(statementSRV, repository.NewStatementRepository(db), statement.NewFileSink(cfg.Statements.Dir),
+			statement.SchedulerConfig{
+				Interval: cfg.Statements.Interval,
+				Format:   cfg.Statements.Format,
+				Day:      cfg.Statements.Day,
+			}).Run(ctx)
+	}
+	if cfg.Orphans.ReconcileEnabled {
+		go orphan.NewJob(orphanSRV, orphan.JobConfig{
+			Interval: cfg.Orphans.Interval,
+			DryRun:   cfg.Orphans.DryRun,
+			Policy:   cfg.Orphans.Policy,
+		}).Run(ctx)
+	}
 	lis, err := net.Listen("tcp", ":50053")
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		fatalf("failed to listen: %v", err)
 	}
-	grpcServer := grpc.NewServer()
+	unary := []grpc.UnaryServerInterceptor{m.UnaryServerInterceptor()}
+	stream := []grpc.StreamServerInterceptor{m.StreamServerInterceptor()}
+	if cfg.Auth.Enabled {
+		authenticator, err := newAuthenticator(cfg.Auth)
+		if err != nil {
+			fatalf("failed to configure auth: %v", err)
+		}
+		unary = append(unary, authenticator.UnaryServerInterceptor())
+		stream = append(stream, authenticator.StreamServerInterceptor())
+	} else {
+		log.Println("authentication is disabled, any caller may act as any user; privileged RPCs are refused")
+	}
+	if cfg.RateLimit.Enabled {
+		methods, err := ratelimit.ParseMethods(cfg.RateLimit.Methods)
+		if err != nil {
+			fatalf("failed to configure rate limits: %v", err)
+		}
+		proxies, err := ratelimit.ParseTrustedProxies(cfg.RateLimit.TrustedProxies)
+		if err != nil {
+			fatalf("failed to configure rate limits: %v", err)
+		}
+		limiter := ratelimit.New(ratelimit.Config{
+			Default:        ratelimit.Limit{Rate: cfg.RateLimit.Rate, Burst: cfg.RateLimit.Burst},
+			Methods:        methods,
+			IdleTTL:        cfg.RateLimit.IdleTTL,
+			MaxBuckets:     cfg.RateLimit.MaxBuckets,
+			TrustedProxies: proxies,
+		})
+		unary = append(unary, limiter.UnaryServerInterceptor())
+		stream = append(stream, limiter.StreamServerInterceptor())
+	}
+	grpcServer := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	)
 
-	handler := handler.NewHandler(grpcServer, txSRV)
+	handler := handler.NewHandler(grpcServer, txSRV, sharedSRV, accountSRV, attachmentSRV, webhookSRV, ruleSRV, payeeSRV, goalSRV, statementSRV, privacySRV, orphanSRV, ledgerSRV)
 	handler.RegisterServices()
 	reflection.Register(grpcServer)
 
+	go func() {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", m.Handler())
+		log.Printf("Starting metrics server on %s", cfg.MetricsAddr)
+		if err := http.ListenAndServe(cfg.MetricsAddr, mux); err != nil {
+			fatalf("failed to serve metrics: %v", err)
+		}
+	}()
+
+	gw, err := gateway.New(ctx, gateway.Config{GRPCAddr: "localhost:50053", CORS: gateway.CORSConfig(cfg.CORS)})
+	if err != nil {
+		fatalf("failed to configure gateway: %v", err)
+	}
+	go func() {
+		log.Printf("Starting REST gateway on %s", cfg.GatewayAddr)
+		if err := http.ListenAndServe(cfg.GatewayAddr, gw); err != nil {
+			fatalf("failed to serve gateway: %v", err)
+		}
+	}()
+
 	log.Printf("Starting gRPC server on :50053")
 	if err := grpcServer.Serve(lis); err != nil {
-		log.Fatalf("failed to serve: %v", err)
+		fatalf("failed to serve: %v", err)
+	}
+}
+
+func newAuthenticator(cfg config.AuthConfig) (*auth.Authenticator, error) {
+	var keys *auth.KeySet
+	var err error
+	if cfg.JWKSFile != "" {
+		keys, err = auth.LoadJWKSFile(cfg.JWKSFile)
+	} else {
+		keys, err = auth.NewHMACKeySet(cfg.HMACSecret)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%v (set AUTH_HMAC_SECRET or AUTH_JWKS_FILE, or AUTH_DISABLED=true for local development)", err)
 	}
+	return auth.NewAuthenticator(keys, cfg.Issuer, cfg.Audience), nil
 }
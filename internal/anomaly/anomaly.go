@@ -0,0 +1,90 @@
+package anomaly
+
+import (
+	"math"
+	"sort"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+const (
+	ReasonCategoryOutlier = "category_outlier"
+	ReasonNewPayee        = "new_payee"
+
+	// madScale makes the MAD a consistent estimator of the standard
+	// deviation, so scores read like z-scores.
+	madScale = 1.4826
+)
+
+type Stats struct {
+	Median  float64
+	MAD     float64
+	Samples int
+}
+
+func Compute(values []float64) Stats {
+	if len(values) == 0 {
+		return Stats{}
+	}
+	median := medianOf(values)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - median)
+	}
+	return Stats{Median: median, MAD: medianOf(deviations), Samples: len(values)}
+}
+
+func medianOf(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2
+}
+
+// Score is the robust z-score of x. When most history has the same cost
+// the MAD is zero, so the scale never drops below a tenth of the median.
+func (s Stats) Score(x float64) float64 {
+	scale := math.Max(madScale*s.MAD, math.Max(0.1*s.Median, 0.01))
+	return (x - s.Median) / scale
+}
+
+type Detector struct {
+	Threshold      float64
+	MinSamples     int
+	NewPayeeFactor float64
+}
+
+// Evaluate scores cost against the user's history in its category and
+// overall. It returns nil when there is too little history to judge.
+// Only costs above the norm are flagged; unusually small ones are not.
+func (d Detector) Evaluate(cost float64, category, overall Stats, newPayee bool) *models.Anomaly {
+	var result *models.Anomaly
+	if category.Samples >= d.MinSamples {
+		result = &models.Anomaly{
+			Score:   category.Score(cost),
+			Median:  category.Median,
+			MAD:     category.MAD,
+			Samples: category.Samples,
+		}
+		if result.Score >= d.Threshold {
+			result.Reasons = append(result.Reasons, ReasonCategoryOutlier)
+		}
+	}
+	if newPayee && overall.Samples >= d.MinSamples && cost >= d.NewPayeeFactor*overall.Median {
+		score := overall.Score(cost)
+		if result == nil {
+			result = &models.Anomaly{Median: overall.Median, MAD: overall.MAD, Samples: overall.Samples, Score: score}
+		}
+		if score >= d.Threshold {
+			result.Reasons = append(result.Reasons, ReasonNewPayee)
+			result.Score = math.Max(result.Score, score)
+		}
+	}
+	if result != nil {
+		result.Flagged = len(result.Reasons) > 0
+	}
+	return result
+}
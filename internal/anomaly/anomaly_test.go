@@ -0,0 +1,77 @@
+package anomaly
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		values []float64
+		want   Stats
+	}{
+		{values: nil, want: Stats{}},
+		{values: []float64{5, 1, 3}, want: Stats{Median: 3, MAD: 2, Samples: 3}},
+		{values: []float64{10, 12, 14, 100}, want: Stats{Median: 13, MAD: 2, Samples: 4}},
+	}
+	for _, tt := range tests {
+		if got := Compute(tt.values); got != tt.want {
+			t.Errorf("Compute(%v) = %+v, want %+v", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	s := Stats{Median: 10, MAD: 2, Samples: 10}
+	if got, want := s.Score(10+3*madScale*2), 3.0; math.Abs(got-want) > 1e-9 {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	// constant history has no MAD; a tenth of the median is the scale.
+	flat := Stats{Median: 50, Samples: 10}
+	if got := flat.Score(60); got != 2 {
+		t.Errorf("flat history: got %v, want 2", got)
+	}
+	if got := (Stats{}).Score(1); got != 100 {
+		t.Errorf("zero history: got %v, want 100", got)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	d := Detector{Threshold: 3.5, MinSamples: 5, NewPayeeFactor: 3}
+	groceries := Stats{Median: 40, MAD: 5, Samples: 20}
+	overall := Stats{Median: 20, MAD: 10, Samples: 100}
+	tests := []struct {
+		name        string
+		cost        float64
+		category    Stats
+		newPayee    bool
+		wantNil     bool
+		wantReasons []string
+	}{
+		{name: "normal cost", cost: 45, category: groceries},
+		{name: "category outlier", cost: 200, category: groceries, wantReasons: []string{ReasonCategoryOutlier}},
+		{name: "small costs are never flagged", cost: 0, category: groceries},
+		{name: "too little history", cost: 1000, category: Stats{Median: 40, Samples: 4}, wantNil: true},
+		{name: "new payee far above the overall median", cost: 200, category: Stats{Samples: 1}, newPayee: true, wantReasons: []string{ReasonNewPayee}},
+		{name: "new payee near the overall median", cost: 50, category: Stats{Samples: 1}, newPayee: true, wantNil: true},
+		{name: "both", cost: 300, category: groceries, newPayee: true, wantReasons: []string{ReasonCategoryOutlier, ReasonNewPayee}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := d.Evaluate(tt.cost, tt.category, overall, tt.newPayee)
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("got %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("got nil")
+			}
+			if !reflect.DeepEqual(got.Reasons, tt.wantReasons) || got.Flagged != (len(tt.wantReasons) > 0) {
+				t.Errorf("got %+v, want reasons %v", got, tt.wantReasons)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package attachment
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+type Cleaner interface {
+	SweepOrphanedAttachments(ctx context.Context, uploadedBefore time.Time, limit int) (int, error)
+}
+
+type SweeperConfig struct {
+	Interval time.Duration
+	// Grace skips attachments uploaded more recently, leaving uploads racing
+	// a transaction's deletion to the next sweep.
+	Grace     time.Duration
+	BatchSize int
+}
+
+// Sweeper periodically deletes attachments whose transaction was deleted
+// without them.
+type Sweeper struct {
+	cleaner Cleaner
+	cfg     SweeperConfig
+}
+
+func NewSweeper(cleaner Cleaner, cfg SweeperConfig) *Sweeper {
+	return &Sweeper{cleaner: cleaner, cfg: cfg}
+}
+
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		s.runOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce sweeps batch after batch until one comes back short.
+func (s *Sweeper) runOnce(ctx context.Context) {
+	before := time.Now().UTC().Add(-s.cfg.Grace)
+	total := 0
+	for ctx.Err() == nil {
+		deleted, err := s.cleaner.SweepOrphanedAttachments(ctx, before, s.cfg.BatchSize)
+		total += deleted
+		if err != nil {
+			log.Printf("attachment sweep failed: %v", err)
+			break
+		}
+		if deleted < s.cfg.BatchSize {
+			break
+		}
+	}
+	if total > 0 {
+		log.Printf("attachment sweep: deleted %d orphaned attachments", total)
+	}
+}
@@ -0,0 +1,52 @@
+package attachment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeCleaner struct {
+	orphans int
+	calls   int
+	err     error
+	before  time.Time
+}
+
+func (c *fakeCleaner) SweepOrphanedAttachments(_ context.Context, uploadedBefore time.Time, limit int) (int, error) {
+	c.calls++
+	c.before = uploadedBefore
+	if c.err != nil {
+		return 0, c.err
+	}
+	n := min(limit, c.orphans)
+	c.orphans -= n
+	return n, nil
+}
+
+func TestSweeperRunOnce(t *testing.T) {
+	tests := []struct {
+		name      string
+		orphans   int
+		err       error
+		wantCalls int
+	}{
+		{name: "nothing to sweep", wantCalls: 1},
+		{name: "partial batch", orphans: 3, wantCalls: 1},
+		{name: "full batches until a short one", orphans: 10, wantCalls: 3},
+		{name: "stops on error", orphans: 10, err: errors.New("boom"), wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleaner := &fakeCleaner{orphans: tt.orphans, err: tt.err}
+			NewSweeper(cleaner, SweeperConfig{Grace: time.Hour, BatchSize: 5}).runOnce(context.Background())
+			if cleaner.calls != tt.wantCalls {
+				t.Errorf("swept %d batches, want %d", cleaner.calls, tt.wantCalls)
+			}
+			if age := time.Since(cleaner.before); age < time.Hour || age > time.Hour+time.Minute {
+				t.Errorf("swept attachments uploaded before %v, want an hour ago", cleaner.before)
+			}
+		})
+	}
+}
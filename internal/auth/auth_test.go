@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const secret = "test-secret"
+
+func sign(t *testing.T, claims Claims, kid string) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func withToken(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func validClaims(subject string) Claims {
+	return Claims{RegisteredClaims: jwt.RegisteredClaims{
+		Subject:   subject,
+		Issuer:    "moneykeeper",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}}
+}
+
+func TestAuthenticateHMAC(t *testing.T) {
+	keys, err := NewHMACKeySet(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := NewAuthenticator(keys, "moneykeeper", "")
+	expired := validClaims("u1")
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+	wrongIssuer := validClaims("u1")
+	wrongIssuer.Issuer = "someone"
+	tests := []struct {
+		name string
+		ctx  context.Context
+		ok   bool
+	}{
+		{"valid", withToken(sign(t, validClaims("u1"), "")), true},
+		{"valid with kid", withToken(sign(t, validClaims("u1"), "key-2024")), true},
+		{"no header", context.Background(), false},
+		{"expired", withToken(sign(t, expired, "")), false},
+		{"wrong issuer", withToken(sign(t, wrongIssuer, "")), false},
+		{"no subject", withToken(sign(t, validClaims(""), "")), false},
+		{"garbage", withToken("not-a-token"), false},
+		{"basic scheme", metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Basic abc")), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := a.Authenticate(tt.ctx)
+			if !tt.ok {
+				if status.Code(err) != codes.Unauthenticated {
+					t.Fatalf("err = %v, want Unauthenticated", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			claims, ok := FromContext(ctx)
+			if !ok || claims.Subject != "u1" {
+				t.Fatalf("claims = %+v", claims)
+			}
+		})
+	}
+}
+
+func TestLoadJWKSFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	jwks := `{"keys":[{"kty":"oct","kid":"k1","k":"dGVzdC1zZWNyZXQ"},{"kty":"oct","kid":"enc","use":"enc","k":"eA"}]}`
+	if err := os.WriteFile(path, []byte(jwks), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := LoadJWKSFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := NewAuthenticator(keys, "", "")
+	if _, err := a.Authenticate(withToken(sign(t, validClaims("u1"), "k1"))); err != nil {
+		t.Fatalf("known kid: %v", err)
+	}
+	if _, err := a.Authenticate(withToken(sign(t, validClaims("u1"), "other"))); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("unknown kid: %v", err)
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	user := NewContext(context.Background(), &Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "u1"}})
+	admin := NewContext(context.Background(), &Claims{Roles: []string{"admin"}, RegisteredClaims: jwt.RegisteredClaims{Subject: "a1"}})
+	anonymous := context.Background()
+
+	required := NewPolicy(true, []string{"admin"})
+	optional := NewPolicy(false, []string{"admin"})
+	tests := []struct {
+		name   string
+		policy *Policy
+		ctx    context.Context
+		userID string
+		want   codes.Code
+	}{
+		{"own data", required, user, "u1", codes.OK},
+		{"someone else's data", required, user, "u2", codes.PermissionDenied},
+		{"privileged", required, admin, "u2", codes.OK},
+		{"anonymous with auth required", required, anonymous, "u1", codes.Unauthenticated},
+		{"anonymous with auth disabled", optional, anonymous, "u1", codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(tt.policy.CheckAccess(tt.ctx, tt.userID)); got != tt.want {
+				t.Fatalf("CheckAccess = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPrivileged(t *testing.T) {
+	user := NewContext(context.Background(), &Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "u1"}})
+	admin := NewContext(context.Background(), &Claims{Roles: []string{"admin"}, RegisteredClaims: jwt.RegisteredClaims{Subject: "a1"}})
+	anonymous := context.Background()
+
+	required := NewPolicy(true, []string{"admin"})
+	optional := NewPolicy(false, []string{"admin"})
+	tests := []struct {
+		name   string
+		policy *Policy
+		ctx    context.Context
+		want   codes.Code
+	}{
+		{"admin", required, admin, codes.OK},
+		{"user", required, user, codes.PermissionDenied},
+		{"anonymous with auth required", required, anonymous, codes.Unauthenticated},
+		{"anonymous with auth disabled", optional, anonymous, codes.Unauthenticated},
+		{"user with auth disabled", optional, user, codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(tt.policy.CheckPrivileged(tt.ctx)); got != tt.want {
+				t.Fatalf("CheckPrivileged = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
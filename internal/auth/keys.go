@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type KeySet struct {
+	keys map[string]interface{}
+	// shared, when set, verifies tokens whatever kid they carry.
+	shared  interface{}
+	methods []string
+}
+
+func NewHMACKeySet(secret string) (*KeySet, error) {
+	if secret == "" {
+		return nil, errors.New("hmac secret is empty")
+	}
+	return &KeySet{
+		keys:    map[string]interface{}{},
+		shared:  []byte(secret),
+		methods: []string{"HS256", "HS384", "HS512"},
+	}, nil
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+func LoadJWKSFile(path string) (*KeySet, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("invalid jwks: %v", err)
+	}
+	set := &KeySet{keys: make(map[string]interface{})}
+	methods := map[string]bool{}
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, algs, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwk %q: %v", k.Kid, err)
+		}
+		set.keys[k.Kid] = key
+		for _, alg := range algs {
+			if !methods[alg] {
+				methods[alg] = true
+				set.methods = append(set.methods, alg)
+			}
+		}
+	}
+	if len(set.keys) == 0 {
+		return nil, errors.New("jwks contains no signing keys")
+	}
+	return set, nil
+}
+
+func (k jwk) publicKey() (interface{}, []string, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}, nil
+	case "EC":
+		var curve elliptic.Curve
+		var alg string
+		switch k.Crv {
+		case "P-256":
+			curve, alg = elliptic.P256(), "ES256"
+		case "P-384":
+			curve, alg = elliptic.P384(), "ES384"
+		case "P-521":
+			curve, alg = elliptic.P521(), "ES512"
+		default:
+			return nil, nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, []string{alg}, nil
+	case "oct":
+		secret, err := base64.RawURLEncoding.DecodeString(k.K)
+		if err != nil {
+			return nil, nil, err
+		}
+		return secret, []string{"HS256", "HS384", "HS512"}, nil
+	}
+	return nil, nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func (s *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	if s.shared != nil {
+		return s.shared, nil
+	}
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
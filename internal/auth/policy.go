@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Policy struct {
+	Required        bool
+	PrivilegedRoles []string
+}
+
+func NewPolicy(required bool, privilegedRoles []string) *Policy {
+	return &Policy{Required: required, PrivilegedRoles: privilegedRoles}
+}
+
+func (p *Policy) CheckAccess(ctx context.Context, userID string) error {
+	claims, ok := FromContext(ctx)
+	if !ok {
+		if p.Required {
+			return status.Error(codes.Unauthenticated, "authentication required")
+		}
+		return nil
+	}
+	if claims.Subject == userID || claims.HasAnyRole(p.PrivilegedRoles...) {
+		return nil
+	}
+	return status.Error(codes.PermissionDenied, "access to another user's data is denied")
+}
+
+// CheckPrivileged allows only callers holding one of the privileged roles.
+// It fails closed: without verified claims nobody is privileged, even when
+// authentication is not required.
+func (p *Policy) CheckPrivileged(ctx context.Context) error {
+	claims, ok := FromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "authentication required")
+	}
+	if claims.HasAnyRole(p.PrivilegedRoles...) {
+		return nil
+	}
+	return status.Error(codes.PermissionDenied, "a privileged role is required")
+}
@@ -0,0 +1,40 @@
+package budget
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+// Static serves per-user category budgets loaded from a JSON file shaped as
+// {"<userId>": [{"category": "food", "limit": 400}]}. It stands in until
+// budgets are fetched from the budget service.
+type Static struct {
+	budgets map[string][]models.Budget
+}
+
+func LoadFile(path string) (*Static, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var budgets map[string][]models.Budget
+	if err := json.Unmarshal(data, &budgets); err != nil {
+		return nil, fmt.Errorf("parse budgets file: %w", err)
+	}
+	return &Static{budgets: budgets}, nil
+}
+
+func (s *Static) GetBudgets(ctx context.Context, userID string) ([]models.Budget, error) {
+	return s.budgets[userID], nil
+}
+
+// None is used when no budget source is configured.
+type None struct{}
+
+func (None) GetBudgets(ctx context.Context, userID string) ([]models.Budget, error) {
+	return nil, nil
+}
@@ -0,0 +1,345 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	MetricsAddr string
+	GatewayAddr string
+	Auth        AuthConfig
+	Tracing     TracingConfig
+	CORS        CORSConfig
+	Attachments AttachmentConfig
+	Events      EventsConfig
+	Webhooks    WebhookConfig
+	Payees      PayeeConfig
+	Anomalies   AnomalyConfig
+	Budgets     BudgetConfig
+	Statements  StatementConfig
+	Orphans     OrphanConfig
+	RateLimit   RateLimitConfig
+	Quotas      QuotaConfig
+	Metrics     MetricsConfig
+}
+
+type AuthConfig struct {
+	// Enabled is on unless AUTH_DISABLED is set.
+	Enabled         bool
+	HMACSecret      string
+	JWKSFile        string
+	Issuer          string
+	Audience        string
+	PrivilegedRoles []string
+}
+
+type TracingConfig struct {
+	Exporter     string
+	OTLPEndpoint string
+	OTLPInsecure bool
+	SampleRatio  float64
+}
+
+type CORSConfig struct {
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	MaxAge         int
+}
+
+type AttachmentConfig struct {
+	MaxSize      int64
+	ContentTypes []string
+	// Quota caps the bytes a user stores in attachments; zero disables it.
+	Quota int64
+	// Attachments left behind by deleted transactions are swept every
+	// SweepInterval once they are older than SweepGrace.
+	SweepInterval  time.Duration
+	SweepGrace     time.Duration
+	SweepBatchSize int
+}
+
+type EventsConfig struct {
+	Publisher     string
+	File          string
+	NATSURL       string
+	NATSSubject   string
+	KafkaBrokers  []string
+	KafkaTopic    string
+	RelayInterval time.Duration
+	BatchSize     int
+	Retention     time.Duration
+	MaxAttempts   int
+	BaseBackoff   time.Duration
+	MaxBackoff    time.Duration
+}
+
+type WebhookConfig struct {
+	// SecretKey is the base64 AES-256 key encrypting webhook secrets;
+	// webhooks are disabled without it.
+	SecretKey   string
+	Interval    time.Duration
+	BatchSize   int
+	Timeout     time.Duration
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+type PayeeConfig struct {
+	Prefixes     []string
+	CitySuffixes []string
+}
+
+type AnomalyConfig struct {
+	Threshold      float64
+	MinSamples     int
+	History        int
+	NewPayeeFactor float64
+}
+
+type BudgetConfig struct {
+	File string
+}
+
+type StatementConfig struct {
+	SchedulerEnabled bool
+	Interval         time.Duration
+	Format           string
+	Day              int
+	Dir              string
+}
+
+type OrphanConfig struct {
+	ReconcileEnabled bool
+	Interval         time.Duration
+	// DryRun only reports orphaned users; it is on unless turned off.
+	DryRun     bool
+	Policy     string
+	ArchiveDir string
+	// ConfirmAfter is how long a user must stay missing from the user
+	// service before their data is removed.
+	ConfirmAfter time.Duration
+}
+
+type RateLimitConfig struct {
+	Enabled bool
+	Rate    float64
+	Burst   int
+	// Methods holds per-method overrides as "Method=rate:burst".
+	Methods    []string
+	IdleTTL    time.Duration
+	MaxBuckets int
+	// TrustedProxies lists the peers, as IPs or CIDR prefixes, allowed to
+	// name the caller in x-forwarded-for; the REST gateway dials over
+	// loopback.
+	TrustedProxies []string
+}
+
+type MetricsConfig struct {
+	// Categories are counted apart in the created transactions metric; the
+	// rest are counted as other.
+	Categories []string
+}
+
+type QuotaConfig struct {
+	TransactionsPerDay int
+}
+
+func Load() Config {
+	return Config{
+		MetricsAddr: getString("METRICS_ADDR", ":9090"),
+		GatewayAddr: getString("GATEWAY_ADDR", ":8080"),
+		Auth: AuthConfig{
+			Enabled:         !getBool("AUTH_DISABLED", false),
+			HMACSecret:      os.Getenv("AUTH_HMAC_SECRET"),
+			JWKSFile:        os.Getenv("AUTH_JWKS_FILE"),
+			Issuer:          os.Getenv("AUTH_ISSUER"),
+			Audience:        os.Getenv("AUTH_AUDIENCE"),
+			PrivilegedRoles: getList("AUTH_PRIVILEGED_ROLES", []string{"admin", "service"}),
+		},
+		Tracing: TracingConfig{
+			Exporter:     getString("TRACING_EXPORTER", "none"),
+			OTLPEndpoint: getString("TRACING_OTLP_ENDPOINT", "localhost:4317"),
+			OTLPInsecure: getBool("TRACING_OTLP_INSECURE", true),
+			SampleRatio:  getFloat("TRACING_SAMPLE_RATIO", 1),
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: getList("CORS_ALLOWED_ORIGINS", nil),
+			AllowedMethods: getList("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"}),
+			AllowedHeaders: getList("CORS_ALLOWED_HEADERS", []string{"Authorization", "Content-Type"}),
+			MaxAge:         getInt("CORS_MAX_AGE", 600),
+		},
+		Attachments: AttachmentConfig{
+			MaxSize:        int64(getInt("ATTACHMENT_MAX_SIZE", 10<<20)),
+			ContentTypes:   getList("ATTACHMENT_CONTENT_TYPES", []string{"image/jpeg", "image/png", "image/webp", "image/heic", "application/pdf"}),
+			Quota:          int64(getInt("ATTACHMENT_QUOTA", 1<<30)),
+			SweepInterval:  getDuration("ATTACHMENT_SWEEP_INTERVAL", time.Hour),
+			SweepGrace:     getDuration("ATTACHMENT_SWEEP_GRACE", time.Hour),
+			SweepBatchSize: getInt("ATTACHMENT_SWEEP_BATCH_SIZE", 100),
+		},
+		Events: EventsConfig{
+			Publisher:     getString("EVENTS_PUBLISHER", "none"),
+			File:          getString("EVENTS_FILE", "events.jsonl"),
+			NATSURL:       getString("EVENTS_NATS_URL", "nats://localhost:4222"),
+			NATSSubject:   getString("EVENTS_NATS_SUBJECT", "moneykeeper.transactions"),
+			KafkaBrokers:  getList("EVENTS_KAFKA_BROKERS", []string{"localhost:9092"}),
+			KafkaTopic:    getString("EVENTS_KAFKA_TOPIC", "moneykeeper.transactions"),
+			RelayInterval: getDuration("EVENTS_RELAY_INTERVAL", time.Second),
+			BatchSize:     getInt("EVENTS_BATCH_SIZE", 100),
+			Retention:     getDuration("EVENTS_RETENTION", 7*24*time.Hour),
+			MaxAttempts:   getInt("EVENTS_MAX_ATTEMPTS", 10),
+			BaseBackoff:   getDuration("EVENTS_BASE_BACKOFF", 5*time.Second),
+			MaxBackoff:    getDuration("EVENTS_MAX_BACKOFF", time.Hour),
+		},
+		Webhooks: WebhookConfig{
+			SecretKey:   os.Getenv("WEBHOOK_SECRET_KEY"),
+			Interval:    getDuration("WEBHOOK_INTERVAL", time.Second),
+			BatchSize:   getInt("WEBHOOK_BATCH_SIZE", 20),
+			Timeout:     getDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+			MaxAttempts: getInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			BaseBackoff: getDuration("WEBHOOK_BASE_BACKOFF", 30*time.Second),
+			MaxBackoff:  getDuration("WEBHOOK_MAX_BACKOFF", 6*time.Hour),
+		},
+		Payees: PayeeConfig{
+			Prefixes: getList("PAYEE_PREFIXES", []string{"pos", "atm", "card", "purchase", "payment", "pmt", "sq", "paypal", "oplata"}),
+			CitySuffixes: getList("PAYEE_CITY_SUFFIXES", []string{"kyiv", "kiev", "lviv", "kharkiv", "odesa", "odessa", "dnipro",
+				"zaporizhzhia", "vinnytsia", "poltava", "chernihiv", "ivano", "frankivsk", "uzhhorod", "ua", "ukr"}),
+		},
+		Anomalies: AnomalyConfig{
+			Threshold:      getFloat("ANOMALY_THRESHOLD", 3.5),
+			MinSamples:     getInt("ANOMALY_MIN_SAMPLES", 8),
+			History:        getInt("ANOMALY_HISTORY", 200),
+			NewPayeeFactor: getFloat("ANOMALY_NEW_PAYEE_FACTOR", 3),
+		},
+		Budgets: BudgetConfig{
+			File: os.Getenv("BUDGETS_FILE"),
+		},
+		Statements: StatementConfig{
+			SchedulerEnabled: getBool("STATEMENT_SCHEDULER_ENABLED", false),
+			Interval:         getDuration("STATEMENT_INTERVAL", time.Hour),
+			Format:           getString("STATEMENT_FORMAT", "pdf"),
+			Day:              getInt("STATEMENT_DAY", 1),
+			Dir:              getString("STATEMENT_DIR", "statements"),
+		},
+		Orphans: OrphanConfig{
+			ReconcileEnabled: getBool("ORPHAN_RECONCILE_ENABLED", false),
+			Interval:         getDuration("ORPHAN_RECONCILE_INTERVAL", 24*time.Hour),
+			DryRun:           getBool("ORPHAN_DRY_RUN", true),
+			Policy:           getString("ORPHAN_POLICY", "archive"),
+			ArchiveDir:       getString("ORPHAN_ARCHIVE_DIR", "orphans"),
+			ConfirmAfter:     getDuration("ORPHAN_CONFIRM_AFTER", 24*time.Hour),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:        getBool("RATE_LIMIT_ENABLED", true),
+			Rate:           getFloat("RATE_LIMIT_RATE", 20),
+			Burst:          getInt("RATE_LIMIT_BURST", 40),
+			Methods:        getList("RATE_LIMIT_METHODS", []string{"GetTransactionList=1:5", "GetTXByTimeFrame=2:10"}),
+			IdleTTL:        getDuration("RATE_LIMIT_IDLE_TTL", 10*time.Minute),
+			MaxBuckets:     getInt("RATE_LIMIT_MAX_BUCKETS", 100000),
+			TrustedProxies: getList("RATE_LIMIT_TRUSTED_PROXIES", []string{"127.0.0.1", "::1"}),
+		},
+		Quotas: QuotaConfig{
+			TransactionsPerDay: getInt("QUOTA_TRANSACTIONS_PER_DAY", 1000),
+		},
+		Metrics: MetricsConfig{
+			Categories: getList("METRICS_CATEGORIES", []string{"food", "groceries", "transport", "housing", "utilities",
+				"health", "entertainment", "shopping", "travel", "savings", "transfer", "settlement"}),
+		},
+	}
+}
+
+// Validate rejects settings the background jobs cannot run with: tickers
+// panic on non-positive intervals and batch loops stop only on a short batch.
+func (c Config) Validate() error {
+	intervals := []struct {
+		key   string
+		value time.Duration
+	}{
+		{"EVENTS_RELAY_INTERVAL", c.Events.RelayInterval},
+		{"WEBHOOK_INTERVAL", c.Webhooks.Interval},
+		{"STATEMENT_INTERVAL", c.Statements.Interval},
+		{"ORPHAN_RECONCILE_INTERVAL", c.Orphans.Interval},
+		{"ORPHAN_CONFIRM_AFTER", c.Orphans.ConfirmAfter},
+		{"ATTACHMENT_SWEEP_INTERVAL", c.Attachments.SweepInterval},
+	}
+	for _, i := range intervals {
+		if i.value <= 0 {
+			return fmt.Errorf("%s must be positive, got %s", i.key, i.value)
+		}
+	}
+	counts := []struct {
+		key   string
+		value int
+	}{
+		{"EVENTS_BATCH_SIZE", c.Events.BatchSize},
+		{"EVENTS_MAX_ATTEMPTS", c.Events.MaxAttempts},
+		{"WEBHOOK_BATCH_SIZE", c.Webhooks.BatchSize},
+		{"WEBHOOK_MAX_ATTEMPTS", c.Webhooks.MaxAttempts},
+		{"ATTACHMENT_SWEEP_BATCH_SIZE", c.Attachments.SweepBatchSize},
+		{"RATE_LIMIT_MAX_BUCKETS", c.RateLimit.MaxBuckets},
+	}
+	for _, n := range counts {
+		if n.value <= 0 {
+			return fmt.Errorf("%s must be positive, got %d", n.key, n.value)
+		}
+	}
+	return nil
+}
+
+func getString(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return def
+}
+
+func getBool(key string, def bool) bool {
+	v, err := strconv.ParseBool(getString(key, strconv.FormatBool(def)))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+func getInt(key string, def int) int {
+	v, err := strconv.Atoi(getString(key, ""))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+func getFloat(key string, def float64) float64 {
+	v, err := strconv.ParseFloat(getString(key, ""), 64)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+func getDuration(key string, def time.Duration) time.Duration {
+	v, err := time.ParseDuration(getString(key, ""))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+func getList(key string, def []string) []string {
+	v := getString(key, "")
+	if v == "" {
+		return def
+	}
+	var list []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{name: "defaults"},
+		{name: "zero relay interval", env: map[string]string{"EVENTS_RELAY_INTERVAL": "0s"}, wantErr: "EVENTS_RELAY_INTERVAL"},
+		{name: "negative webhook interval", env: map[string]string{"WEBHOOK_INTERVAL": "-1s"}, wantErr: "WEBHOOK_INTERVAL"},
+		{name: "zero statement interval", env: map[string]string{"STATEMENT_INTERVAL": "0"}, wantErr: "STATEMENT_INTERVAL"},
+		{name: "zero orphan interval", env: map[string]string{"ORPHAN_RECONCILE_INTERVAL": "0m"}, wantErr: "ORPHAN_RECONCILE_INTERVAL"},
+		{name: "zero batch size", env: map[string]string{"EVENTS_BATCH_SIZE": "0"}, wantErr: "EVENTS_BATCH_SIZE"},
+		{name: "unparsable interval keeps the default", env: map[string]string{"WEBHOOK_INTERVAL": "soon"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			err := Load().Validate()
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("got %v, want an error about %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package dedupe
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+// centTolerance absorbs float noise when costs are compared exactly.
+const centTolerance = 0.005
+
+type Tolerance struct {
+	Amount        float64
+	Window        time.Duration
+	MinSimilarity float64
+}
+
+// Find groups transactions that are pairwise linked by a near-identical
+// cost, a date within the window and similar names. Links are transitive,
+// so a cluster may span more than one window. Only spending is
+// considered; transfers and goal contributions are skipped.
+func Find(txs []models.Transaction, tol Tolerance) []models.DuplicateCluster {
+	candidates := make([]models.Transaction, 0, len(txs))
+	for _, tx := range txs {
+		if tx.IsSpending() {
+			candidates = append(candidates, tx)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Date.Before(candidates[j].Date) })
+
+	names := make([]string, len(candidates))
+	for i, tx := range candidates {
+		names[i] = Normalize(tx.Name)
+	}
+	parent := make([]int, len(candidates))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	similarity := map[int]float64{}
+	for i := range candidates {
+		for j := i + 1; j < len(candidates); j++ {
+			if candidates[j].Date.Sub(candidates[i].Date) > tol.Window {
+				break
+			}
+			if math.Abs(candidates[i].Cost-candidates[j].Cost) > tol.Amount+centTolerance {
+				continue
+			}
+			sim := similarityOf(names[i], names[j])
+			if sim < tol.MinSimilarity {
+				continue
+			}
+			ri, rj := find(i), find(j)
+			low := sim
+			for _, root := range []int{ri, rj} {
+				if s, ok := similarity[root]; ok && s < low {
+					low = s
+				}
+			}
+			if ri != rj {
+				parent[rj] = ri
+				delete(similarity, rj)
+			}
+			similarity[ri] = low
+		}
+	}
+
+	byRoot := map[int]int{}
+	var clusters []models.DuplicateCluster
+	for i, tx := range candidates {
+		root := find(i)
+		if _, ok := similarity[root]; !ok {
+			continue
+		}
+		idx, ok := byRoot[root]
+		if !ok {
+			idx = len(clusters)
+			byRoot[root] = idx
+			clusters = append(clusters, models.DuplicateCluster{Similarity: similarity[root]})
+		}
+		clusters[idx].Transactions = append(clusters[idx].Transactions, tx)
+	}
+	return clusters
+}
+
+// Normalize lowercases a name and collapses everything but letters and
+// digits into single spaces.
+func Normalize(name string) string {
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// Similarity returns 1 minus the edit distance of the normalized names
+// relative to the longer one.
+func Similarity(a, b string) float64 {
+	return similarityOf(Normalize(a), Normalize(b))
+}
+
+func similarityOf(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
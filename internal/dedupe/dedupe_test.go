@@ -0,0 +1,69 @@
+package dedupe
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+func TestNormalize(t *testing.T) {
+	if got := Normalize("  Netflix.com *Subscr--Ua "); got != "netflix com subscr ua" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{a: "Netflix", b: "NETFLIX!", want: 1},
+		{a: "netflix", b: "netflux", want: 1 - 1.0/7},
+		{a: "кава", b: "Кава", want: 1},
+		{a: "", b: "", want: 1},
+		{a: "abc", b: "", want: 0},
+	}
+	for _, tt := range tests {
+		if got := Similarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	at := func(h int) time.Time { return start.Add(time.Duration(h) * time.Hour) }
+	txs := []models.Transaction{
+		{ID: "a1", Name: "Netflix", Cost: 9.99, Date: at(0)},
+		{ID: "b1", Name: "Uber ride", Cost: 12, Date: at(1)},
+		{ID: "a2", Name: "NETFLIX.", Cost: 9.99, Date: at(20)},
+		// linked to a2 but not to a1: clusters are transitive.
+		{ID: "a3", Name: "Netflix", Cost: 10.00, Date: at(40)},
+		{ID: "b2", Name: "Uber ride", Cost: 15, Date: at(2)},
+		{ID: "c1", Name: "Coffee", Cost: 3, Date: at(3), Kind: models.KindTransferOut},
+		{ID: "c2", Name: "Coffee", Cost: 3, Date: at(3), Kind: models.KindTransferIn},
+		{ID: "d1", Name: "Rent", Cost: 500, Date: at(0)},
+		{ID: "d2", Name: "Rent", Cost: 500, Date: at(100)},
+		{ID: "e1", Name: "Silpo", Cost: 40, Date: at(5)},
+		{ID: "e2", Name: "Silpa", Cost: 40, Date: at(6)},
+	}
+	clusters := Find(txs, Tolerance{Amount: 0.01, Window: 24 * time.Hour, MinSimilarity: 0.8})
+	if len(clusters) != 2 {
+		t.Fatalf("got %d clusters, want 2: %+v", len(clusters), clusters)
+	}
+	ids := func(c models.DuplicateCluster) []string {
+		var out []string
+		for _, tx := range c.Transactions {
+			out = append(out, tx.ID)
+		}
+		return out
+	}
+	if got := ids(clusters[0]); len(got) != 3 || got[0] != "a1" || got[1] != "a2" || got[2] != "a3" || clusters[0].Similarity != 1 {
+		t.Errorf("first cluster %v with similarity %v, want a1 a2 a3 at 1", got, clusters[0].Similarity)
+	}
+	if got := ids(clusters[1]); len(got) != 2 || got[0] != "e1" || got[1] != "e2" || clusters[1].Similarity != 0.8 {
+		t.Errorf("second cluster %v with similarity %v, want e1 e2 at 0.8", got, clusters[1].Similarity)
+	}
+}
@@ -0,0 +1,34 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []models.Event
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, event models.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *MemoryPublisher) Events() []models.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]models.Event(nil), p.events...)
+}
+
+func (p *MemoryPublisher) Close() error {
+	return nil
+}
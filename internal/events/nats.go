@@ -0,0 +1,43 @@
+package events
+
+import (
+	"context"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"github.com/nats-io/nats.go"
+)
+
+// NATSPublisher publishes each event to <subject>.<event type>. Events are
+// flushed one by one so the server has accepted them before they are marked
+// as published.
+type NATSPublisher struct {
+	conn    *nats.Conn
+	subject string
+}
+
+func NewNATSPublisher(url, subject string) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name("moneykeeper-transaction"))
+	if err != nil {
+		return nil, err
+	}
+	return &NATSPublisher{conn: conn, subject: subject}, nil
+}
+
+func (p *NATSPublisher) Publish(ctx context.Context, event models.Event) error {
+	data, err := Encode(event)
+	if err != nil {
+		return err
+	}
+	msg := nats.NewMsg(p.subject + "." + event.Type)
+	msg.Data = data
+	msg.Header.Set(nats.MsgIdHdr, event.ID)
+	msg.Header.Set("User-Id", event.UserID)
+	if err := p.conn.PublishMsg(msg); err != nil {
+		return err
+	}
+	return p.conn.FlushWithContext(ctx)
+}
+
+func (p *NATSPublisher) Close() error {
+	return p.conn.Drain()
+}
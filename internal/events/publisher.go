@@ -0,0 +1,80 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+const (
+	PublisherNone   = "none"
+	PublisherMemory = "memory"
+	PublisherFile   = "file"
+	PublisherNATS   = "nats"
+	PublisherKafka  = "kafka"
+)
+
+// EventPublisher delivers outbox events downstream. Publish must not return
+// until the event is accepted by the transport; the relay retries on error.
+type EventPublisher interface {
+	Publish(ctx context.Context, event models.Event) error
+	Close() error
+}
+
+type Config struct {
+	Publisher    string
+	File         string
+	NATSURL      string
+	NATSSubject  string
+	KafkaBrokers []string
+	KafkaTopic   string
+}
+
+func New(cfg Config) (EventPublisher, error) {
+	switch cfg.Publisher {
+	case "", PublisherNone:
+		return nil, nil
+	case PublisherMemory:
+		return NewMemoryPublisher(), nil
+	case PublisherFile:
+		return NewFilePublisher(cfg.File)
+	case PublisherNATS:
+		return NewNATSPublisher(cfg.NATSURL, cfg.NATSSubject)
+	case PublisherKafka:
+		return NewKafkaPublisher(cfg.KafkaBrokers, cfg.KafkaTopic), nil
+	default:
+		return nil, fmt.Errorf("unknown event publisher %q", cfg.Publisher)
+	}
+}
+
+func Encode(event models.Event) ([]byte, error) {
+	return json.Marshal(event)
+}
+
+// Multi publishes every event to all publishers in turn. A failure makes the
+// relay retry the event on all of them, so each must tolerate duplicates.
+func Multi(publishers ...EventPublisher) EventPublisher {
+	return multiPublisher(publishers)
+}
+
+type multiPublisher []EventPublisher
+
+func (m multiPublisher) Publish(ctx context.Context, event models.Event) error {
+	for _, p := range m {
+		if err := p.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m multiPublisher) Close() error {
+	var errs []error
+	for _, p := range m {
+		errs = append(errs, p.Close())
+	}
+	return errors.Join(errs...)
+}
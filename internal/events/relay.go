@@ -0,0 +1,119 @@
+package events
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+type OutboxStore interface {
+	GetPendingEvents(ctx context.Context, now time.Time, limit int) ([]models.Event, error)
+	MarkPublished(ctx context.Context, eventID string) error
+	RecordFailure(ctx context.Context, eventID string, cause error, nextAttempt *time.Time) error
+	DeletePublishedEvents(ctx context.Context, before time.Time) (int64, error)
+}
+
+type RelayConfig struct {
+	Interval    time.Duration
+	BatchSize   int
+	Retention   time.Duration
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// Relay polls the outbox and hands pending events to the publisher. An event
+// is marked as published only after Publish succeeds, so delivery is
+// at-least-once. A failed event is retried with exponential backoff and
+// becomes a dead letter after MaxAttempts; until then the rest of that
+// user's events are held back to keep them in order.
+type Relay struct {
+	store     OutboxStore
+	publisher EventPublisher
+	cfg       RelayConfig
+}
+
+func NewRelay(store OutboxStore, publisher EventPublisher, cfg RelayConfig) *Relay {
+	return &Relay{store: store, publisher: publisher, cfg: cfg}
+}
+
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		for r.relayBatch(ctx) == r.cfg.BatchSize {
+		}
+		r.cleanup(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relayBatch publishes one batch and returns how many events it published.
+func (r *Relay) relayBatch(ctx context.Context) int {
+	events, err := r.store.GetPendingEvents(ctx, time.Now().UTC(), r.cfg.BatchSize)
+	if err != nil {
+		log.Println(err)
+		return 0
+	}
+	published := 0
+	blocked := map[string]bool{}
+	for _, event := range events {
+		if ctx.Err() != nil {
+			return 0
+		}
+		if blocked[event.UserID] {
+			continue
+		}
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			blocked[event.UserID] = true
+			r.fail(ctx, event, err)
+			continue
+		}
+		if err := r.store.MarkPublished(ctx, event.ID); err != nil {
+			log.Println(err)
+			blocked[event.UserID] = true
+			continue
+		}
+		published++
+	}
+	return published
+}
+
+func (r *Relay) fail(ctx context.Context, event models.Event, cause error) {
+	var next *time.Time
+	if attempt := event.Attempts + 1; attempt < r.cfg.MaxAttempts {
+		at := time.Now().UTC().Add(r.backoff(attempt))
+		next = &at
+		log.Printf("failed to publish event %s (attempt %d), retrying at %s: %v", event.ID, attempt, at.Format(time.RFC3339), cause)
+	} else {
+		log.Printf("failed to publish event %s (attempt %d), giving up: %v", event.ID, attempt, cause)
+	}
+	if err := r.store.RecordFailure(ctx, event.ID, cause, next); err != nil {
+		log.Println(err)
+	}
+}
+
+// backoff returns BaseBackoff doubled for every attempt already made, capped
+// at MaxBackoff.
+func (r *Relay) backoff(attempt int) time.Duration {
+	delay := r.cfg.BaseBackoff
+	for i := 1; i < attempt && delay < r.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, r.cfg.MaxBackoff)
+}
+
+func (r *Relay) cleanup(ctx context.Context) {
+	if r.cfg.Retention <= 0 {
+		return
+	}
+	if _, err := r.store.DeletePublishedEvents(ctx, time.Now().UTC().Add(-r.cfg.Retention)); err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,158 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+// memStore is an in-memory outbox following OutboxRepo's rules: users with
+// an event waiting for its next attempt are skipped entirely.
+type memStore struct {
+	events  []models.Event
+	deleted int
+}
+
+func (s *memStore) GetPendingEvents(_ context.Context, now time.Time, limit int) ([]models.Event, error) {
+	pending := func(e models.Event) bool { return e.PublishedAt == nil && e.Status != models.EventDead }
+	deferred := map[string]bool{}
+	for _, e := range s.events {
+		if pending(e) && e.NextAttemptAt != nil && e.NextAttemptAt.After(now) {
+			deferred[e.UserID] = true
+		}
+	}
+	var events []models.Event
+	for _, e := range s.events {
+		if pending(e) && !deferred[e.UserID] && len(events) < limit {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func (s *memStore) find(id string) *models.Event {
+	for i := range s.events {
+		if s.events[i].ID == id {
+			return &s.events[i]
+		}
+	}
+	return nil
+}
+
+func (s *memStore) MarkPublished(_ context.Context, id string) error {
+	now := time.Now()
+	e := s.find(id)
+	e.PublishedAt = &now
+	e.Attempts++
+	e.NextAttemptAt = nil
+	return nil
+}
+
+func (s *memStore) RecordFailure(_ context.Context, id string, cause error, next *time.Time) error {
+	e := s.find(id)
+	e.Attempts++
+	e.LastError = cause.Error()
+	e.NextAttemptAt = next
+	if next == nil {
+		e.Status = models.EventDead
+	}
+	return nil
+}
+
+func (s *memStore) DeletePublishedEvents(_ context.Context, before time.Time) (int64, error) {
+	n := len(s.events)
+	s.events = slices.DeleteFunc(s.events, func(e models.Event) bool { return e.PublishedAt != nil && e.PublishedAt.Before(before) })
+	s.deleted += n - len(s.events)
+	return int64(n - len(s.events)), nil
+}
+
+// flakyPublisher fails every event whose ID is in failing.
+type flakyPublisher struct {
+	failing   map[string]bool
+	published []string
+}
+
+func (p *flakyPublisher) Publish(_ context.Context, e models.Event) error {
+	if p.failing[e.ID] {
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, e.ID)
+	return nil
+}
+
+func (p *flakyPublisher) Close() error { return nil }
+
+func TestRelayHoldsBackUserAfterFailure(t *testing.T) {
+	ctx := context.Background()
+	store := &memStore{events: []models.Event{
+		{ID: "a1", UserID: "alice"},
+		{ID: "b1", UserID: "bob"},
+		{ID: "a2", UserID: "alice"},
+		{ID: "b2", UserID: "bob"},
+	}}
+	publisher := &flakyPublisher{failing: map[string]bool{"a1": true}}
+	relay := NewRelay(store, publisher, RelayConfig{BatchSize: 10, MaxAttempts: 3, BaseBackoff: time.Minute, MaxBackoff: time.Hour})
+
+	relay.relayBatch(ctx)
+	relay.relayBatch(ctx)
+	if want := []string{"b1", "b2"}; !slices.Equal(publisher.published, want) {
+		t.Fatalf("published %v, want %v", publisher.published, want)
+	}
+	a1 := store.find("a1")
+	if a1.Attempts != 1 || a1.NextAttemptAt == nil || time.Until(*a1.NextAttemptAt) < 59*time.Second {
+		t.Errorf("a1 should be retried in a minute, got %+v", a1)
+	}
+
+	delete(publisher.failing, "a1")
+	a1.NextAttemptAt = nil
+	relay.relayBatch(ctx)
+	if want := []string{"b1", "b2", "a1", "a2"}; !slices.Equal(publisher.published, want) {
+		t.Errorf("published %v, want %v", publisher.published, want)
+	}
+}
+
+func TestRelayDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	store := &memStore{events: []models.Event{{ID: "a1", UserID: "alice"}, {ID: "a2", UserID: "alice"}}}
+	publisher := &flakyPublisher{failing: map[string]bool{"a1": true}}
+	relay := NewRelay(store, publisher, RelayConfig{BatchSize: 10, MaxAttempts: 3, BaseBackoff: time.Minute, MaxBackoff: time.Hour})
+
+	for i := 0; i < 3; i++ {
+		relay.relayBatch(ctx)
+		store.find("a1").NextAttemptAt = nil
+	}
+	if a1 := store.find("a1"); a1.Status != models.EventDead || a1.Attempts != 3 {
+		t.Fatalf("a1 should be dead after 3 attempts, got %+v", a1)
+	}
+	relay.relayBatch(ctx)
+	if want := []string{"a2"}; !slices.Equal(publisher.published, want) {
+		t.Errorf("published %v, want %v once a1 is dead", publisher.published, want)
+	}
+}
+
+func TestRelayBackoff(t *testing.T) {
+	relay := NewRelay(nil, nil, RelayConfig{BaseBackoff: time.Second, MaxBackoff: 10 * time.Second})
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 4: 8 * time.Second, 5: 10 * time.Second, 30: 10 * time.Second} {
+		if got := relay.backoff(attempt); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
+
+func TestRelayCleanup(t *testing.T) {
+	old := time.Now().Add(-48 * time.Hour)
+	recent := time.Now().Add(-time.Hour)
+	store := &memStore{events: []models.Event{
+		{ID: "old", PublishedAt: &old},
+		{ID: "recent", PublishedAt: &recent},
+		{ID: "pending"},
+	}}
+	NewRelay(store, nil, RelayConfig{Retention: 24 * time.Hour}).cleanup(context.Background())
+	if store.deleted != 1 || store.find("old") != nil {
+		t.Errorf("deleted %d events, want only the old published one", store.deleted)
+	}
+}
@@ -0,0 +1,265 @@
+package forecast
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+const (
+	day = 24 * time.Hour
+	// RecurringLookback is how much history recurring items are detected in.
+	RecurringLookback = 180 * day
+	// z90 turns a standard deviation into a 90% two-sided range.
+	z90 = 1.645
+)
+
+type line struct {
+	category string
+	amount   float64
+}
+
+// Project estimates where spending in period will land. Spending before
+// asOf is taken as is; the rest of the period is filled with recurring
+// items that are due and, for every remaining day, the average spent on
+// that weekday during the lookback window before asOf. history must cover
+// the lookback window, RecurringLookback and the period.
+func Project(history []models.Transaction, period models.TimeFrame, asOf time.Time, lookback time.Duration) models.SpendingForecast {
+	result := models.SpendingForecast{StartDate: period.StartDate, EndDate: period.EndDate, AsOf: asOf}
+	recurring, seriesTx := detectRecurring(history, asOf, period.EndDate)
+	for _, item := range recurring {
+		if item.Due.Before(period.StartDate) {
+			continue
+		}
+		result.Recurring = append(result.Recurring, item)
+	}
+
+	byCategory := map[string]*models.CategoryForecast{}
+	variance := map[string]float64{}
+	get := func(category string) *models.CategoryForecast {
+		f, ok := byCategory[category]
+		if !ok {
+			f = &models.CategoryForecast{Category: category}
+			byCategory[category] = f
+		}
+		return f
+	}
+	for _, tx := range history {
+		if !tx.IsSpending() || tx.Date.Before(period.StartDate) || !tx.Date.Before(asOf) || tx.Date.After(period.EndDate) {
+			continue
+		}
+		for _, l := range lines(tx) {
+			get(l.category).Spent += l.amount
+		}
+	}
+	for _, item := range result.Recurring {
+		get(item.Category).Recurring += item.Amount
+	}
+
+	rates := weekdayRates(history, seriesTx, asOf, lookback)
+	first := truncateDay(asOf)
+	if first.Before(asOf) {
+		first = first.Add(day)
+	}
+	if start := truncateDay(period.StartDate); first.Before(start) {
+		first = start
+	}
+	for d := first; !d.After(period.EndDate); d = d.Add(day) {
+		for category, byWeekday := range rates {
+			r := byWeekday[d.Weekday()]
+			if r.mean == 0 && r.variance == 0 {
+				continue
+			}
+			get(category).Projected += r.mean
+			variance[category] += r.variance
+		}
+	}
+
+	total := models.CategoryForecast{Category: "total"}
+	var totalVariance float64
+	for category, f := range byCategory {
+		spread := z90 * math.Sqrt(variance[category])
+		base := f.Spent + f.Recurring
+		f.Low = base + math.Max(0, f.Projected-spread)
+		f.High = base + f.Projected + spread
+		f.Projected += base
+		result.Categories = append(result.Categories, *f)
+
+		total.Spent += f.Spent
+		total.Recurring += f.Recurring
+		total.Projected += f.Projected
+		totalVariance += variance[category]
+	}
+	base := total.Spent + total.Recurring
+	spread := z90 * math.Sqrt(totalVariance)
+	total.Low = base + math.Max(0, total.Projected-base-spread)
+	total.High = total.Projected + spread
+	result.Total = total
+	sort.Slice(result.Categories, func(i, j int) bool {
+		if result.Categories[i].Projected != result.Categories[j].Projected {
+			return result.Categories[i].Projected > result.Categories[j].Projected
+		}
+		return result.Categories[i].Category < result.Categories[j].Category
+	})
+	return result
+}
+
+type rate struct {
+	mean     float64
+	variance float64
+}
+
+// weekdayRates returns, per category and weekday, the mean and variance of
+// daily spending over whole days in the lookback window before asOf.
+// Transactions that belong to recurring series are left out, since those
+// are projected separately.
+func weekdayRates(history []models.Transaction, exclude map[string]bool, asOf time.Time, lookback time.Duration) map[string][7]rate {
+	end := truncateDay(asOf)
+	start := end.Add(-lookback)
+	days := int(end.Sub(start) / day)
+	if days <= 0 {
+		return nil
+	}
+	daily := map[string][]float64{}
+	for _, tx := range history {
+		if !tx.IsSpending() || exclude[tx.ID] || tx.Date.Before(start) || !tx.Date.Before(end) {
+			continue
+		}
+		idx := int(tx.Date.Sub(start) / day)
+		for _, l := range lines(tx) {
+			if daily[l.category] == nil {
+				daily[l.category] = make([]float64, days)
+			}
+			daily[l.category][idx] += l.amount
+		}
+	}
+	rates := make(map[string][7]rate, len(daily))
+	for category, sums := range daily {
+		var byWeekday [7][]float64
+		for i, v := range sums {
+			wd := start.Add(time.Duration(i) * day).Weekday()
+			byWeekday[wd] = append(byWeekday[wd], v)
+		}
+		var r [7]rate
+		for wd, values := range byWeekday {
+			r[wd] = meanVariance(values)
+		}
+		rates[category] = r
+	}
+	return rates
+}
+
+func meanVariance(values []float64) rate {
+	if len(values) == 0 {
+		return rate{}
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	if len(values) < 2 {
+		return rate{mean: mean}
+	}
+	var sq float64
+	for _, v := range values {
+		sq += (v - mean) * (v - mean)
+	}
+	return rate{mean: mean, variance: sq / float64(len(values)-1)}
+}
+
+// detectRecurring finds series of at least three payments to the same payee
+// at a steady weekly, biweekly or monthly interval with a steady amount,
+// and returns their occurrences expected after asOf up to until, including
+// one that is overdue. It also returns the IDs of transactions in the
+// series.
+func detectRecurring(history []models.Transaction, asOf, until time.Time) ([]models.RecurringItem, map[string]bool) {
+	series := map[string][]models.Transaction{}
+	for _, tx := range history {
+		if !tx.IsSpending() || !tx.Date.Before(asOf) || tx.Date.Before(asOf.Add(-RecurringLookback)) {
+			continue
+		}
+		key := tx.PayeeID
+		if key == "" {
+			key = strings.ToLower(strings.TrimSpace(tx.Name))
+		}
+		series[key] = append(series[key], tx)
+	}
+	var items []models.RecurringItem
+	members := map[string]bool{}
+	for _, txs := range series {
+		if len(txs) < 3 {
+			continue
+		}
+		sort.Slice(txs, func(i, j int) bool { return txs[i].Date.Before(txs[j].Date) })
+		intervals := make([]float64, len(txs)-1)
+		amounts := make([]float64, len(txs))
+		for i, tx := range txs {
+			amounts[i] = tx.Cost
+			if i > 0 {
+				intervals[i-1] = tx.Date.Sub(txs[i-1].Date).Hours() / 24
+			}
+		}
+		interval, intervalMAD := medianMAD(intervals)
+		amount, amountMAD := medianMAD(amounts)
+		if !regularInterval(interval) || intervalMAD > 0.2*interval || amountMAD > 0.15*amount {
+			continue
+		}
+		last := txs[len(txs)-1]
+		step := time.Duration(interval * float64(day))
+		if asOf.Sub(last.Date) > step+step/2 {
+			continue
+		}
+		for _, tx := range txs {
+			members[tx.ID] = true
+		}
+		for due := last.Date.Add(step); !due.After(until); due = due.Add(step) {
+			items = append(items, models.RecurringItem{Name: last.Name, Category: last.Category, Amount: amount, Due: due})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Due.Before(items[j].Due) })
+	return items, members
+}
+
+func regularInterval(days float64) bool {
+	return days >= 6 && days <= 8 || days >= 13 && days <= 16 || days >= 27 && days <= 33
+}
+
+func medianMAD(values []float64) (float64, float64) {
+	median := medianOf(values)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - median)
+	}
+	return median, medianOf(deviations)
+}
+
+func medianOf(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2
+}
+
+// lines splits a transaction into per-category amounts like the category
+// summary does.
+func lines(tx models.Transaction) []line {
+	if len(tx.Splits) == 0 {
+		return []line{{category: tx.Category, amount: tx.Cost}}
+	}
+	out := make([]line, len(tx.Splits))
+	for i, s := range tx.Splits {
+		out[i] = line{category: s.Category, amount: s.Amount}
+	}
+	return out
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
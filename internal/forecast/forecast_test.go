@@ -0,0 +1,104 @@
+package forecast
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+func date(month time.Month, d int) time.Time {
+	return time.Date(2024, month, d, 12, 0, 0, 0, time.UTC)
+}
+
+func TestProject(t *testing.T) {
+	var history []models.Transaction
+	for d := date(time.February, 17); d.Before(date(time.March, 16)); d = d.Add(day) {
+		history = append(history, models.Transaction{ID: d.Format("0102"), Name: "Silpo", Category: "food", Cost: 10, Date: d})
+	}
+	for i, d := range []time.Time{time.Date(2023, time.December, 22, 12, 0, 0, 0, time.UTC), date(time.January, 21), date(time.February, 20)} {
+		history = append(history, models.Transaction{ID: "netflix" + string(rune('0'+i)), Name: "Netflix", Category: "subscriptions", Cost: 15, Date: d})
+	}
+	// transfers never count as spending.
+	history = append(history, models.Transaction{ID: "transfer", Name: "Savings", Cost: 500, Date: date(time.March, 2), Kind: models.KindTransferOut})
+
+	period := models.TimeFrame{StartDate: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2024, time.March, 31, 23, 59, 59, 0, time.UTC)}
+	asOf := time.Date(2024, time.March, 16, 0, 0, 0, 0, time.UTC)
+	got := Project(history, period, asOf, 28*day)
+
+	if len(got.Recurring) != 1 || got.Recurring[0].Name != "Netflix" || got.Recurring[0].Amount != 15 || !got.Recurring[0].Due.Equal(date(time.March, 21)) {
+		t.Fatalf("recurring %+v, want Netflix due on March 21", got.Recurring)
+	}
+	want := map[string]models.CategoryForecast{
+		// 15 days spent, 16 days projected at 10 a day with no variance.
+		"food":          {Category: "food", Spent: 150, Projected: 310, Low: 310, High: 310},
+		"subscriptions": {Category: "subscriptions", Recurring: 15, Projected: 15, Low: 15, High: 15},
+	}
+	if len(got.Categories) != len(want) {
+		t.Fatalf("categories %+v", got.Categories)
+	}
+	for _, c := range got.Categories {
+		w := want[c.Category]
+		if math.Abs(c.Spent-w.Spent) > 1e-9 || math.Abs(c.Recurring-w.Recurring) > 1e-9 || math.Abs(c.Projected-w.Projected) > 1e-9 ||
+			math.Abs(c.Low-w.Low) > 1e-9 || math.Abs(c.High-w.High) > 1e-9 {
+			t.Errorf("%s: got %+v, want %+v", c.Category, c, w)
+		}
+	}
+	if got.Categories[0].Category != "food" {
+		t.Errorf("categories should be sorted by projection, got %+v", got.Categories)
+	}
+	if got.Total.Projected != 325 || got.Total.Spent != 150 || got.Total.Recurring != 15 {
+		t.Errorf("total %+v", got.Total)
+	}
+}
+
+func TestProjectRange(t *testing.T) {
+	// spending alternates between 0 and 20 on the same weekday, so the
+	// projection is 10 a week with a range around it.
+	var history []models.Transaction
+	for i, d := 0, date(time.February, 5); i < 4; i, d = i+1, d.Add(7*day) {
+		history = append(history, models.Transaction{ID: d.Format("0102"), Name: "Market", Category: "food", Cost: float64(20 * (i % 2)), Date: d})
+	}
+	start := time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC)
+	period := models.TimeFrame{StartDate: start, EndDate: start.Add(7*day - time.Second)}
+	got := Project(history, period, start, 28*day)
+	if len(got.Categories) != 1 {
+		t.Fatalf("categories %+v, want food only", got.Categories)
+	}
+	food := got.Categories[0]
+	if food.Projected != 10 || food.Low >= food.Projected || food.High <= food.Projected || food.Low < 0 {
+		t.Errorf("got %+v, want 10 inside a non-negative range", food)
+	}
+}
+
+func TestDetectRecurring(t *testing.T) {
+	asOf := date(time.April, 1)
+	tests := []struct {
+		name  string
+		dates []time.Time
+		costs []float64
+		want  int
+	}{
+		{name: "weekly", dates: []time.Time{date(time.March, 11), date(time.March, 18), date(time.March, 25)}, costs: []float64{5, 5, 5.2}, want: 2},
+		{name: "two payments are not a series", dates: []time.Time{date(time.March, 18), date(time.March, 25)}, costs: []float64{5, 5}},
+		{name: "irregular interval", dates: []time.Time{date(time.March, 1), date(time.March, 5), date(time.March, 25)}, costs: []float64{5, 5, 5}},
+		{name: "varying amount", dates: []time.Time{date(time.March, 11), date(time.March, 18), date(time.March, 25)}, costs: []float64{5, 9, 14}},
+		{name: "stopped", dates: []time.Time{date(time.January, 1), date(time.January, 8), date(time.January, 15)}, costs: []float64{5, 5, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var history []models.Transaction
+			for i, d := range tt.dates {
+				history = append(history, models.Transaction{ID: d.Format("0102"), Name: "Gym ", Category: "sport", Cost: tt.costs[i], Date: d})
+			}
+			items, members := detectRecurring(history, asOf, date(time.April, 14))
+			if len(items) != tt.want {
+				t.Fatalf("got %+v, want %d items", items, tt.want)
+			}
+			if tt.want > 0 && (len(members) != len(tt.dates) || items[0].Amount != 5 || !items[0].Due.Equal(date(time.April, 1))) {
+				t.Errorf("items %+v, members %v", items, members)
+			}
+		})
+	}
+}
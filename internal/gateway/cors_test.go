@@ -0,0 +1,66 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithCORS(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	cfg := CORSConfig{
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Authorization"},
+		MaxAge:         600,
+	}
+	tests := []struct {
+		name        string
+		allowed     []string
+		origin      string
+		preflight   bool
+		wantOrigin  string
+		wantCreds   bool
+		wantStatus  int
+		wantMethods string
+	}{
+		{name: "listed origin", allowed: []string{"https://app.example"}, origin: "https://app.example",
+			wantOrigin: "https://app.example", wantCreds: true, wantStatus: http.StatusOK},
+		{name: "unlisted origin", allowed: []string{"https://app.example"}, origin: "https://evil.example",
+			wantStatus: http.StatusOK},
+		{name: "wildcard", allowed: []string{"*"}, origin: "https://evil.example",
+			wantOrigin: "*", wantStatus: http.StatusOK},
+		{name: "wildcard keeps credentials for listed origins", allowed: []string{"*", "https://app.example"}, origin: "https://app.example",
+			wantOrigin: "https://app.example", wantCreds: true, wantStatus: http.StatusOK},
+		{name: "preflight", allowed: []string{"https://app.example"}, origin: "https://app.example", preflight: true,
+			wantOrigin: "https://app.example", wantCreds: true, wantStatus: http.StatusNoContent, wantMethods: "GET, POST"},
+		{name: "no origin", allowed: []string{"*"}, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.AllowedOrigins = tt.allowed
+			req := httptest.NewRequest(http.MethodGet, "/v1/transactions", nil)
+			if tt.preflight {
+				req.Method = http.MethodOptions
+				req.Header.Set("Access-Control-Request-Method", "POST")
+			}
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			rec := httptest.NewRecorder()
+			withCORS(ok, cfg).ServeHTTP(rec, req)
+			h := rec.Header()
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := h.Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("allow origin = %q, want %q", got, tt.wantOrigin)
+			}
+			if got := h.Get("Access-Control-Allow-Credentials") == "true"; got != tt.wantCreds {
+				t.Errorf("allow credentials = %v, want %v", got, tt.wantCreds)
+			}
+			if got := h.Get("Access-Control-Allow-Methods"); got != tt.wantMethods {
+				t.Errorf("allow methods = %q, want %q", got, tt.wantMethods)
+			}
+		})
+	}
+}
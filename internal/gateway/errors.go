@@ -0,0 +1,81 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+type errorBody struct {
+	Error errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Code    int               `json:"code"`
+	Status  string            `json:"status"`
+	Message string            `json:"message"`
+	Details []json.RawMessage `json:"details,omitempty"`
+}
+
+func errorHandler(ctx context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, _ *http.Request, err error) {
+	st := status.Convert(err)
+	httpStatus := runtime.HTTPStatusFromCode(st.Code())
+	body := errorBody{Error: errorDetail{
+		Code:    httpStatus,
+		Status:  codeName(st),
+		Message: st.Message(),
+	}}
+	for _, detail := range st.Proto().GetDetails() {
+		raw, err := marshalDetail(detail)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		body.Error.Details = append(body.Error.Details, raw)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if retry := retryAfter(st); retry != "" {
+		w.Header().Set("Retry-After", retry)
+	}
+	w.WriteHeader(httpStatus)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Println(err)
+	}
+}
+
+// retryAfter turns a RetryInfo detail into whole seconds for the Retry-After
+// header.
+func retryAfter(st *status.Status) string {
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			return strconv.Itoa(int(math.Ceil(info.GetRetryDelay().AsDuration().Seconds())))
+		}
+	}
+	return ""
+}
+
+func marshalDetail(detail *anypb.Any) (json.RawMessage, error) {
+	raw, err := protojson.Marshal(detail)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(raw), nil
+}
+
+func codeName(st *status.Status) string {
+	if name, ok := code.Code_name[int32(st.Code())]; ok {
+		return name
+	}
+	return code.Code_UNKNOWN.String()
+}
@@ -0,0 +1,45 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorHandler(t *testing.T) {
+	st, err := status.New(codes.InvalidArgument, "invalid request").WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "cost", Description: "is required"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	errorHandler(context.Background(), nil, nil, rec, httptest.NewRequest(http.MethodPost, "/v1/transactions", nil), st.Err())
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	var body struct {
+		Error struct {
+			Code    int
+			Status  string
+			Message string
+			Details []map[string]interface{}
+		}
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Error.Code != 400 || body.Error.Status != "INVALID_ARGUMENT" || body.Error.Message != "invalid request" {
+		t.Fatalf("body = %+v", body.Error)
+	}
+	if len(body.Error.Details) != 1 || body.Error.Details[0]["@type"] != "type.googleapis.com/google.rpc.BadRequest" {
+		t.Fatalf("details = %v", body.Error.Details)
+	}
+}
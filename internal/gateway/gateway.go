@@ -0,0 +1,51 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	transactionProto "github.com/justIGreK/MoneyKeeper-Transaction/pkg/go/transaction"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+type Config struct {
+	GRPCAddr string
+	CORS     CORSConfig
+}
+
+func New(ctx context.Context, cfg Config) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions:   protojson.MarshalOptions{EmitUnpopulated: true},
+			UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+		}),
+		runtime.WithErrorHandler(errorHandler),
+	)
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	}
+	for _, register := range []func(context.Context, *runtime.ServeMux, string, []grpc.DialOption) error{
+		transactionProto.RegisterTransactionServiceHandlerFromEndpoint,
+		transactionProto.RegisterSharedExpenseServiceHandlerFromEndpoint,
+		transactionProto.RegisterAccountServiceHandlerFromEndpoint,
+		transactionProto.RegisterAttachmentServiceHandlerFromEndpoint,
+		transactionProto.RegisterWebhookServiceHandlerFromEndpoint,
+		transactionProto.RegisterRuleServiceHandlerFromEndpoint,
+		transactionProto.RegisterPayeeServiceHandlerFromEndpoint,
+		transactionProto.RegisterGoalServiceHandlerFromEndpoint,
+		transactionProto.RegisterStatementServiceHandlerFromEndpoint,
+		transactionProto.RegisterPrivacyServiceHandlerFromEndpoint,
+		transactionProto.RegisterOrphanServiceHandlerFromEndpoint,
+		transactionProto.RegisterLedgerServiceHandlerFromEndpoint,
+	} {
+		if err := register(ctx, mux, cfg.GRPCAddr, opts); err != nil {
+			return nil, err
+		}
+	}
+	return withCORS(mux, cfg.CORS), nil
+}
@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		started := time.Now()
+		resp, err := handler(ctx, req)
+		m.observeRPC(info.FullMethod, started, err)
+		return resp, err
+	}
+}
+
+func (m *Metrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		started := time.Now()
+		err := handler(srv, ss)
+		m.observeRPC(info.FullMethod, started, err)
+		return err
+	}
+}
+
+func (m *Metrics) observeRPC(method string, started time.Time, err error) {
+	code := status.Code(err).String()
+	m.rpcDuration.WithLabelValues(method, code).Observe(time.Since(started).Seconds())
+	m.rpcRequests.WithLabelValues(method, code).Inc()
+}
@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "mktx"
+
+// otherCategory labels created transactions outside the known categories.
+const otherCategory = "other"
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	rpcDuration  *prometheus.HistogramVec
+	rpcRequests  *prometheus.CounterVec
+	repoDuration *prometheus.HistogramVec
+	repoErrors   *prometheus.CounterVec
+	repoDocs     *prometheus.CounterVec
+	userDuration *prometheus.HistogramVec
+	txCreated    *prometheus.CounterVec
+	// categories are the category labels kept apart; the rest count as
+	// other, since categories are free text and each label is a series.
+	categories map[string]bool
+}
+
+func New(categories []string) *Metrics {
+	m := &Metrics{
+		registry:   prometheus.NewRegistry(),
+		categories: make(map[string]bool, len(categories)),
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "grpc",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of handled gRPC requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "code"}),
+		rpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "grpc",
+			Name:      "requests_total",
+			Help:      "Handled gRPC requests by method and status code.",
+		}, []string{"method", "code"}),
+		repoDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "repository",
+			Name:      "operation_duration_seconds",
+			Help:      "Latency of repository operations.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation"}),
+		repoErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "repository",
+			Name:      "errors_total",
+			Help:      "Failed repository operations.",
+		}, []string{"operation"}),
+		repoDocs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "repository",
+			Name:      "documents_returned_total",
+			Help:      "Documents returned by repository operations.",
+		}, []string{"operation"}),
+		userDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "user_client",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of UserService.GetUser calls.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"code"}),
+		txCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "transactions_created_total",
+			Help:      "Created transactions by category.",
+		}, []string{"category"}),
+	}
+	for _, c := range categories {
+		m.categories[strings.ToLower(c)] = true
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.rpcDuration, m.rpcRequests,
+		m.repoDuration, m.repoErrors, m.repoDocs,
+		m.userDuration, m.txCreated,
+	)
+	return m
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+func (m *Metrics) ObserveQuery(operation string, started time.Time, docs int, err error) {
+	m.repoDuration.WithLabelValues(operation).Observe(time.Since(started).Seconds())
+	if err != nil {
+		m.repoErrors.WithLabelValues(operation).Inc()
+		return
+	}
+	if docs > 0 {
+		m.repoDocs.WithLabelValues(operation).Add(float64(docs))
+	}
+}
+
+func (m *Metrics) TransactionCreated(category string) {
+	category = strings.ToLower(category)
+	if !m.categories[category] {
+		category = otherCategory
+	}
+	m.txCreated.WithLabelValues(category).Inc()
+}
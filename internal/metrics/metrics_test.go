@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/pkg/client"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	m := New([]string{"food"})
+	intercept := m.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/transaction.TransactionService/AddTransaction"}
+	ok := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	fail := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "missing")
+	}
+	for _, handler := range []grpc.UnaryHandler{ok, ok, fail} {
+		intercept(context.Background(), nil, info, handler)
+	}
+	if got := testutil.ToFloat64(m.rpcRequests.WithLabelValues(info.FullMethod, "OK")); got != 2 {
+		t.Errorf("OK requests = %v", got)
+	}
+	if got := testutil.ToFloat64(m.rpcRequests.WithLabelValues(info.FullMethod, "NotFound")); got != 1 {
+		t.Errorf("NotFound requests = %v", got)
+	}
+}
+
+func TestObserveQuery(t *testing.T) {
+	m := New([]string{"food"})
+	m.ObserveQuery("GetTXByTimeFrame", time.Now(), 3, nil)
+	m.ObserveQuery("GetTXByTimeFrame", time.Now(), 2, nil)
+	m.ObserveQuery("GetTXByTimeFrame", time.Now(), 0, errors.New("timeout"))
+	if got := testutil.ToFloat64(m.repoDocs.WithLabelValues("GetTXByTimeFrame")); got != 5 {
+		t.Errorf("documents = %v", got)
+	}
+	if got := testutil.ToFloat64(m.repoErrors.WithLabelValues("GetTXByTimeFrame")); got != 1 {
+		t.Errorf("errors = %v", got)
+	}
+}
+
+type userStub struct{ err error }
+
+func (u userStub) GetUser(ctx context.Context, id string) (string, string, error) {
+	return id, "name", u.err
+}
+
+func TestHandlerExposesUserCache(t *testing.T) {
+	m := New([]string{"food"})
+	users := m.InstrumentUserClient(userStub{})
+	users.GetUser(context.Background(), "u1")
+	m.RegisterUserCache(func() client.CacheStats {
+		return client.CacheStats{Hits: 3, Misses: 1, BreakerState: "open"}
+	})
+	m.TransactionCreated("food")
+	m.TransactionCreated("Food")
+	m.TransactionCreated("gift for Ann")
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	for _, want := range []string{
+		"mktx_user_cache_hits_total 3",
+		"mktx_user_cache_hit_ratio 0.75",
+		`mktx_user_client_circuit_breaker_state{state="open"} 1`,
+		`mktx_user_client_circuit_breaker_state{state="closed"} 0`,
+		`mktx_user_client_request_duration_seconds_count{code="OK"} 1`,
+		`mktx_transactions_created_total{category="food"} 2`,
+		`mktx_transactions_created_total{category="other"} 1`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics output lacks %q", want)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/pkg/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/status"
+)
+
+type instrumentedUserClient struct {
+	next     client.UserGetter
+	duration *prometheus.HistogramVec
+}
+
+func (m *Metrics) InstrumentUserClient(next client.UserGetter) client.UserGetter {
+	return &instrumentedUserClient{next: next, duration: m.userDuration}
+}
+
+func (c *instrumentedUserClient) GetUser(ctx context.Context, id string) (string, string, error) {
+	started := time.Now()
+	userID, name, err := c.next.GetUser(ctx, id)
+	c.duration.WithLabelValues(status.Code(err).String()).Observe(time.Since(started).Seconds())
+	return userID, name, err
+}
+
+var breakerStates = []string{"closed", "half-open", "open"}
+
+func (m *Metrics) RegisterUserCache(stats func() client.CacheStats) {
+	counter := func(name, help string, value func(client.CacheStats) uint64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "user_cache",
+			Name:      name,
+			Help:      help,
+		}, func() float64 { return float64(value(stats())) })
+	}
+	m.registry.MustRegister(
+		counter("hits_total", "User lookups served from the cache.", func(s client.CacheStats) uint64 { return s.Hits }),
+		counter("negative_hits_total", "Unknown-user lookups served from the cache.", func(s client.CacheStats) uint64 { return s.NegativeHits }),
+		counter("misses_total", "User lookups that reached the user service.", func(s client.CacheStats) uint64 { return s.Misses }),
+		counter("errors_total", "User lookups that failed.", func(s client.CacheStats) uint64 { return s.Errors }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "user_cache",
+			Name:      "hit_ratio",
+			Help:      "Share of user lookups served from the cache.",
+		}, func() float64 { return stats().HitRate() }),
+	)
+	for _, state := range breakerStates {
+		state := state
+		m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Subsystem:   "user_client",
+			Name:        "circuit_breaker_state",
+			Help:        "Current state of the user service circuit breaker.",
+			ConstLabels: prometheus.Labels{"state": state},
+		}, func() float64 {
+			if stats().BreakerState == state {
+				return 1
+			}
+			return 0
+		}))
+	}
+}
@@ -0,0 +1,80 @@
+package models
+
+import "time"
+
+const (
+	KindExpense     = "expense"
+	KindTransferOut = "transfer_out"
+	KindTransferIn  = "transfer_in"
+	// KindContribution is money put toward a tag goal. Contributions to an
+	// account goal are transfers.
+	KindContribution = "contribution"
+	// Settlement legs record money paid back within a shared expense group.
+	KindSettlementOut = "settlement_out"
+	KindSettlementIn  = "settlement_in"
+)
+
+// Entries of these kinds add to or take from their account's balance. An
+// entry without a kind predates kinds and is an expense; any other kind
+// leaves the balance alone.
+var (
+	InflowKinds  = []string{KindTransferIn, KindSettlementIn}
+	OutflowKinds = []string{"", KindExpense, KindTransferOut, KindSettlementOut}
+)
+
+const (
+	TransferCategory   = "transfer"
+	SettlementCategory = "settlement"
+)
+
+type Account struct {
+	ID             string    `bson:"_id,omitempty"`
+	UserID         string    `bson:"user_id"`
+	Name           string    `bson:"name"`
+	Type           string    `bson:"type"`
+	Currency       string    `bson:"currency"`
+	OpeningBalance float64   `bson:"opening_balance"`
+	CreatedAt      time.Time `bson:"created_at"`
+}
+
+type CreateAccount struct {
+	UserID         string  `json:"userId" validate:"required,userid"`
+	Name           string  `json:"name" validate:"required,max=100"`
+	Type           string  `json:"type" validate:"required,oneof=cash card bank savings credit other"`
+	Currency       string  `json:"currency" validate:"required,iso4217"`
+	OpeningBalance float64 `json:"openingBalance" validate:"gte=-1000000000,lte=1000000000"`
+}
+
+type UpdateAccount struct {
+	ID     string  `json:"accountId" validate:"required,mongodb"`
+	UserID string  `json:"userId" validate:"required,userid"`
+	Name   *string `json:"name" validate:"omitempty,min=1,max=100"`
+	Type   *string `json:"type" validate:"omitempty,oneof=cash card bank savings credit other"`
+}
+
+type AccountKey struct {
+	ID     string `json:"accountId" validate:"required,mongodb"`
+	UserID string `json:"userId" validate:"required,userid"`
+}
+
+type CreateTransfer struct {
+	UserID        string  `json:"userId" validate:"required,userid"`
+	FromAccountID string  `json:"fromAccountId" validate:"required,mongodb"`
+	ToAccountID   string  `json:"toAccountId" validate:"required,mongodb,nefield=FromAccountID"`
+	Amount        float64 `json:"amount" validate:"required,gt=0,lte=1000000000"`
+	Note          string  `json:"note" validate:"max=200"`
+	Date          *string `json:"date" validate:"omitempty,datetime=2006-01-02T15:04:05"`
+}
+
+type Transfer struct {
+	ID           string
+	OutgoingTxID string
+	IncomingTxID string
+}
+
+type AccountBalance struct {
+	AccountID string
+	Currency  string
+	AsOf      time.Time
+	Balance   float64
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type Attachment struct {
+	ID          string
+	UserID      string
+	TxID        string
+	FileName    string
+	ContentType string
+	Size        int64
+	SHA256      string
+	UploadedAt  time.Time
+}
+
+type UploadAttachment struct {
+	UserID      string `json:"userId" validate:"required,userid"`
+	TxID        string `json:"txId" validate:"required,mongodb"`
+	FileName    string `json:"fileName" validate:"required,max=255"`
+	ContentType string `json:"contentType" validate:"required,max=100"`
+	SHA256      string `json:"sha256" validate:"required,len=64,hexadecimal"`
+}
+
+type AttachmentKey struct {
+	ID     string `json:"attachmentId" validate:"required,mongodb"`
+	UserID string `json:"userId" validate:"required,userid"`
+}
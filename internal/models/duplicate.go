@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+const AuditMerge = "merge"
+
+type FindDuplicates struct {
+	UserID string `json:"userId" validate:"required,userid"`
+	// AmountTolerance is the largest cost difference within a cluster; zero
+	// means equal to the cent.
+	AmountTolerance float64 `json:"amountTolerance" validate:"gte=0,lte=1000000"`
+	WindowMinutes   int     `json:"windowMinutes" validate:"gte=0,lte=10080"`
+	NameSimilarity  float64 `json:"nameSimilarity" validate:"gte=0,lte=1"`
+}
+
+type DuplicateCluster struct {
+	Transactions []Transaction
+	Similarity   float64
+}
+
+type MergeTransactions struct {
+	UserID       string   `json:"userId" validate:"required,userid"`
+	KeepID       string   `json:"keepId" validate:"required,mongodb"`
+	MergeIDs     []string `json:"mergeIds" validate:"required,min=1,max=50,unique,dive,mongodb"`
+	CombineTags  bool     `json:"combineTags"`
+	CombineNotes bool     `json:"combineNotes"`
+}
+
+type AuditEntry struct {
+	ID            string        `bson:"_id,omitempty"`
+	UserID        string        `bson:"user_id"`
+	Action        string        `bson:"action"`
+	TransactionID string        `bson:"transaction_id"`
+	RelatedIDs    []string      `bson:"related_ids,omitempty"`
+	Before        []Transaction `bson:"before,omitempty"`
+	CreatedAt     time.Time     `bson:"created_at"`
+}
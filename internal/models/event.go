@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+const (
+	EventTransactionCreated = "transaction.created"
+	EventTransactionUpdated = "transaction.updated"
+	EventTransactionDeleted = "transaction.deleted"
+	EventTransactionAnomaly = "transaction.anomaly"
+)
+
+// EventDead marks an event the relay gave up on after its last attempt.
+const EventDead = "dead"
+
+type Event struct {
+	ID            string       `bson:"_id,omitempty" json:"id"`
+	Type          string       `bson:"type" json:"type"`
+	UserID        string       `bson:"user_id" json:"userId"`
+	TransactionID string       `bson:"transaction_id" json:"transactionId"`
+	Transaction   *Transaction `bson:"transaction,omitempty" json:"transaction,omitempty"`
+	OccurredAt    time.Time    `bson:"occurred_at" json:"occurredAt"`
+	PublishedAt   *time.Time   `bson:"published_at,omitempty" json:"-"`
+	Attempts      int          `bson:"attempts" json:"-"`
+	LastError     string       `bson:"last_error,omitempty" json:"-"`
+	NextAttemptAt *time.Time   `bson:"next_attempt_at,omitempty" json:"-"`
+	Status        string       `bson:"status,omitempty" json:"-"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+type ForecastSpending struct {
+	UserID string `json:"userId" validate:"required,userid"`
+	// AsOf splits the period into spent and projected days; defaults to now.
+	AsOf          string `json:"asOf" validate:"omitempty,datetime=2006-01-02"`
+	LookbackWeeks int    `json:"lookbackWeeks" validate:"gte=0,lte=52"`
+}
+
+type Budget struct {
+	Category string  `json:"category"`
+	Limit    float64 `json:"limit"`
+}
+
+type CategoryForecast struct {
+	Category  string
+	Spent     float64
+	Recurring float64
+	Projected float64
+	Low       float64
+	High      float64
+	Budget    *float64
+}
+
+type RecurringItem struct {
+	Name     string
+	Category string
+	Amount   float64
+	Due      time.Time
+}
+
+type SpendingForecast struct {
+	StartDate  time.Time
+	EndDate    time.Time
+	AsOf       time.Time
+	Categories []CategoryForecast
+	Recurring  []RecurringItem
+	Total      CategoryForecast
+}
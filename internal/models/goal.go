@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+const GoalCategory = "savings"
+
+// Goal is a savings target. Progress is the balance of the linked account
+// or, for a tag goal, the sum of contributions carrying the tag.
+type Goal struct {
+	ID        string    `bson:"_id,omitempty"`
+	UserID    string    `bson:"user_id"`
+	Name      string    `bson:"name"`
+	Target    float64   `bson:"target"`
+	Deadline  time.Time `bson:"deadline"`
+	AccountID string    `bson:"account_id,omitempty"`
+	Tag       string    `bson:"tag,omitempty"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+type CreateGoal struct {
+	// ID is set when updating an existing goal.
+	ID        string  `json:"goalId" validate:"omitempty,mongodb"`
+	UserID    string  `json:"userId" validate:"required,userid"`
+	Name      string  `json:"name" validate:"required,max=100"`
+	Target    float64 `json:"target" validate:"gt=0,lte=1000000000"`
+	Deadline  string  `json:"deadline" validate:"required,datetime=2006-01-02"`
+	AccountID string  `json:"accountId" validate:"required_without=Tag,excluded_with=Tag,omitempty,mongodb"`
+	Tag       string  `json:"tag" validate:"required_without=AccountID,omitempty,min=1,max=30"`
+}
+
+type GoalKey struct {
+	ID     string `json:"goalId" validate:"required,mongodb"`
+	UserID string `json:"userId" validate:"required,userid"`
+}
+
+type GoalContribution struct {
+	GoalID string `json:"goalId" validate:"required,mongodb"`
+	UserID string `json:"userId" validate:"required,userid"`
+	// Amount is negative for a withdrawal.
+	Amount float64 `json:"amount" validate:"required,ne=0,gte=-1000000000,lte=1000000000"`
+	Date   *string `json:"date" validate:"omitempty,datetime=2006-01-02T15:04:05"`
+	Note   string  `json:"note" validate:"max=500"`
+	// FromAccountID is where the money for an account goal comes from, or
+	// goes back to on a withdrawal. Tag goals move no money.
+	FromAccountID string `json:"fromAccountId" validate:"omitempty,mongodb"`
+}
+
+type GoalProgress struct {
+	Goal
+	Saved     float64
+	Remaining float64
+	// RequiredMonthly is what still has to be saved per month to reach the
+	// target by the deadline.
+	RequiredMonthly float64
+	// Expected is where a steady saver would be by now.
+	Expected float64
+	OnTrack  bool
+	Achieved bool
+}
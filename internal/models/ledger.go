@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+// Ledger is a shared book of transactions. Its transactions keep their
+// author in UserID and carry the ledger's ID in LedgerID.
+type Ledger struct {
+	ID        string         `bson:"_id,omitempty"`
+	Name      string         `bson:"name"`
+	OwnerID   string         `bson:"owner_id"`
+	Members   []LedgerMember `bson:"members"`
+	Invites   []LedgerInvite `bson:"invites"`
+	CreatedAt time.Time      `bson:"created_at"`
+}
+
+type LedgerMember struct {
+	UserID   string    `bson:"user_id"`
+	Role     string    `bson:"role"`
+	JoinedAt time.Time `bson:"joined_at"`
+}
+
+type LedgerInvite struct {
+	UserID    string    `bson:"user_id"`
+	Role      string    `bson:"role"`
+	InvitedBy string    `bson:"invited_by"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+func (l Ledger) Member(userID string) (LedgerMember, bool) {
+	for _, m := range l.Members {
+		if m.UserID == userID {
+			return m, true
+		}
+	}
+	return LedgerMember{}, false
+}
+
+func (l Ledger) Invite(userID string) (LedgerInvite, bool) {
+	for _, i := range l.Invites {
+		if i.UserID == userID {
+			return i, true
+		}
+	}
+	return LedgerInvite{}, false
+}
+
+type CreateLedger struct {
+	UserID string `json:"userId" validate:"required,userid"`
+	Name   string `json:"name" validate:"required,max=100"`
+}
+
+type LedgerKey struct {
+	ID     string `json:"ledgerId" validate:"required,mongodb"`
+	UserID string `json:"userId" validate:"required,userid"`
+}
+
+type InviteMember struct {
+	LedgerID string `json:"ledgerId" validate:"required,mongodb"`
+	UserID   string `json:"userId" validate:"required,userid"`
+	MemberID string `json:"memberId" validate:"required,userid"`
+	Role     string `json:"role" validate:"required,oneof=editor viewer"`
+}
+
+// RemoveMember removes a member or withdraws a pending invite. Members may
+// remove themselves; removing others takes the owner.
+type RemoveMember struct {
+	LedgerID string `json:"ledgerId" validate:"required,mongodb"`
+	UserID   string `json:"userId" validate:"required,userid"`
+	MemberID string `json:"memberId" validate:"required,userid"`
+}
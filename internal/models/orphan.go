@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+const (
+	OrphanArchive = "archive"
+	OrphanPurge   = "purge"
+	// OrphanPending is the action of a user not missing for long enough yet.
+	OrphanPending = "pending"
+)
+
+type ReconcileOrphans struct {
+	// Confirm applies the policy; without it the call is a dry run.
+	Confirm bool   `json:"confirm"`
+	Policy  string `json:"policy" validate:"omitempty,oneof=archive purge"`
+}
+
+type OrphanedUser struct {
+	UserID       string
+	Transactions int64
+	// Action is the policy applied, or the one that would be in a dry run;
+	// OrphanPending until the user has been missing long enough.
+	Action string
+	// FirstSeen is when the user was first found missing.
+	FirstSeen time.Time
+	Archive   string
+	Error     string
+}
+
+type OrphanReport struct {
+	DryRun       bool
+	Policy       string
+	CheckedUsers int
+	// Unverified counts users the user service could not be asked about;
+	// they are left alone until a later run.
+	Unverified int
+	Orphans    []OrphanedUser
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
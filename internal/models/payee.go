@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type Payee struct {
+	ID     string `bson:"_id,omitempty"`
+	UserID string `bson:"user_id"`
+	Name   string `bson:"name"`
+	// Aliases are normalized name keys that link transactions to the payee.
+	Aliases   []string  `bson:"aliases"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+type MergePayees struct {
+	UserID    string   `json:"userId" validate:"required,userid"`
+	TargetID  string   `json:"targetId" validate:"required,mongodb"`
+	SourceIDs []string `json:"sourceIds" validate:"required,min=1,max=50,unique,dive,mongodb"`
+	// Name renames the target when set.
+	Name string `json:"name" validate:"max=100"`
+}
+
+type PayeeTotal struct {
+	PayeeID string  `bson:"_id"`
+	Name    string  `bson:"-"`
+	Total   float64 `bson:"total"`
+	Count   int     `bson:"count"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Stores erased for a user, in the order they are processed.
+const (
+	StoreAttachments    = "attachments"
+	StoreTransactions   = "transactions"
+	StoreAccounts       = "accounts"
+	StoreGoals          = "goals"
+	StoreLedgers        = "ledgers"
+	StorePayees         = "payees"
+	StoreRules          = "rules"
+	StoreCategoryModels = "category_models"
+	StoreWebhooks       = "webhooks"
+	StoreDeliveries     = "webhook_deliveries"
+	StoreStatements     = "statements"
+	StoreAudit          = "audit"
+	StoreOutbox         = "outbox"
+	StoreSharedExpenses = "shared_expenses"
+	StoreGroups         = "groups"
+)
+
+type ErasureStep struct {
+	Store       string    `bson:"store" json:"store"`
+	Deleted     int64     `bson:"deleted" json:"deleted"`
+	CompletedAt time.Time `bson:"completed_at" json:"completedAt"`
+}
+
+// Erasure tracks an erasure so an interrupted one resumes where it stopped.
+// Once completed it is the receipt: Digest is the hex SHA-256 of its JSON
+// encoding, which leaves the digest out.
+type Erasure struct {
+	UserID      string        `bson:"_id" json:"userId"`
+	StartedAt   time.Time     `bson:"started_at" json:"startedAt"`
+	CompletedAt *time.Time    `bson:"completed_at,omitempty" json:"completedAt,omitempty"`
+	Steps       []ErasureStep `bson:"steps" json:"steps"`
+	// AnonymousID replaces the user in shared expenses and settlements. It
+	// is dropped once the erasure completes.
+	AnonymousID string `bson:"anonymous_id,omitempty" json:"-"`
+	Digest      string `bson:"digest,omitempty" json:"-"`
+}
+
+func (e Erasure) Done(store string) bool {
+	for _, step := range e.Steps {
+		if step.Store == store {
+			return true
+		}
+	}
+	return false
+}
+
+type ExportManifest struct {
+	UserID     string         `json:"userId"`
+	ExportedAt time.Time      `json:"exportedAt"`
+	Files      map[string]int `json:"files"`
+}
@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+type Rule struct {
+	ID         string         `bson:"_id,omitempty"`
+	UserID     string         `bson:"user_id"`
+	Name       string         `bson:"name"`
+	Priority   int            `bson:"priority"`
+	Enabled    bool           `bson:"enabled"`
+	Conditions RuleConditions `bson:"conditions"`
+	Actions    RuleActions    `bson:"actions"`
+	CreatedAt  time.Time      `bson:"created_at"`
+}
+
+// RuleConditions must all hold for a rule to match; unset ones are ignored.
+type RuleConditions struct {
+	NameContains string   `bson:"name_contains,omitempty" json:"nameContains" validate:"max=100"`
+	NamePattern  string   `bson:"name_pattern,omitempty" json:"namePattern" validate:"max=200"`
+	MinAmount    *float64 `bson:"min_amount,omitempty" json:"minAmount" validate:"omitempty,gte=0"`
+	MaxAmount    *float64 `bson:"max_amount,omitempty" json:"maxAmount" validate:"omitempty,gte=0"`
+	Weekdays     []int    `bson:"weekdays,omitempty" json:"weekdays" validate:"max=7,dive,gte=0,lte=6"`
+	AccountID    string   `bson:"account_id,omitempty" json:"accountId" validate:"omitempty,mongodb"`
+}
+
+type RuleActions struct {
+	Category string   `bson:"category,omitempty" json:"category" validate:"omitempty,max=50,category"`
+	Tags     []string `bson:"tags,omitempty" json:"tags" validate:"max=20,dive,min=1,max=30"`
+}
+
+type CreateRule struct {
+	ID         string         `json:"ruleId" validate:"omitempty,mongodb"`
+	UserID     string         `json:"userId" validate:"required,userid"`
+	Name       string         `json:"name" validate:"required,max=100"`
+	Priority   int            `json:"priority" validate:"gte=-1000,lte=1000"`
+	Enabled    bool           `json:"enabled"`
+	Conditions RuleConditions `json:"conditions"`
+	Actions    RuleActions    `json:"actions"`
+}
+
+type RuleKey struct {
+	ID     string `json:"ruleId" validate:"required,mongodb"`
+	UserID string `json:"userId" validate:"required,userid"`
+}
+
+type ReapplyRules struct {
+	UserID string `json:"userId" validate:"required,userid"`
+	DryRun bool   `json:"dryRun"`
+	// OverwriteManual lets rules replace categories the user chose.
+	OverwriteManual bool `json:"overwriteManual"`
+}
+
+type RuleChange struct {
+	TxID        string
+	Name        string
+	Date        time.Time
+	OldCategory string
+	NewCategory string
+	OldTags     []string
+	NewTags     []string
+	RuleIDs     []string
+}
+
+// ReapplyResult reports the changes found. When Error stopped the run,
+// Changes holds only the Updated ones already written and Applied is false.
+type ReapplyResult struct {
+	Scanned int
+	Changes []RuleChange
+	Applied bool
+	Updated int
+	Error   string
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	SplitEqual      = "equal"
+	SplitPercentage = "percentage"
+	SplitExact      = "exact"
+)
+
+// Group is a set of users sharing expenses. Version changes with every
+// expense and settle-up, so a settle-up only applies to the balances it was
+// computed from.
+type Group struct {
+	ID             string           `bson:"_id,omitempty"`
+	Name           string           `bson:"name"`
+	OwnerID        string           `bson:"owner_id"`
+	Members        []string         `bson:"members"`
+	Version        int64            `bson:"version"`
+	LastSettlement *GroupSettlement `bson:"last_settlement,omitempty"`
+	CreatedAt      time.Time        `bson:"created_at"`
+}
+
+func (g Group) IsMember(userID string) bool {
+	return slices.Contains(g.Members, userID)
+}
+
+// GroupSettlement remembers the last settle-up so a repeated call for the
+// same version returns it instead of recording it again.
+type GroupSettlement struct {
+	Version int64    `bson:"version"`
+	UserID  string   `bson:"user_id"`
+	IDs     []string `bson:"ids"`
+}
+
+type CreateGroup struct {
+	UserID    string   `json:"userId" validate:"required,userid"`
+	Name      string   `json:"name" validate:"required,max=100"`
+	MemberIDs []string `json:"memberIds" validate:"max=50,unique,dive,userid"`
+}
+
+type AddGroupMember struct {
+	GroupID  string `json:"groupId" validate:"required,mongodb"`
+	UserID   string `json:"userId" validate:"required,userid"`
+	MemberID string `json:"memberId" validate:"required,userid"`
+}
+
+type CreateSharedExpense struct {
+	GroupID      string              `json:"groupId" validate:"required,mongodb"`
+	UserID       string              `json:"userId" validate:"required,userid"`
+	PayerID      string              `json:"payerId" validate:"required,userid"`
+	Name         string              `json:"name" validate:"required,max=100"`
+	Total        float64             `json:"total" validate:"required,gt=0,lte=1000000000"`
+	SplitType    string              `json:"splitType" validate:"required,oneof=equal percentage exact"`
+	Participants []CreateParticipant `json:"participants" validate:"required,min=1,max=50,unique=UserID,dive"`
+	Date         *string             `json:"date" validate:"omitempty,datetime=2006-01-02T15:04:05"`
+}
+
+type CreateParticipant struct {
+	UserID string  `json:"userId" validate:"required,userid"`
+	Value  float64 `json:"value" validate:"gte=0"`
+}
+
+type SharedExpense struct {
+	ID        string        `bson:"_id,omitempty"`
+	GroupID   string        `bson:"group_id"`
+	PayerID   string        `bson:"payer_id"`
+	Name      string        `bson:"name"`
+	Total     float64       `bson:"total"`
+	SplitType string        `bson:"split_type"`
+	Shares    []Participant `bson:"shares"`
+	Date      time.Time     `bson:"date"`
+}
+
+type Participant struct {
+	UserID string  `bson:"user_id"`
+	Amount float64 `bson:"amount"`
+}
+
+// Settlement is a payment between two members, stored as a pair of
+// settlement transactions linked by their TransferID, which is its ID.
+type Settlement struct {
+	ID         string
+	GroupID    string
+	FromUserID string
+	ToUserID   string
+	Amount     float64
+	Date       time.Time
+}
+
+// Legs returns the transactions recording the settlement for its payer and
+// its recipient.
+func (s Settlement) Legs(name string) (outgoing, incoming Transaction) {
+	leg := func(userID, counterparty, kind string) Transaction {
+		return Transaction{
+			UserID:         userID,
+			Category:       SettlementCategory,
+			Name:           name,
+			Cost:           s.Amount,
+			Date:           s.Date,
+			Kind:           kind,
+			TransferID:     s.ID,
+			GroupID:        s.GroupID,
+			CounterpartyID: counterparty,
+		}
+	}
+	return leg(s.FromUserID, s.ToUserID, KindSettlementOut), leg(s.ToUserID, s.FromUserID, KindSettlementIn)
+}
+
+type Balance struct {
+	UserID string
+	Net    float64
+}
+
+type Debt struct {
+	FromUserID string
+	ToUserID   string
+	Amount     float64
+}
+
+type GroupKey struct {
+	GroupID string `json:"groupId" validate:"required,mongodb"`
+	UserID  string `json:"userId" validate:"required,userid"`
+}
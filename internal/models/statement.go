@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+const (
+	FormatHTML     = "html"
+	FormatMarkdown = "markdown"
+	FormatPDF      = "pdf"
+)
+
+type GenerateStatement struct {
+	UserID string `json:"userId" validate:"required,userid"`
+	// Month is the statement period; defaults to the previous month.
+	Month  string `json:"month" validate:"omitempty,datetime=2006-01"`
+	Format string `json:"format" validate:"omitempty,oneof=html markdown pdf"`
+}
+
+type CategoryComparison struct {
+	Category string
+	Total    float64
+	Previous float64
+	Count    int
+}
+
+// Change is the relative change against the previous period, or nil when
+// nothing was spent in the category then.
+func (c CategoryComparison) Change() *float64 {
+	if c.Previous == 0 {
+		return nil
+	}
+	change := (c.Total - c.Previous) / c.Previous
+	return &change
+}
+
+type Statement struct {
+	UserID        string
+	UserName      string
+	StartDate     time.Time
+	EndDate       time.Time
+	Total         float64
+	PreviousTotal float64
+	Categories    []CategoryComparison
+	TopExpenses   []Transaction
+	Transactions  []Transaction
+	GeneratedAt   time.Time
+}
+
+type Document struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// CategoryModel holds the naive Bayes counts learned from a user's
+// transactions, one document per user.
+type CategoryModel struct {
+	UserID     string                   `bson:"_id"`
+	Docs       int                      `bson:"docs"`
+	Categories map[string]CategoryStats `bson:"categories"`
+	TrainedAt  time.Time                `bson:"trained_at"`
+}
+
+type CategoryStats struct {
+	Docs      int            `bson:"docs"`
+	Tokens    int            `bson:"tokens"`
+	Words     map[string]int `bson:"words"`
+	LogCost   float64        `bson:"log_cost"`
+	LogCostSq float64        `bson:"log_cost_sq"`
+}
+
+type SuggestCategory struct {
+	UserID string  `json:"userId" validate:"required,userid"`
+	Name   string  `json:"name" validate:"required,max=100"`
+	Cost   float64 `json:"cost" validate:"gte=0,lte=1000000000"`
+	Limit  int     `json:"limit" validate:"gte=0,lte=20"`
+}
+
+type CategorySuggestion struct {
+	Category   string
+	Confidence float64
+}
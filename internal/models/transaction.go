@@ -1,22 +1,84 @@
 package models
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 type CreateTransaction struct {
-	Category string
-	UserID   string  `validate:"required"`
-	Name     string  `validate:"required"`
-	Cost     float64 `validate:"required"`
-	Date     *string
+	Category  string   `json:"category" validate:"omitempty,max=50,category"`
+	UserID    string   `json:"userId" validate:"required,userid"`
+	Name      string   `json:"name" validate:"required,max=100"`
+	Cost      float64  `json:"cost" validate:"gte=0,lte=1000000000"`
+	Date      *string  `json:"date" validate:"omitempty,datetime=2006-01-02T15:04:05"`
+	Splits    []Split  `json:"splits" validate:"omitempty,max=50,dive"`
+	AccountID string   `json:"accountId" validate:"omitempty,mongodb"`
+	Tags      []string `json:"tags" validate:"max=20,dive,min=1,max=30"`
+	Note      string   `json:"note" validate:"max=500"`
+	LedgerID  string   `json:"ledgerId" validate:"omitempty,mongodb"`
+}
+
+type Split struct {
+	Category string  `bson:"category" json:"category" validate:"required,max=50,category"`
+	Amount   float64 `bson:"amount" json:"amount" validate:"gt=0,lte=1000000000"`
+	Note     string  `bson:"note,omitempty" json:"note" validate:"max=200"`
 }
 
 type Transaction struct {
-	ID       string    `bson:"_id,omitempty"`
-	UserID   string    `bson:"user_id"`
-	Category string    `bson:"category"`
-	Name     string    `bson:"name"`
-	Cost     float64   `bson:"cost"`
-	Date     time.Time `bson:"date"`
+	ID         string    `bson:"_id,omitempty"`
+	UserID     string    `bson:"user_id"`
+	Category   string    `bson:"category"`
+	Name       string    `bson:"name"`
+	Cost       float64   `bson:"cost"`
+	Date       time.Time `bson:"date"`
+	Splits     []Split   `bson:"splits,omitempty"`
+	AccountID  string    `bson:"account_id,omitempty"`
+	Kind       string    `bson:"kind,omitempty"`
+	TransferID string    `bson:"transfer_id,omitempty"`
+	Tags       []string  `bson:"tags,omitempty"`
+	Note       string    `bson:"note,omitempty"`
+	PayeeID    string    `bson:"payee_id,omitempty"`
+	GoalID     string    `bson:"goal_id,omitempty"`
+	LedgerID   string    `bson:"ledger_id,omitempty"`
+	// GroupID and CounterpartyID are set on settlement legs.
+	GroupID        string     `bson:"group_id,omitempty"`
+	CounterpartyID string     `bson:"counterparty_id,omitempty"`
+	Anomaly        *Anomaly   `bson:"anomaly,omitempty"`
+	DeletedAt      *time.Time `bson:"deleted_at,omitempty"`
+	MergedInto     string     `bson:"merged_into,omitempty"`
+	// CategorySetBy records who chose the category; reapplied rules leave
+	// one the user set alone unless asked otherwise.
+	CategorySetBy string `bson:"category_set_by,omitempty"`
+}
+
+const (
+	CategoryByUser = "user"
+	CategoryByRule = "rule"
+)
+
+func (t Transaction) IsTransfer() bool {
+	return t.Kind == KindTransferOut || t.Kind == KindTransferIn
+}
+
+func (t Transaction) IsSettlement() bool {
+	return t.Kind == KindSettlementOut || t.Kind == KindSettlementIn
+}
+
+// AccountEffect is how much the transaction changes its account's balance.
+func (t Transaction) AccountEffect() float64 {
+	switch {
+	case slices.Contains(InflowKinds, t.Kind):
+		return t.Cost
+	case slices.Contains(OutflowKinds, t.Kind):
+		return -t.Cost
+	}
+	return 0
+}
+
+// IsSpending reports whether the transaction counts toward spending;
+// transfers, settlements and goal contributions only move money.
+func (t Transaction) IsSpending() bool {
+	return !t.IsTransfer() && !t.IsSettlement() && t.Kind != KindContribution
 }
 
 type TimeFrame struct {
@@ -25,16 +87,72 @@ type TimeFrame struct {
 }
 
 type CreateTimeFrame struct {
-	StartDate string
-	EndDate   string
+	StartDate string `json:"startDate" validate:"omitempty,datetime=2006-01-02"`
+	EndDate   string `json:"endDate" validate:"omitempty,datetime=2006-01-02"`
 }
 
 type UpdateTransaction struct {
-	ID       string
-	UserID   string
-	Category *string
-	Name     *string
-	Cost     *float64
-	Date     *string
-	Time     *string
+	ID        string   `json:"txId" validate:"required,mongodb"`
+	UserID    string   `json:"userId" validate:"required,userid"`
+	Category  *string  `json:"category" validate:"omitempty,max=50,category"`
+	Name      *string  `json:"name" validate:"omitempty,min=1,max=100"`
+	Cost      *float64 `json:"cost" validate:"omitempty,gte=0,lte=1000000000"`
+	Date      *string  `json:"date" validate:"omitempty,datetime=2006-01-02"`
+	Time      *string  `json:"time" validate:"omitempty,datetime=15:04"`
+	Splits    *[]Split `json:"splits" validate:"omitempty,max=50,dive"`
+	AccountID *string  `json:"accountId" validate:"omitempty,mongodb|len=0"`
+	Note      *string  `json:"note" validate:"omitempty,max=500"`
+}
+
+type TransactionKey struct {
+	ID     string `json:"txId" validate:"required,mongodb"`
+	UserID string `json:"userId" validate:"required,userid"`
+}
+
+type UserKey struct {
+	UserID string `json:"userId" validate:"required,userid"`
+}
+
+type CategoryTotal struct {
+	Category string  `bson:"_id"`
+	Total    float64 `bson:"total"`
+	Count    int     `bson:"count"`
+}
+
+type CashFlowRequest struct {
+	UserID         string   `json:"userId" validate:"required,userid"`
+	AccountID      string   `json:"accountId" validate:"omitempty,mongodb"`
+	OpeningBalance *float64 `json:"openingBalance" validate:"omitempty,gte=-1000000000,lte=1000000000"`
+}
+
+type CashFlowEntry struct {
+	Transaction `bson:",inline"`
+	Amount      float64 `bson:"amount"`
+	Balance     float64 `bson:"balance"`
+}
+
+type CashFlowStatement struct {
+	AccountID      string
+	OpeningBalance float64
+	ClosingBalance float64
+	Inflow         float64
+	Outflow        float64
+	Entries        []CashFlowEntry
+}
+
+// Anomaly is how far a transaction's cost was from the user's norm when it
+// was created.
+type Anomaly struct {
+	Score   float64  `bson:"score"`
+	Median  float64  `bson:"median"`
+	MAD     float64  `bson:"mad"`
+	Samples int      `bson:"samples"`
+	Reasons []string `bson:"reasons,omitempty"`
+	Flagged bool     `bson:"flagged"`
+}
+
+type ListAnomalies struct {
+	UserID   string  `json:"userId" validate:"required,userid"`
+	MinScore float64 `json:"minScore" validate:"gte=0,lte=1000"`
+	Limit    int     `json:"limit" validate:"gte=0,lte=500"`
 }
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+// Webhook holds its signing secret encrypted. Secret is only set on the
+// webhook returned by its creation, when it was generated.
+type Webhook struct {
+	ID           string    `bson:"_id,omitempty"`
+	UserID       string    `bson:"user_id"`
+	URL          string    `bson:"url"`
+	Secret       string    `bson:"-"`
+	SealedSecret string    `bson:"sealed_secret"`
+	EventTypes   []string  `bson:"event_types"`
+	CreatedAt    time.Time `bson:"created_at"`
+}
+
+// Subscribed reports whether the webhook wants events of the given type; an
+// empty list subscribes to all of them.
+func (w Webhook) Subscribed(eventType string) bool {
+	if len(w.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range w.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type CreateWebhook struct {
+	UserID     string   `json:"userId" validate:"required,userid"`
+	URL        string   `json:"url" validate:"required,http_url,max=2048"`
+	Secret     string   `json:"secret" validate:"omitempty,min=16,max=256"`
+	EventTypes []string `json:"eventTypes" validate:"max=4,dive,oneof=transaction.created transaction.updated transaction.deleted transaction.anomaly"`
+}
+
+type WebhookKey struct {
+	ID     string `json:"webhookId" validate:"required,mongodb"`
+	UserID string `json:"userId" validate:"required,userid"`
+}
+
+type WebhookDelivery struct {
+	ID            string     `bson:"_id,omitempty"`
+	WebhookID     string     `bson:"webhook_id"`
+	UserID        string     `bson:"user_id"`
+	EventID       string     `bson:"event_id"`
+	EventType     string     `bson:"event_type"`
+	Payload       []byte     `bson:"payload"`
+	Status        string     `bson:"status"`
+	Attempts      int        `bson:"attempts"`
+	ResponseCode  int        `bson:"response_code,omitempty"`
+	LastError     string     `bson:"last_error,omitempty"`
+	CreatedAt     time.Time  `bson:"created_at"`
+	NextAttemptAt time.Time  `bson:"next_attempt_at"`
+	DeliveredAt   *time.Time `bson:"delivered_at,omitempty"`
+}
+
+type DeliveryFilter struct {
+	UserID    string `json:"userId" validate:"required,userid"`
+	WebhookID string `json:"webhookId" validate:"required,mongodb"`
+	Status    string `json:"status" validate:"omitempty,oneof=pending delivered dead"`
+	Limit     int    `json:"limit" validate:"gte=0,lte=500"`
+}
+
+type DeliveryKey struct {
+	ID     string `json:"deliveryId" validate:"required,mongodb"`
+	UserID string `json:"userId" validate:"required,userid"`
+}
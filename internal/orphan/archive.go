@@ -0,0 +1,41 @@
+package orphan
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileArchiver writes archives to <dir>/<userID>-<timestamp>.zip.
+type FileArchiver struct {
+	dir string
+}
+
+func NewFileArchiver(dir string) *FileArchiver {
+	return &FileArchiver{dir: dir}
+}
+
+func (a *FileArchiver) Archive(ctx context.Context, userID string, write func(w io.Writer) error) (string, error) {
+	if err := os.MkdirAll(a.dir, 0o750); err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp(a.dir, ".archive-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	name := filepath.Join(a.dir, filepath.Base(userID)+"-"+time.Now().UTC().Format("20060102T150405Z")+".zip")
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
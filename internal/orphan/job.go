@@ -0,0 +1,71 @@
+package orphan
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+type Reconciler interface {
+	Reconcile(ctx context.Context, dryRun bool, policy string) (*models.OrphanReport, error)
+}
+
+type JobConfig struct {
+	Interval time.Duration
+	DryRun   bool
+	Policy   string
+}
+
+// Job periodically reconciles the users that own transactions against the
+// user service.
+type Job struct {
+	reconciler Reconciler
+	cfg        JobConfig
+}
+
+func NewJob(reconciler Reconciler, cfg JobConfig) *Job {
+	return &Job{reconciler: reconciler, cfg: cfg}
+}
+
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		j.runOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *Job) runOnce(ctx context.Context) {
+	report, err := j.reconciler.Reconcile(ctx, j.cfg.DryRun, j.cfg.Policy)
+	if err != nil {
+		log.Printf("orphan reconciliation failed: %v", err)
+		return
+	}
+	mode := ""
+	if report.DryRun {
+		mode = " (dry run)"
+	}
+	log.Printf("orphan reconciliation%s: checked %d users, %d orphaned, %d unverified",
+		mode, report.CheckedUsers, len(report.Orphans), report.Unverified)
+	for _, o := range report.Orphans {
+		switch {
+		case o.Action == models.OrphanPending:
+			log.Printf("orphaned user %s (%d transactions): missing since %s, awaiting confirmation", o.UserID, o.Transactions, o.FirstSeen.Format(time.RFC3339))
+		case o.Error != "":
+			log.Printf("orphaned user %s (%d transactions): %s failed: %s", o.UserID, o.Transactions, o.Action, o.Error)
+		case report.DryRun:
+			log.Printf("orphaned user %s (%d transactions): would %s", o.UserID, o.Transactions, o.Action)
+		case o.Archive != "":
+			log.Printf("orphaned user %s (%d transactions): archived to %s and purged", o.UserID, o.Transactions, o.Archive)
+		default:
+			log.Printf("orphaned user %s (%d transactions): purged", o.UserID, o.Transactions)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package payee
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	cardNumber = regexp.MustCompile(`\d(?:[ -]?\d){11,18}`)
+	maskedCard = regexp.MustCompile(`[*x]{2,}[ -]?\d{2,4}`)
+)
+
+// Normalizer reduces bank-imported transaction names to a payee key, so
+// "POS 1234 SILPO KYIV 22" and "SILPO #88" both become "silpo".
+type Normalizer struct {
+	prefixes map[string]bool
+	cities   map[string]bool
+}
+
+func NewNormalizer(prefixes, cities []string) *Normalizer {
+	return &Normalizer{prefixes: toSet(prefixes), cities: toSet(cities)}
+}
+
+func toSet(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[strings.ToLower(strings.TrimSpace(w))] = true
+	}
+	return set
+}
+
+// Key strips card numbers, tokens containing digits (terminal and store
+// IDs), leading payment prefixes and trailing city names. It returns an
+// empty string when nothing recognizable is left.
+func (n *Normalizer) Key(name string) string {
+	s := strings.ToLower(name)
+	s = maskedCard.ReplaceAllString(s, " ")
+	s = cardNumber.ReplaceAllString(s, " ")
+	var tokens []string
+	for _, t := range strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !strings.ContainsFunc(t, unicode.IsDigit) {
+			tokens = append(tokens, t)
+		}
+	}
+	for len(tokens) > 1 && n.prefixes[tokens[0]] {
+		tokens = tokens[1:]
+	}
+	for len(tokens) > 1 && n.cities[tokens[len(tokens)-1]] {
+		tokens = tokens[:len(tokens)-1]
+	}
+	return strings.Join(tokens, " ")
+}
+
+// DisplayName capitalizes each word of a key.
+func DisplayName(key string) string {
+	words := strings.Fields(key)
+	for i, w := range words {
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		words[i] = string(r)
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,33 @@
+package payee
+
+import "testing"
+
+func TestKey(t *testing.T) {
+	n := NewNormalizer([]string{"pos", "card ", "Paypal"}, []string{"kyiv", "lviv"})
+	tests := map[string]string{
+		"POS 1234 SILPO KYIV 22":         "silpo",
+		"SILPO #88":                      "silpo",
+		"CARD 4149 4393 1234 5678 ATB":   "atb",
+		"Paypal *Steam games **1234":     "steam games",
+		"Nova Poshta Lviv":               "nova poshta",
+		"KYIV":                           "kyiv",
+		"POS Kyiv":                       "kyiv",
+		"12345 678":                      "",
+		"Сільпо Київ":                    "сільпо київ",
+		"Uber   TRIP-help.uber.com 0042": "uber trip help uber com",
+	}
+	for name, want := range tests {
+		if got := n.Key(name); got != want {
+			t.Errorf("Key(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestDisplayName(t *testing.T) {
+	if got := DisplayName("nova poshta"); got != "Nova Poshta" {
+		t.Errorf("got %q", got)
+	}
+	if got := DisplayName("сільпо"); got != "Сільпо" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,252 @@
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/auth"
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RetryAfterHeader carries the seconds to wait on ResourceExhausted.
+const RetryAfterHeader = "retry-after"
+
+// forwardedForHeader is where the REST gateway passes on the address of the
+// HTTP client, appended to any X-Forwarded-For the client sent.
+const forwardedForHeader = "x-forwarded-for"
+
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+type Config struct {
+	Default Limit
+	// Methods overrides the default by method name, either the full gRPC
+	// name or just the last element.
+	Methods map[string]Limit
+	// IdleTTL is how long an unused bucket is kept.
+	IdleTTL time.Duration
+	// MaxBuckets caps the buckets kept; the least recently used one makes
+	// room for a new caller. Zero means no cap.
+	MaxBuckets int
+	// TrustedProxies are the peers, such as the REST gateway, whose
+	// x-forwarded-for metadata names the caller instead of the peer.
+	TrustedProxies []netip.Prefix
+}
+
+type bucket struct {
+	key      string
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// Limiter keeps a token bucket per caller and method.
+type Limiter struct {
+	cfg Config
+	mu  sync.Mutex
+	// order holds the buckets, most recently used first.
+	order   *list.List
+	buckets map[string]*list.Element
+	now     func() time.Time
+}
+
+func New(cfg Config) *Limiter {
+	return &Limiter{cfg: cfg, order: list.New(), buckets: map[string]*list.Element{}, now: time.Now}
+}
+
+// ParseMethods reads overrides written as "Method=rate:burst".
+func ParseMethods(entries []string) (map[string]Limit, error) {
+	methods := make(map[string]Limit, len(entries))
+	for _, entry := range entries {
+		name, spec, ok := strings.Cut(entry, "=")
+		rateSpec, burstSpec, ok2 := strings.Cut(spec, ":")
+		if !ok || !ok2 || name == "" {
+			return nil, fmt.Errorf("rate limit %q must look like Method=rate:burst", entry)
+		}
+		r, err := strconv.ParseFloat(rateSpec, 64)
+		if err != nil {
+			return nil, err
+		}
+		burst, err := strconv.Atoi(burstSpec)
+		if err != nil {
+			return nil, err
+		}
+		methods[name] = Limit{Rate: r, Burst: burst}
+	}
+	return methods, nil
+}
+
+// ParseTrustedProxies reads proxy addresses written as CIDR prefixes or
+// single IPs.
+func ParseTrustedProxies(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q must be an IP or a CIDR prefix", entry)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes, nil
+}
+
+// Allow takes a token for the caller's method. When none is left it returns
+// how long until one is.
+func (l *Limiter) Allow(caller, method string) (bool, time.Duration) {
+	limit := l.limit(method)
+	if limit.Rate <= 0 {
+		return true, 0
+	}
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+	b := l.bucket(caller+"|"+method, limit)
+	b.lastSeen = now
+	reservation := b.limiter.ReserveN(now, 1)
+	delay := reservation.DelayFrom(now)
+	if delay == 0 {
+		return true, 0
+	}
+	reservation.CancelAt(now)
+	return false, delay
+}
+
+func (l *Limiter) limit(method string) Limit {
+	if limit, ok := l.cfg.Methods[method]; ok {
+		return limit
+	}
+	if limit, ok := l.cfg.Methods[method[strings.LastIndex(method, "/")+1:]]; ok {
+		return limit
+	}
+	return l.cfg.Default
+}
+
+// bucket returns the key's bucket, most recently used from now on, creating
+// it when missing.
+func (l *Limiter) bucket(key string, limit Limit) *bucket {
+	if elem, ok := l.buckets[key]; ok {
+		l.order.MoveToFront(elem)
+		return elem.Value.(*bucket)
+	}
+	if l.cfg.MaxBuckets > 0 && l.order.Len() >= l.cfg.MaxBuckets {
+		l.remove(l.order.Back())
+	}
+	b := &bucket{key: key, limiter: rate.NewLimiter(rate.Limit(limit.Rate), max(limit.Burst, 1))}
+	l.buckets[key] = l.order.PushFront(b)
+	return b
+}
+
+// sweep drops the buckets idle for IdleTTL, the least recently used first.
+// A dropped bucket comes back full, which an idle caller would have had
+// anyway.
+func (l *Limiter) sweep(now time.Time) {
+	if l.cfg.IdleTTL <= 0 {
+		return
+	}
+	for elem := l.order.Back(); elem != nil && now.Sub(elem.Value.(*bucket).lastSeen) >= l.cfg.IdleTTL; elem = l.order.Back() {
+		l.remove(elem)
+	}
+}
+
+func (l *Limiter) remove(elem *list.Element) {
+	l.order.Remove(elem)
+	delete(l.buckets, elem.Value.(*bucket).key)
+}
+
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := l.check(ctx, l.caller(ctx), info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (l *Limiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := l.check(ss.Context(), l.caller(ss.Context()), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func (l *Limiter) check(ctx context.Context, caller, method string) error {
+	ok, wait := l.Allow(caller, method)
+	if ok {
+		return nil
+	}
+	seconds := int(math.Ceil(wait.Seconds()))
+	// The header is best effort; RetryInfo carries the same delay.
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RetryAfterHeader, strconv.Itoa(seconds)))
+	st := status.New(codes.ResourceExhausted, "rate limit exceeded, retry in "+strconv.Itoa(seconds)+"s")
+	detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(wait)})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// caller identifies who to limit: the authenticated subject, otherwise the
+// caller's address. Unauthenticated requests are never keyed by anything in
+// the request, which a caller could vary to get fresh buckets. The address
+// is the peer's, or for a trusted proxy the last x-forwarded-for entry,
+// which the proxy itself appended.
+func (l *Limiter) caller(ctx context.Context) string {
+	if claims, ok := auth.FromContext(ctx); ok {
+		return "user:" + claims.Subject
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "unknown"
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+	if l.trusted(host) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get(forwardedForHeader); len(values) > 0 {
+			entries := strings.Split(values[len(values)-1], ",")
+			if forwarded := strings.TrimSpace(entries[len(entries)-1]); forwarded != "" {
+				return "addr:" + forwarded
+			}
+		}
+	}
+	return "addr:" + host
+}
+
+func (l *Limiter) trusted(host string) bool {
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range l.cfg.TrustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
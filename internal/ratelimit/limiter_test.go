@@ -0,0 +1,195 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/auth"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// newTestLimiter returns a limiter on a clock that moves only when the
+// returned function advances it.
+func newTestLimiter(cfg Config) (*Limiter, func(time.Duration)) {
+	l := New(cfg)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+	return l, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestAllow(t *testing.T) {
+	l, advance := newTestLimiter(Config{
+		Default: Limit{Rate: 1, Burst: 2},
+		Methods: map[string]Limit{"GetTransactionList": {Rate: 0.5, Burst: 1}, "/svc/Free": {}},
+	})
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.Allow("addr:a", "/svc/AddTransaction"); !ok {
+			t.Fatalf("call %d within the burst was refused", i+1)
+		}
+	}
+	ok, wait := l.Allow("addr:a", "/svc/AddTransaction")
+	if ok || wait != time.Second {
+		t.Fatalf("over the burst: got %v, %s, want refused for 1s", ok, wait)
+	}
+	if ok, _ := l.Allow("addr:b", "/svc/AddTransaction"); !ok {
+		t.Error("another caller must have its own bucket")
+	}
+	advance(time.Second)
+	if ok, _ := l.Allow("addr:a", "/svc/AddTransaction"); !ok {
+		t.Error("a refused call must not use up the next token")
+	}
+
+	if ok, _ := l.Allow("addr:a", "/svc/GetTransactionList"); !ok {
+		t.Fatal("first call to an overridden method was refused")
+	}
+	if ok, wait := l.Allow("addr:a", "/svc/GetTransactionList"); ok || wait != 2*time.Second {
+		t.Errorf("override by short name: got %v, %s, want refused for 2s", ok, wait)
+	}
+	for i := 0; i < 10; i++ {
+		if ok, _ := l.Allow("addr:a", "/svc/Free"); !ok {
+			t.Fatal("a method with a zero rate must not be limited")
+		}
+	}
+}
+
+func TestBucketsAreCappedAndSwept(t *testing.T) {
+	l, advance := newTestLimiter(Config{Default: Limit{Rate: 0.001, Burst: 1}, IdleTTL: time.Minute, MaxBuckets: 2})
+	l.Allow("addr:a", "/svc/M")
+	advance(time.Second)
+	l.Allow("addr:b", "/svc/M")
+	advance(time.Second)
+	// a is now the most recently used, so c evicts b.
+	if ok, _ := l.Allow("addr:a", "/svc/M"); ok {
+		t.Fatal("a must still be limited")
+	}
+	l.Allow("addr:c", "/svc/M")
+	if len(l.buckets) != 2 || l.order.Len() != 2 {
+		t.Fatalf("kept %d buckets, want 2", len(l.buckets))
+	}
+	if _, ok := l.buckets["addr:b|/svc/M"]; ok {
+		t.Error("the least recently used bucket must be evicted")
+	}
+	if ok, _ := l.Allow("addr:a", "/svc/M"); ok {
+		t.Error("a recently used bucket must be kept")
+	}
+
+	advance(2 * time.Minute)
+	l.Allow("addr:d", "/svc/M")
+	if len(l.buckets) != 1 || l.order.Len() != 1 {
+		t.Errorf("kept %d buckets after they went idle, want only the new one", len(l.buckets))
+	}
+}
+
+func peerContext(addr string, md metadata.MD) context.Context {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(addr), Port: 40000}})
+	if md != nil {
+		ctx = metadata.NewIncomingContext(ctx, md)
+	}
+	return ctx
+}
+
+func TestCaller(t *testing.T) {
+	l := New(Config{TrustedProxies: []netip.Prefix{netip.MustParsePrefix("127.0.0.1/32")}})
+	forwarded := metadata.Pairs(forwardedForHeader, "203.0.113.7, 198.51.100.2")
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{
+			name: "authenticated subject",
+			ctx:  auth.NewContext(peerContext("192.0.2.1", nil), &auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "alice"}}),
+			want: "user:alice",
+		},
+		{name: "peer address", ctx: peerContext("192.0.2.1", nil), want: "addr:192.0.2.1"},
+		{name: "untrusted peer cannot forward", ctx: peerContext("192.0.2.1", forwarded), want: "addr:192.0.2.1"},
+		{name: "trusted proxy forwards the address it saw", ctx: peerContext("127.0.0.1", forwarded), want: "addr:198.51.100.2"},
+		{name: "trusted proxy without a forwarded address", ctx: peerContext("127.0.0.1", nil), want: "addr:127.0.0.1"},
+		{name: "no peer", ctx: context.Background(), want: "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := l.caller(tt.ctx); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+type userRequest struct{ userID string }
+
+func (r userRequest) GetUserId() string { return r.userID }
+
+func TestUnaryInterceptor(t *testing.T) {
+	l := New(Config{Default: Limit{Rate: 1, Burst: 1}})
+	intercept := l.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/svc/AddTransaction"}
+	handler := func(context.Context, interface{}) (interface{}, error) { return "ok", nil }
+	ctx := peerContext("192.0.2.1", nil)
+
+	if _, err := intercept(ctx, userRequest{"alice"}, info, handler); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	// naming another user in the request must not get a fresh bucket.
+	_, err := intercept(ctx, userRequest{"bob"}, info, handler)
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("second call: got %v, want ResourceExhausted", err)
+	}
+	var retry *errdetails.RetryInfo
+	for _, d := range st.Details() {
+		if r, ok := d.(*errdetails.RetryInfo); ok {
+			retry = r
+		}
+	}
+	if retry == nil || retry.RetryDelay.AsDuration() <= 0 {
+		t.Errorf("details %v, want a RetryInfo with a delay", st.Details())
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	got, err := ParseTrustedProxies([]string{"127.0.0.1", "::1", "10.0.0.0/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []netip.Prefix{
+		netip.MustParsePrefix("127.0.0.1/32"),
+		netip.MustParsePrefix("::1/128"),
+		netip.MustParsePrefix("10.0.0.0/8"),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	}
+	if _, err := ParseTrustedProxies([]string{"gateway"}); err == nil {
+		t.Error("a host name must be rejected")
+	}
+}
+
+func TestParseMethods(t *testing.T) {
+	got, err := ParseMethods([]string{"GetTransactionList=1:5", "/svc/Export=0.5:2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["GetTransactionList"] != (Limit{Rate: 1, Burst: 5}) || got["/svc/Export"] != (Limit{Rate: 0.5, Burst: 2}) {
+		t.Errorf("got %v", got)
+	}
+	for _, bad := range []string{"GetTransactionList", "=1:5", "M=x:5", "M=1:x"} {
+		if _, err := ParseMethods([]string{bad}); err == nil {
+			t.Errorf("%q must be rejected", bad)
+		}
+	}
+}
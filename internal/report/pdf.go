@@ -0,0 +1,115 @@
+package report
+
+import (
+	_ "embed"
+	"io"
+	"strconv"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+type column struct {
+	title string
+	width float64
+	align string
+}
+
+// DejaVu Sans Condensed, as shipped in the font directory of
+// github.com/go-pdf/fpdf, covers Latin, Cyrillic and Greek. Its license,
+// which must ship with the fonts, is in fonts/LICENSE.
+var (
+	//go:embed fonts/DejaVuSansCondensed.ttf
+	regularFont []byte
+	//go:embed fonts/DejaVuSansCondensed-Bold.ttf
+	boldFont []byte
+)
+
+const fontFamily = "DejaVu"
+
+// renderPDF lays the statement out with an embedded UTF-8 font, so names in
+// any script the font covers print as entered.
+func renderPDF(w io.Writer, s models.Statement) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AddUTF8FontFromBytes(fontFamily, "", regularFont)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", boldFont)
+	pdf.AddPage()
+
+	pdf.SetFont(fontFamily, "B", 16)
+	pdf.CellFormat(0, 9, "Statement "+period(s), "", 1, "L", false, 0, "")
+	pdf.SetFont(fontFamily, "", 9)
+	meta := "generated " + s.GeneratedAt.Format("2006-01-02 15:04") + " UTC"
+	if s.UserName != "" {
+		meta = s.UserName + " - " + meta
+	}
+	pdf.CellFormat(0, 6, meta, "", 1, "L", false, 0, "")
+
+	heading := func(text string) {
+		pdf.Ln(4)
+		pdf.SetFont(fontFamily, "B", 12)
+		pdf.CellFormat(0, 8, text, "", 1, "L", false, 0, "")
+	}
+	table := func(cols []column, rows [][]string) {
+		pdf.SetFont(fontFamily, "B", 9)
+		pdf.SetFillColor(235, 235, 235)
+		for _, c := range cols {
+			pdf.CellFormat(c.width, 6, c.title, "B", 0, c.align, true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont(fontFamily, "", 9)
+		for _, row := range rows {
+			for i, c := range cols {
+				pdf.CellFormat(c.width, 5.5, fit(pdf, row[i], c.width), "B", 0, c.align, false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+	}
+
+	heading("Summary")
+	table([]column{{"", 90, "L"}, {"Amount", 40, "R"}}, [][]string{
+		{"Spent this period", money(s.Total)},
+		{"Previous period", money(s.PreviousTotal)},
+		{"Change", change(s.Total, s.PreviousTotal)},
+	})
+
+	heading("By category")
+	rows := make([][]string, len(s.Categories))
+	for i, c := range s.Categories {
+		rows[i] = []string{c.Category, strconv.Itoa(c.Count), money(c.Total), money(c.Previous), change(c.Total, c.Previous)}
+	}
+	table([]column{{"Category", 60, "L"}, {"Transactions", 28, "R"}, {"Total", 32, "R"}, {"Previous", 32, "R"}, {"Change", 28, "R"}}, rows)
+
+	heading("Top expenses")
+	rows = make([][]string, len(s.TopExpenses))
+	for i, tx := range s.TopExpenses {
+		rows[i] = []string{tx.Date.Format("2006-01-02"), tx.Name, tx.Category, money(tx.Cost)}
+	}
+	table([]column{{"Date", 25, "L"}, {"Name", 80, "L"}, {"Category", 45, "L"}, {"Amount", 30, "R"}}, rows)
+
+	heading("Transactions")
+	rows = make([][]string, len(s.Transactions))
+	for i, tx := range s.Transactions {
+		rows[i] = []string{tx.Date.Format("2006-01-02 15:04"), tx.Name, tx.Category, kind(tx), money(tx.Cost)}
+	}
+	table([]column{{"Date", 30, "L"}, {"Name", 62, "L"}, {"Category", 38, "L"}, {"Kind", 24, "L"}, {"Amount", 26, "R"}}, rows)
+
+	if err := pdf.Error(); err != nil {
+		return err
+	}
+	return pdf.Output(w)
+}
+
+// fit shortens text so it stays within a cell.
+func fit(pdf *fpdf.Fpdf, text string, width float64) string {
+	limit := width - 2
+	if pdf.GetStringWidth(text) <= limit {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"...") > limit {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
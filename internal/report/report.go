@@ -0,0 +1,104 @@
+package report
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var funcs = map[string]any{
+	"money":  money,
+	"change": change,
+	"period": period,
+	"kind":   kind,
+	"trend": func(c models.CategoryComparison) string {
+		switch {
+		case c.Previous == 0 || c.Total == c.Previous:
+			return ""
+		case c.Total > c.Previous:
+			return "up"
+		default:
+			return "down"
+		}
+	},
+	// cell keeps a value from breaking a markdown table row.
+	"cell": func(s string) string {
+		return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+	},
+}
+
+var (
+	htmlTemplate     = htmltemplate.Must(htmltemplate.New("statement.html.tmpl").Funcs(funcs).ParseFS(templateFS, "templates/statement.html.tmpl"))
+	markdownTemplate = texttemplate.Must(texttemplate.New("statement.md.tmpl").Funcs(funcs).ParseFS(templateFS, "templates/statement.md.tmpl"))
+)
+
+// Render produces the statement document in the given format.
+func Render(statement models.Statement, format string) (models.Document, error) {
+	name := fmt.Sprintf("statement-%s", statement.StartDate.Format("2006-01"))
+	var buf bytes.Buffer
+	switch format {
+	case models.FormatHTML:
+		if err := htmlTemplate.Execute(&buf, statement); err != nil {
+			return models.Document{}, err
+		}
+		return models.Document{Name: name + ".html", ContentType: "text/html; charset=utf-8", Data: buf.Bytes()}, nil
+	case models.FormatMarkdown:
+		if err := markdownTemplate.Execute(&buf, statement); err != nil {
+			return models.Document{}, err
+		}
+		return models.Document{Name: name + ".md", ContentType: "text/markdown; charset=utf-8", Data: buf.Bytes()}, nil
+	case models.FormatPDF:
+		if err := renderPDF(&buf, statement); err != nil {
+			return models.Document{}, err
+		}
+		return models.Document{Name: name + ".pdf", ContentType: "application/pdf", Data: buf.Bytes()}, nil
+	}
+	return models.Document{}, fmt.Errorf("unknown statement format %q", format)
+}
+
+func money(v float64) string {
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	whole := fmt.Sprintf("%.2f", v)
+	intPart, frac := whole[:len(whole)-3], whole[len(whole)-3:]
+	var b strings.Builder
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(r)
+	}
+	return sign + b.String() + frac
+}
+
+func change(current, previous float64) string {
+	if previous == 0 {
+		return "—"
+	}
+	return fmt.Sprintf("%+.1f%%", (current-previous)/previous*100)
+}
+
+func period(s models.Statement) string {
+	if s.StartDate.Year() == s.EndDate.Year() && s.StartDate.Month() == s.EndDate.Month() {
+		return s.StartDate.Format("January 2006")
+	}
+	return s.StartDate.Format("2006-01-02") + " – " + s.EndDate.Format("2006-01-02")
+}
+
+func kind(tx models.Transaction) string {
+	if tx.Kind == "" {
+		return models.KindExpense
+	}
+	return tx.Kind
+}
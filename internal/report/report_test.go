@@ -0,0 +1,114 @@
+package report
+
+import (
+	"compress/zlib"
+	"io"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+func statement() models.Statement {
+	day := time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC)
+	return models.Statement{
+		UserID:        "alice",
+		UserName:      "Олена <admin>",
+		StartDate:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:       time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC),
+		Total:         1234.5,
+		PreviousTotal: 1000,
+		Categories: []models.CategoryComparison{
+			{Category: "продукти", Total: 1200, Count: 2, Previous: 900},
+			{Category: "cafe", Total: 34.5, Count: 1},
+		},
+		TopExpenses: []models.Transaction{{Name: "Сільпо | Київ", Category: "продукти", Cost: 1000, Date: day}},
+		Transactions: []models.Transaction{
+			{Name: "Сільпо | Київ", Category: "продукти", Cost: 1000, Date: day},
+			{Name: "АТБ", Category: "продукти", Cost: 200, Date: day, Kind: models.KindExpense},
+			{Name: "Coffee", Category: "cafe", Cost: 34.5, Date: day},
+		},
+		GeneratedAt: day,
+	}
+}
+
+func TestRenderHTMLAndMarkdown(t *testing.T) {
+	html, err := Render(statement(), models.FormatHTML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if html.Name != "statement-2024-03.html" || !strings.Contains(string(html.Data), "Олена &lt;admin&gt;") {
+		t.Errorf("html %s does not escape the user name:\n%s", html.Name, html.Data)
+	}
+	md, err := Render(statement(), models.FormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(md.Data), `Сільпо \| Київ`) {
+		t.Errorf("markdown should escape pipes in cells:\n%s", md.Data)
+	}
+	if _, err := Render(statement(), "docx"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+// TestRenderPDFEmbedsUnicodeFont checks that the PDF embeds the TrueType
+// font and writes Cyrillic text as the UTF-16 codes the font is indexed by.
+func TestRenderPDFEmbedsUnicodeFont(t *testing.T) {
+	doc, err := Render(statement(), models.FormatPDF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := string(doc.Data)
+	if !strings.HasPrefix(data, "%PDF-") || !strings.Contains(data, "/FontFile2") || strings.Contains(data, "/Helvetica") {
+		t.Fatal("want only the embedded TrueType font")
+	}
+	var content strings.Builder
+	for rest := data; ; {
+		start := strings.Index(rest, "stream\n")
+		if start < 0 {
+			break
+		}
+		rest = rest[start+len("stream\n"):]
+		end := strings.Index(rest, "endstream")
+		if r, err := zlib.NewReader(strings.NewReader(rest[:end])); err == nil {
+			b, _ := io.ReadAll(r)
+			content.Write(b)
+		}
+		rest = rest[end+len("endstream"):]
+	}
+	var want strings.Builder
+	for _, c := range utf16.Encode([]rune("Сільпо")) {
+		want.WriteByte(byte(c >> 8))
+		want.WriteByte(byte(c))
+	}
+	if !strings.Contains(content.String(), want.String()) {
+		t.Error("the page does not contain the Cyrillic payee name")
+	}
+}
+
+func TestFitKeepsRunes(t *testing.T) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(fontFamily, "", regularFont)
+	pdf.SetFont(fontFamily, "", 9)
+	long := strings.Repeat("Київ ", 20)
+	got := fit(pdf, long, 30)
+	if !utf8.ValidString(got) || !strings.HasSuffix(got, "...") || pdf.GetStringWidth(got) > 28 {
+		t.Errorf("fit = %q", got)
+	}
+	if got := fit(pdf, "АТБ", 30); got != "АТБ" {
+		t.Errorf("short text changed to %q", got)
+	}
+}
+
+func TestMoney(t *testing.T) {
+	for v, want := range map[float64]string{0: "0.00", 1234567.891: "1 234 567.89", -999.5: "-999.50"} {
+		if got := money(v); got != want {
+			t.Errorf("money(%v) = %q, want %q", v, got, want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AccountRepo struct {
+	collection *mongo.Collection
+}
+
+func NewAccountRepository(db *mongo.Client) *AccountRepo {
+	return &AccountRepo{
+		collection: db.Database(dbname).Collection(accountCollection),
+	}
+}
+
+func (r *AccountRepo) AddAccount(ctx context.Context, account models.Account) (string, error) {
+	result, err := r.collection.InsertOne(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	return result.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (r *AccountRepo) GetAccount(ctx context.Context, accountID, userID string) (*models.Account, error) {
+	oid, err := convertToObjectIDs(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("InvalidID: %v", err)
+	}
+	var account models.Account
+	err = r.collection.FindOne(ctx, bson.M{"_id": oid[0], "user_id": userID}).Decode(&account)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &account, err
+}
+
+func (r *AccountRepo) GetAccounts(ctx context.Context, userID string) ([]models.Account, error) {
+	accounts := []models.Account{}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &accounts)
+	if err != nil {
+		return nil, err
+	}
+	return accounts, err
+}
+
+func (r *AccountRepo) UpdateAccount(ctx context.Context, account models.Account) error {
+	oid, err := convertToObjectIDs(account.ID)
+	if err != nil {
+		return fmt.Errorf("InvalidID: %v", err)
+	}
+	filter := bson.M{"_id": oid[0], "user_id": account.UserID}
+	update := bson.M{
+		"$set": bson.M{
+			"name": account.Name,
+			"type": account.Type,
+		},
+	}
+	_, err = r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *AccountRepo) DeleteAccount(ctx context.Context, accountID, userID string) error {
+	oid, err := convertToObjectIDs(accountID)
+	if err != nil {
+		return fmt.Errorf("InvalidID: %v", err)
+	}
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": oid[0], "user_id": userID})
+	return err
+}
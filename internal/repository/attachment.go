@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AttachmentRepo struct {
+	bucket *gridfs.Bucket
+}
+
+func NewAttachmentRepository(db *mongo.Client) *AttachmentRepo {
+	bucket, err := gridfs.NewBucket(db.Database(dbname), options.GridFSBucket().SetName(attachmentBucket))
+	if err != nil {
+		log.Fatalf("Failed to open GridFS bucket: %v", err)
+	}
+	return &AttachmentRepo{bucket: bucket}
+}
+
+type attachmentFile struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	Length     int64              `bson:"length"`
+	UploadDate time.Time          `bson:"uploadDate"`
+	Filename   string             `bson:"filename"`
+	Metadata   attachmentMetadata `bson:"metadata"`
+}
+
+type attachmentMetadata struct {
+	UserID      string `bson:"user_id"`
+	TxID        string `bson:"tx_id"`
+	ContentType string `bson:"content_type"`
+	SHA256      string `bson:"sha256"`
+}
+
+func (f attachmentFile) toModel() models.Attachment {
+	return models.Attachment{
+		ID:          f.ID.Hex(),
+		UserID:      f.Metadata.UserID,
+		TxID:        f.Metadata.TxID,
+		FileName:    f.Filename,
+		ContentType: f.Metadata.ContentType,
+		Size:        f.Length,
+		SHA256:      f.Metadata.SHA256,
+		UploadedAt:  f.UploadDate,
+	}
+}
+
+// AddAttachment copies src into GridFS. The upload is aborted and no file is
+// left behind when src returns an error.
+func (r *AttachmentRepo) AddAttachment(ctx context.Context, attachment models.Attachment, src io.Reader) (string, error) {
+	opts := options.GridFSUpload().SetMetadata(attachmentMetadata{
+		UserID:      attachment.UserID,
+		TxID:        attachment.TxID,
+		ContentType: attachment.ContentType,
+		SHA256:      attachment.SHA256,
+	})
+	stream, err := r.bucket.OpenUploadStream(attachment.FileName, opts)
+	if err != nil {
+		return "", err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := stream.SetWriteDeadline(deadline); err != nil {
+			_ = stream.Abort()
+			return "", err
+		}
+	}
+	if _, err := io.Copy(stream, src); err != nil {
+		_ = stream.Abort()
+		return "", err
+	}
+	if err := stream.Close(); err != nil {
+		return "", err
+	}
+	return stream.FileID.(primitive.ObjectID).Hex(), nil
+}
+
+func (r *AttachmentRepo) GetAttachment(ctx context.Context, attachmentID, userID string) (*models.Attachment, error) {
+	oid, err := convertToObjectIDs(attachmentID)
+	if err != nil {
+		return nil, fmt.Errorf("InvalidID: %v", err)
+	}
+	var file attachmentFile
+	err = r.bucket.GetFilesCollection().FindOne(ctx, bson.M{"_id": oid[0], "metadata.user_id": userID}).Decode(&file)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	attachment := file.toModel()
+	return &attachment, nil
+}
+
+func (r *AttachmentRepo) GetAttachments(ctx context.Context, userID string, txIDs ...string) ([]models.Attachment, error) {
+	filter := bson.M{"metadata.user_id": userID}
+	if len(txIDs) > 0 {
+		filter["metadata.tx_id"] = bson.M{"$in": txIDs}
+	}
+	opts := options.GridFSFind().SetSort(bson.D{{Key: "uploadDate", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.bucket.FindContext(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var files []attachmentFile
+	if err := cursor.All(ctx, &files); err != nil {
+		return nil, err
+	}
+	attachments := make([]models.Attachment, len(files))
+	for i, f := range files {
+		attachments[i] = f.toModel()
+	}
+	return attachments, nil
+}
+
+func (r *AttachmentRepo) OpenAttachment(ctx context.Context, attachmentID string) (io.ReadCloser, error) {
+	oid, err := convertToObjectIDs(attachmentID)
+	if err != nil {
+		return nil, fmt.Errorf("InvalidID: %v", err)
+	}
+	stream, err := r.bucket.OpenDownloadStream(oid[0])
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := stream.SetReadDeadline(deadline); err != nil {
+			stream.Close()
+			return nil, err
+		}
+	}
+	return stream, nil
+}
+
+func (r *AttachmentRepo) DeleteAttachment(ctx context.Context, attachmentID string) error {
+	oid, err := convertToObjectIDs(attachmentID)
+	if err != nil {
+		return fmt.Errorf("InvalidID: %v", err)
+	}
+	return r.bucket.DeleteContext(ctx, oid[0])
+}
+
+func (r *AttachmentRepo) DeleteTxAttachments(ctx context.Context, userID string, txIDs ...string) error {
+	if len(txIDs) == 0 {
+		return nil
+	}
+	attachments, err := r.GetAttachments(ctx, userID, txIDs...)
+	if err != nil {
+		return err
+	}
+	for _, a := range attachments {
+		if err := r.DeleteAttachment(ctx, a.ID); err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteUserAttachments removes every attachment of the user, content
+// included.
+func (r *AttachmentRepo) DeleteUserAttachments(ctx context.Context, userID string) (int64, error) {
+	attachments, err := r.GetAttachments(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	var deleted int64
+	for _, a := range attachments {
+		err := r.DeleteAttachment(ctx, a.ID)
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			continue
+		}
+		if err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// GetOrphanedAttachments returns up to limit attachments uploaded before
+// uploadedBefore whose transaction no longer exists. Transactions merged
+// into another one are kept, so their attachments are not orphaned.
+func (r *AttachmentRepo) GetOrphanedAttachments(ctx context.Context, uploadedBefore time.Time, limit int) ([]models.Attachment, error) {
+	txID := bson.M{"$convert": bson.M{"input": "$$txID", "to": "objectId", "onError": nil, "onNull": nil}}
+	cursor, err := r.bucket.GetFilesCollection().Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"uploadDate": bson.M{"$lt": uploadedBefore}}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": transactionCollection,
+			"let":  bson.M{"txID": "$metadata.tx_id"},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", txID}}}},
+				bson.M{"$project": bson.M{"_id": 1}},
+			},
+			"as": "tx",
+		}}},
+		{{Key: "$match", Value: bson.M{"tx": bson.M{"$size": 0}}}},
+		{{Key: "$limit", Value: limit}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var files []attachmentFile
+	if err := cursor.All(ctx, &files); err != nil {
+		return nil, err
+	}
+	attachments := make([]models.Attachment, len(files))
+	for i, f := range files {
+		attachments[i] = f.toModel()
+	}
+	return attachments, nil
+}
+
+func (r *AttachmentRepo) CountUserAttachments(ctx context.Context, userID string) (int64, error) {
+	return r.bucket.GetFilesCollection().CountDocuments(ctx, bson.M{"metadata.user_id": userID})
+}
+
+// GetAttachmentsSize returns the bytes stored in the user's attachments.
+func (r *AttachmentRepo) GetAttachmentsSize(ctx context.Context, userID string) (int64, error) {
+	cursor, err := r.bucket.GetFilesCollection().Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"metadata.user_id": userID}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "size": bson.M{"$sum": "$length"}}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	var result []struct {
+		Size int64 `bson:"size"`
+	}
+	if err := cursor.All(ctx, &result); err != nil {
+		return 0, err
+	}
+	if len(result) == 0 {
+		return 0, nil
+	}
+	return result[0].Size, nil
+}
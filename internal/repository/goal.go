@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type GoalRepo struct {
+	collection *mongo.Collection
+}
+
+func NewGoalRepository(db *mongo.Client) *GoalRepo {
+	return &GoalRepo{
+		collection: db.Database(dbname).Collection(goalCollection),
+	}
+}
+
+func (r *GoalRepo) AddGoal(ctx context.Context, goal models.Goal) (string, error) {
+	result, err := r.collection.InsertOne(ctx, goal)
+	if err != nil {
+		return "", err
+	}
+	return result.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (r *GoalRepo) GetGoal(ctx context.Context, goalID, userID string) (*models.Goal, error) {
+	oid, err := convertToObjectIDs(goalID)
+	if err != nil {
+		return nil, fmt.Errorf("InvalidID: %v", err)
+	}
+	var goal models.Goal
+	err = r.collection.FindOne(ctx, bson.M{"_id": oid[0], "user_id": userID}).Decode(&goal)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &goal, nil
+}
+
+func (r *GoalRepo) GetGoals(ctx context.Context, userID string) ([]models.Goal, error) {
+	goals := []models.Goal{}
+	opts := options.Find().SetSort(bson.D{{Key: "deadline", Value: 1}, {Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &goals)
+	if err != nil {
+		return nil, err
+	}
+	return goals, err
+}
+
+func (r *GoalRepo) UpdateGoal(ctx context.Context, goal models.Goal) error {
+	oid, err := convertToObjectIDs(goal.ID)
+	if err != nil {
+		return fmt.Errorf("InvalidID: %v", err)
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"name":     goal.Name,
+			"target":   goal.Target,
+			"deadline": goal.Deadline,
+		},
+	}
+	if goal.AccountID != "" {
+		update["$set"].(bson.M)["account_id"] = goal.AccountID
+		update["$unset"] = bson.M{"tag": ""}
+	} else {
+		update["$set"].(bson.M)["tag"] = goal.Tag
+		update["$unset"] = bson.M{"account_id": ""}
+	}
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": oid[0], "user_id": goal.UserID}, update)
+	return err
+}
+
+func (r *GoalRepo) DeleteGoal(ctx context.Context, goalID, userID string) error {
+	oid, err := convertToObjectIDs(goalID)
+	if err != nil {
+		return fmt.Errorf("InvalidID: %v", err)
+	}
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": oid[0], "user_id": userID})
+	return err
+}
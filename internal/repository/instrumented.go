@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/justIGreK/MoneyKeeper-Transaction/internal/repository")
+
+type QueryObserver interface {
+	ObserveQuery(operation string, started time.Time, docs int, err error)
+	TransactionCreated(category string)
+}
+
+type InstrumentedTransactionRepo struct {
+	repo     *TransactionRepo
+	observer QueryObserver
+}
+
+func NewInstrumentedTransactionRepo(repo *TransactionRepo, observer QueryObserver) *InstrumentedTransactionRepo {
+	return &InstrumentedTransactionRepo{repo: repo, observer: observer}
+}
+
+type operation struct {
+	name    string
+	started time.Time
+	span    trace.Span
+}
+
+func (r *InstrumentedTransactionRepo) start(ctx context.Context, name, userID string) (context.Context, *operation) {
+	ctx, span := tracer.Start(ctx, "TransactionRepo."+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "mongodb"),
+			attribute.String("db.name", dbname),
+			attribute.String("db.mongodb.collection", transactionCollection),
+			attribute.String("user.id", userID),
+		))
+	return ctx, &operation{name: name, started: time.Now(), span: span}
+}
+
+func (r *InstrumentedTransactionRepo) finish(op *operation, docs int, err error) {
+	r.observer.ObserveQuery(op.name, op.started, docs, err)
+	op.span.SetAttributes(attribute.Int("db.documents", docs))
+	if err != nil {
+		op.span.RecordError(err)
+		op.span.SetStatus(codes.Error, err.Error())
+	}
+	op.span.End()
+}
+
+func (r *InstrumentedTransactionRepo) AddTransaction(ctx context.Context, transaction models.Transaction) (string, error) {
+	ctx, op := r.start(ctx, "AddTransaction", transaction.UserID)
+	id, err := r.repo.AddTransaction(ctx, transaction)
+	r.finish(op, 0, err)
+	if err == nil {
+		r.observer.TransactionCreated(transaction.Category)
+	}
+	return id, err
+}
+
+func (r *InstrumentedTransactionRepo) GetTransaction(ctx context.Context, transactionID, userID string) (*models.Transaction, error) {
+	ctx, op := r.start(ctx, "GetTransaction", userID)
+	tx, err := r.repo.GetTransaction(ctx, transactionID, userID)
+	r.finish(op, count(tx != nil), err)
+	return tx, err
+}
+
+func (r *InstrumentedTransactionRepo) GetAllTransactions(ctx context.Context, userID string) ([]models.Transaction, error) {
+	ctx, op := r.start(ctx, "GetAllTransactions", userID)
+	txs, err := r.repo.GetAllTransactions(ctx, userID)
+	r.finish(op, len(txs), err)
+	return txs, err
+}
+
+func (r *InstrumentedTransactionRepo) GetTXByTimeFrame(ctx context.Context, userID string, dateFrame models.TimeFrame) ([]models.Transaction, error) {
+	ctx, op := r.start(ctx, "GetTXByTimeFrame", userID)
+	txs, err := r.repo.GetTXByTimeFrame(ctx, userID, dateFrame)
+	r.finish(op, len(txs), err)
+	return txs, err
+}
+
+func (r *InstrumentedTransactionRepo) GetCategoryTotals(ctx context.Context, userID string, dateFrame models.TimeFrame) ([]models.CategoryTotal, error) {
+	ctx, op := r.start(ctx, "GetCategoryTotals", userID)
+	totals, err := r.repo.GetCategoryTotals(ctx, userID, dateFrame)
+	r.finish(op, len(totals), err)
+	return totals, err
+}
+
+func (r *InstrumentedTransactionRepo) UpdateTx(ctx context.Context, updates models.Transaction) error {
+	ctx, op := r.start(ctx, "UpdateTx", updates.UserID)
+	err := r.repo.UpdateTx(ctx, updates)
+	r.finish(op, 0, err)
+	return err
+}
+
+func (r *InstrumentedTransactionRepo) DeleteTx(ctx context.Context, userID, txID string) error {
+	ctx, op := r.start(ctx, "DeleteTx", userID)
+	err := r.repo.DeleteTx(ctx, userID, txID)
+	r.finish(op, 0, err)
+	return err
+}
+
+func (r *InstrumentedTransactionRepo) AddTransfer(ctx context.Context, outgoing, incoming models.Transaction) (*models.Transfer, error) {
+	ctx, op := r.start(ctx, "AddTransfer", outgoing.UserID)
+	transfer, err := r.repo.AddTransfer(ctx, outgoing, incoming)
+	r.finish(op, 0, err)
+	return transfer, err
+}
+
+func (r *InstrumentedTransactionRepo) DeleteTransfer(ctx context.Context, userID, transferID string) ([]string, error) {
+	ctx, op := r.start(ctx, "DeleteTransfer", userID)
+	ids, err := r.repo.DeleteTransfer(ctx, userID, transferID)
+	r.finish(op, len(ids), err)
+	return ids, err
+}
+
+func (r *InstrumentedTransactionRepo) GetCashFlow(ctx context.Context, userID, accountID string, dateFrame models.TimeFrame) ([]models.CashFlowEntry, error) {
+	ctx, op := r.start(ctx, "GetCashFlow", userID)
+	entries, err := r.repo.GetCashFlow(ctx, userID, accountID, dateFrame)
+	r.finish(op, len(entries), err)
+	return entries, err
+}
+
+func (r *InstrumentedTransactionRepo) GetAccountFlow(ctx context.Context, userID, accountID string, until time.Time) (float64, error) {
+	ctx, op := r.start(ctx, "GetAccountFlow", userID)
+	flow, err := r.repo.GetAccountFlow(ctx, userID, accountID, until)
+	r.finish(op, 0, err)
+	return flow, err
+}
+
+func (r *InstrumentedTransactionRepo) CountAccountTransactions(ctx context.Context, userID, accountID string) (int64, error) {
+	ctx, op := r.start(ctx, "CountAccountTransactions", userID)
+	n, err := r.repo.CountAccountTransactions(ctx, userID, accountID)
+	r.finish(op, 0, err)
+	return n, err
+}
+
+func (r *InstrumentedTransactionRepo) GetPayeeTotals(ctx context.Context, userID string, dateFrame models.TimeFrame) ([]models.PayeeTotal, error) {
+	ctx, op := r.start(ctx, "GetPayeeTotals", userID)
+	totals, err := r.repo.GetPayeeTotals(ctx, userID, dateFrame)
+	r.finish(op, len(totals), err)
+	return totals, err
+}
+
+func (r *InstrumentedTransactionRepo) MergeTransactions(ctx context.Context, keep models.Transaction, removed []models.Transaction, entry models.AuditEntry) error {
+	ctx, op := r.start(ctx, "MergeTransactions", keep.UserID)
+	err := r.repo.MergeTransactions(ctx, keep, removed, entry)
+	r.finish(op, len(removed)+1, err)
+	return err
+}
+
+func (r *InstrumentedTransactionRepo) GetRecentCosts(ctx context.Context, userID, category string, limit int) ([]float64, error) {
+	ctx, op := r.start(ctx, "GetRecentCosts", userID)
+	costs, err := r.repo.GetRecentCosts(ctx, userID, category, limit)
+	r.finish(op, len(costs), err)
+	return costs, err
+}
+
+func (r *InstrumentedTransactionRepo) CountPayeeTransactions(ctx context.Context, userID, payeeID string) (int64, error) {
+	ctx, op := r.start(ctx, "CountPayeeTransactions", userID)
+	n, err := r.repo.CountPayeeTransactions(ctx, userID, payeeID)
+	r.finish(op, 0, err)
+	return n, err
+}
+
+func (r *InstrumentedTransactionRepo) GetAnomalies(ctx context.Context, userID string, minScore float64, dateFrame models.TimeFrame, limit int) ([]models.Transaction, error) {
+	ctx, op := r.start(ctx, "GetAnomalies", userID)
+	txs, err := r.repo.GetAnomalies(ctx, userID, minScore, dateFrame, limit)
+	r.finish(op, len(txs), err)
+	return txs, err
+}
+
+func (r *InstrumentedTransactionRepo) GetTagContributions(ctx context.Context, userID, tag string, until time.Time) (float64, error) {
+	ctx, op := r.start(ctx, "GetTagContributions", userID)
+	saved, err := r.repo.GetTagContributions(ctx, userID, tag, until)
+	r.finish(op, 0, err)
+	return saved, err
+}
+
+func (r *InstrumentedTransactionRepo) StreamUserTransactions(ctx context.Context, userID string, fn func(models.Transaction) error) error {
+	ctx, op := r.start(ctx, "StreamUserTransactions", userID)
+	n := 0
+	err := r.repo.StreamUserTransactions(ctx, userID, func(tx models.Transaction) error {
+		n++
+		return fn(tx)
+	})
+	r.finish(op, n, err)
+	return err
+}
+
+func (r *InstrumentedTransactionRepo) DeleteUserTransactions(ctx context.Context, userID string) (int64, error) {
+	ctx, op := r.start(ctx, "DeleteUserTransactions", userID)
+	deleted, err := r.repo.DeleteUserTransactions(ctx, userID)
+	r.finish(op, int(deleted), err)
+	return deleted, err
+}
+
+func (r *InstrumentedTransactionRepo) CountUserTransactions(ctx context.Context, userID string) (int64, error) {
+	ctx, op := r.start(ctx, "CountUserTransactions", userID)
+	n, err := r.repo.CountUserTransactions(ctx, userID)
+	r.finish(op, 0, err)
+	return n, err
+}
+
+func (r *InstrumentedTransactionRepo) GetUserIDs(ctx context.Context) ([]string, error) {
+	ctx, op := r.start(ctx, "GetUserIDs", "")
+	ids, err := r.repo.GetUserIDs(ctx)
+	r.finish(op, len(ids), err)
+	return ids, err
+}
+
+func (r *InstrumentedTransactionRepo) GetTransactionByID(ctx context.Context, transactionID string) (*models.Transaction, error) {
+	ctx, op := r.start(ctx, "GetTransactionByID", "")
+	tx, err := r.repo.GetTransactionByID(ctx, transactionID)
+	r.finish(op, count(tx != nil), err)
+	return tx, err
+}
+
+func (r *InstrumentedTransactionRepo) GetLedgerTransactions(ctx context.Context, ledgerID string, dateFrame models.TimeFrame) ([]models.Transaction, error) {
+	ctx, op := r.start(ctx, "GetLedgerTransactions", "")
+	op.span.SetAttributes(attribute.String("ledger.id", ledgerID))
+	txs, err := r.repo.GetLedgerTransactions(ctx, ledgerID, dateFrame)
+	r.finish(op, len(txs), err)
+	return txs, err
+}
+
+func (r *InstrumentedTransactionRepo) CountTransactionsSince(ctx context.Context, userID string, since time.Time) (int64, error) {
+	ctx, op := r.start(ctx, "CountTransactionsSince", userID)
+	n, err := r.repo.CountTransactionsSince(ctx, userID, since)
+	r.finish(op, 0, err)
+	return n, err
+}
+
+func count(found bool) int {
+	if found {
+		return 1
+	}
+	return 0
+}
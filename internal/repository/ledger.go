@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LedgerRepo struct {
+	collection *mongo.Collection
+}
+
+func NewLedgerRepository(db *mongo.Client) *LedgerRepo {
+	return &LedgerRepo{
+		collection: db.Database(dbname).Collection(ledgerCollection),
+	}
+}
+
+func (r *LedgerRepo) AddLedger(ctx context.Context, ledger models.Ledger) (string, error) {
+	result, err := r.collection.InsertOne(ctx, ledger)
+	if err != nil {
+		return "", err
+	}
+	return result.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (r *LedgerRepo) GetLedger(ctx context.Context, ledgerID string) (*models.Ledger, error) {
+	oid, err := convertToObjectIDs(ledgerID)
+	if err != nil {
+		return nil, fmt.Errorf("InvalidID: %v", err)
+	}
+	var ledger models.Ledger
+	err = r.collection.FindOne(ctx, bson.M{"_id": oid[0]}).Decode(&ledger)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &ledger, nil
+}
+
+// GetLedgers returns the ledgers the user is a member of or invited to.
+func (r *LedgerRepo) GetLedgers(ctx context.Context, userID string) ([]models.Ledger, error) {
+	ledgers := []models.Ledger{}
+	filter := bson.M{"$or": bson.A{bson.M{"members.user_id": userID}, bson.M{"invites.user_id": userID}}}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &ledgers)
+	if err != nil {
+		return nil, err
+	}
+	return ledgers, err
+}
+
+// AddInvite replaces any pending invite of the same user. It does nothing
+// when the user has become a member in the meantime.
+func (r *LedgerRepo) AddInvite(ctx context.Context, ledgerID string, invite models.LedgerInvite) error {
+	oid, err := convertToObjectIDs(ledgerID)
+	if err != nil {
+		return fmt.Errorf("InvalidID: %v", err)
+	}
+	filter := bson.M{"_id": oid[0], "members.user_id": bson.M{"$ne": invite.UserID}}
+	_, err = r.collection.UpdateOne(ctx, filter, bson.M{"$pull": bson.M{"invites": bson.M{"user_id": invite.UserID}}})
+	if err != nil {
+		return err
+	}
+	_, err = r.collection.UpdateOne(ctx, filter, bson.M{"$push": bson.M{"invites": invite}})
+	return err
+}
+
+// AcceptInvite turns the user's pending invite into a membership. It
+// reports false when there is no invite to accept.
+func (r *LedgerRepo) AcceptInvite(ctx context.Context, ledgerID string, member models.LedgerMember) (bool, error) {
+	oid, err := convertToObjectIDs(ledgerID)
+	if err != nil {
+		return false, fmt.Errorf("InvalidID: %v", err)
+	}
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": oid[0], "invites.user_id": member.UserID, "members.user_id": bson.M{"$ne": member.UserID}},
+		bson.M{
+			"$pull": bson.M{"invites": bson.M{"user_id": member.UserID}},
+			"$push": bson.M{"members": member},
+		})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// RemoveMember drops the user's membership and pending invite. The owner
+// is never removed.
+func (r *LedgerRepo) RemoveMember(ctx context.Context, ledgerID, userID string) (bool, error) {
+	oid, err := convertToObjectIDs(ledgerID)
+	if err != nil {
+		return false, fmt.Errorf("InvalidID: %v", err)
+	}
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": oid[0], "owner_id": bson.M{"$ne": userID}},
+		bson.M{"$pull": bson.M{
+			"members": bson.M{"user_id": userID},
+			"invites": bson.M{"user_id": userID},
+		}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
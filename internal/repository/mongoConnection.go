@@ -3,17 +3,34 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const (
-	dbname                = "mktx"
-	transactionCollection = "transactions"
-
+	dbname                  = "mktx"
+	transactionCollection   = "transactions"
+	sharedExpenseCollection = "shared_expenses"
+	groupCollection         = "groups"
+	accountCollection       = "accounts"
+	attachmentBucket        = "attachments"
+	outboxCollection        = "outbox"
+	webhookCollection       = "webhooks"
+	deliveryCollection      = "webhook_deliveries"
+	ruleCollection          = "rules"
+	categoryModelCollection = "category_models"
+	auditCollection         = "audit"
+	payeeCollection         = "payees"
+	goalCollection          = "goals"
+	statementCollection     = "statements"
+	erasureCollection       = "erasures"
+	ledgerCollection        = "ledgers"
+	orphanCollection        = "orphan_sightings"
 )
 
 func CreateMongoClient(ctx context.Context) *mongo.Client {
@@ -29,6 +46,27 @@ func CreateMongoClient(ctx context.Context) *mongo.Client {
 	return client
 }
 
+// EnsureIndexes creates the unique indexes that upserts rely on to stay
+// single under concurrent callers. Creating an existing index is a no-op.
+func EnsureIndexes(ctx context.Context, db *mongo.Client) error {
+	indexes := map[string]mongo.IndexModel{
+		payeeCollection: {
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "aliases", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		deliveryCollection: {
+			Keys:    bson.D{{Key: "event_id", Value: 1}, {Key: "webhook_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+	for collection, index := range indexes {
+		if _, err := db.Database(dbname).Collection(collection).Indexes().CreateOne(ctx, index); err != nil {
+			return fmt.Errorf("creating index on %s: %w", collection, err)
+		}
+	}
+	return nil
+}
+
 func convertToObjectIDs(ids ...string) ([]primitive.ObjectID, error) {
 	objectIDs := make([]primitive.ObjectID, 0, len(ids))
 
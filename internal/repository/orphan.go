@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type orphanSighting struct {
+	UserID    string    `bson:"_id"`
+	FirstSeen time.Time `bson:"first_seen"`
+}
+
+// OrphanSightingRepo remembers when each user was first found missing from
+// the user service, so removal can wait for a later run to confirm it.
+type OrphanSightingRepo struct {
+	collection *mongo.Collection
+}
+
+func NewOrphanSightingRepository(db *mongo.Client) *OrphanSightingRepo {
+	return &OrphanSightingRepo{
+		collection: db.Database(dbname).Collection(orphanCollection),
+	}
+}
+
+func (r *OrphanSightingRepo) GetOrphanSightings(ctx context.Context) (map[string]time.Time, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	var sightings []orphanSighting
+	if err := cursor.All(ctx, &sightings); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]time.Time, len(sightings))
+	for _, s := range sightings {
+		seen[s.UserID] = s.FirstSeen
+	}
+	return seen, nil
+}
+
+// AddOrphanSighting records the user as missing since seenAt unless an
+// earlier sighting is already recorded.
+func (r *OrphanSightingRepo) AddOrphanSighting(ctx context.Context, userID string, seenAt time.Time) error {
+	update := bson.M{"$setOnInsert": bson.M{"first_seen": seenAt}}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": userID}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *OrphanSightingRepo) DeleteOrphanSighting(ctx context.Context, userID string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": userID})
+	return err
+}
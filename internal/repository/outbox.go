@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OutboxRepo struct {
+	collection *mongo.Collection
+}
+
+func NewOutboxRepository(db *mongo.Client) *OutboxRepo {
+	return &OutboxRepo{
+		collection: db.Database(dbname).Collection(outboxCollection),
+	}
+}
+
+// GetPendingEvents returns unpublished events in the order they were written.
+// Users with an event waiting for its next attempt are left out entirely, so
+// their later events are not published ahead of it.
+func (r *OutboxRepo) GetPendingEvents(ctx context.Context, now time.Time, limit int) ([]models.Event, error) {
+	pending := bson.M{
+		"published_at": bson.M{"$exists": false},
+		"status":       bson.M{"$ne": models.EventDead},
+	}
+	deferred, err := r.collection.Distinct(ctx, "user_id", bson.M{
+		"published_at":    pending["published_at"],
+		"status":          pending["status"],
+		"next_attempt_at": bson.M{"$gt": now},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(deferred) > 0 {
+		pending["user_id"] = bson.M{"$nin": deferred}
+	}
+	events := []models.Event{}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "occurred_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.collection.Find(ctx, pending, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &events)
+	if err != nil {
+		return nil, err
+	}
+	return events, err
+}
+
+func (r *OutboxRepo) MarkPublished(ctx context.Context, eventID string) error {
+	oid, err := convertToObjectIDs(eventID)
+	if err != nil {
+		return fmt.Errorf("InvalidID: %v", err)
+	}
+	_, err = r.collection.UpdateByID(ctx, oid[0], bson.M{
+		"$set":   bson.M{"published_at": time.Now().UTC()},
+		"$inc":   bson.M{"attempts": 1},
+		"$unset": bson.M{"last_error": "", "next_attempt_at": ""},
+	})
+	return err
+}
+
+// RecordFailure schedules the event's next attempt, or dead-letters it when
+// nextAttempt is nil.
+func (r *OutboxRepo) RecordFailure(ctx context.Context, eventID string, cause error, nextAttempt *time.Time) error {
+	oid, err := convertToObjectIDs(eventID)
+	if err != nil {
+		return fmt.Errorf("InvalidID: %v", err)
+	}
+	set := bson.M{"last_error": cause.Error()}
+	if nextAttempt != nil {
+		set["next_attempt_at"] = *nextAttempt
+	} else {
+		set["status"] = models.EventDead
+	}
+	_, err = r.collection.UpdateByID(ctx, oid[0], bson.M{
+		"$set": set,
+		"$inc": bson.M{"attempts": 1},
+	})
+	return err
+}
+
+// DeletePublishedEvents removes events published before the given time.
+func (r *OutboxRepo) DeletePublishedEvents(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"published_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PayeeRepo struct {
+	collection   *mongo.Collection
+	transactions *mongo.Collection
+}
+
+func NewPayeeRepository(db *mongo.Client) *PayeeRepo {
+	return &PayeeRepo{
+		collection:   db.Database(dbname).Collection(payeeCollection),
+		transactions: db.Database(dbname).Collection(transactionCollection),
+	}
+}
+
+// ResolvePayee returns the user's payee that has key among its aliases,
+// creating it with the given name if there is none. When a concurrent call
+// creates the payee first, the unique alias index rejects the second insert
+// and the lookup is retried to find the winner.
+func (r *PayeeRepo) ResolvePayee(ctx context.Context, userID, key, name string) (*models.Payee, error) {
+	filter := bson.M{"user_id": userID, "aliases": bson.M{"$elemMatch": bson.M{"$eq": key}}}
+	update := bson.M{
+		"$setOnInsert": bson.M{"name": name, "created_at": time.Now().UTC()},
+		"$addToSet":    bson.M{"aliases": key},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var payee models.Payee
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&payee)
+	if mongo.IsDuplicateKeyError(err) {
+		err = r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&payee)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &payee, nil
+}
+
+func (r *PayeeRepo) GetPayee(ctx context.Context, payeeID, userID string) (*models.Payee, error) {
+	oid, err := convertToObjectIDs(payeeID)
+	if err != nil {
+		return nil, fmt.Errorf("InvalidID: %v", err)
+	}
+	var payee models.Payee
+	err = r.collection.FindOne(ctx, bson.M{"_id": oid[0], "user_id": userID}).Decode(&payee)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &payee, nil
+}
+
+func (r *PayeeRepo) GetPayees(ctx context.Context, userID string) ([]models.Payee, error) {
+	payees := []models.Payee{}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &payees)
+	if err != nil {
+		return nil, err
+	}
+	return payees, err
+}
+
+// MergePayees moves the aliases and transactions of sources to target and
+// deletes the sources. It returns the number of transactions relinked.
+func (r *PayeeRepo) MergePayees(ctx context.Context, target models.Payee, sources []models.Payee) (int64, error) {
+	targetID, err := convertToObjectIDs(target.ID)
+	if err != nil {
+		return 0, fmt.Errorf("InvalidID: %v", err)
+	}
+	ids := make([]string, len(sources))
+	aliases := []string{}
+	for i, p := range sources {
+		ids[i] = p.ID
+		aliases = append(aliases, p.Aliases...)
+	}
+	oids, err := convertToObjectIDs(ids...)
+	if err != nil {
+		return 0, fmt.Errorf("InvalidID: %v", err)
+	}
+	session, err := r.collection.Database().Client().StartSession()
+	if err != nil {
+		return 0, err
+	}
+	defer session.EndSession(ctx)
+	var moved int64
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		// the sources go first: the unique alias index would reject their
+		// aliases on the target while they still hold them.
+		_, err := r.collection.DeleteMany(sc, bson.M{"_id": bson.M{"$in": oids}, "user_id": target.UserID})
+		if err != nil {
+			return nil, err
+		}
+		_, err = r.collection.UpdateOne(sc, bson.M{"_id": targetID[0], "user_id": target.UserID}, bson.M{
+			"$set":      bson.M{"name": target.Name},
+			"$addToSet": bson.M{"aliases": bson.M{"$each": aliases}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		result, err := r.transactions.UpdateMany(sc, bson.M{"user_id": target.UserID, "payee_id": bson.M{"$in": ids}},
+			bson.M{"$set": bson.M{"payee_id": target.ID}})
+		if err != nil {
+			return nil, err
+		}
+		moved = result.ModifiedCount
+		return nil, nil
+	})
+	return moved, err
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PrivacyRepo struct {
+	db       *mongo.Database
+	erasures *mongo.Collection
+}
+
+func NewPrivacyRepository(db *mongo.Client) *PrivacyRepo {
+	return &PrivacyRepo{
+		db:       db.Database(dbname),
+		erasures: db.Database(dbname).Collection(erasureCollection),
+	}
+}
+
+// ownedStores maps the stores holding documents that belong to a single
+// user to their collection and owner field.
+var ownedStores = map[string]struct{ collection, field string }{
+	models.StoreAccounts:       {accountCollection, "user_id"},
+	models.StoreGoals:          {goalCollection, "user_id"},
+	models.StorePayees:         {payeeCollection, "user_id"},
+	models.StoreRules:          {ruleCollection, "user_id"},
+	models.StoreCategoryModels: {categoryModelCollection, "_id"},
+	models.StoreWebhooks:       {webhookCollection, "user_id"},
+	models.StoreDeliveries:     {deliveryCollection, "user_id"},
+	models.StoreStatements:     {statementCollection, "user_id"},
+	models.StoreAudit:          {auditCollection, "user_id"},
+	models.StoreOutbox:         {outboxCollection, "user_id"},
+}
+
+func (r *PrivacyRepo) GetErasure(ctx context.Context, userID string) (*models.Erasure, error) {
+	var erasure models.Erasure
+	err := r.erasures.FindOne(ctx, bson.M{"_id": userID}).Decode(&erasure)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &erasure, nil
+}
+
+func (r *PrivacyRepo) SaveErasure(ctx context.Context, erasure models.Erasure) error {
+	_, err := r.erasures.ReplaceOne(ctx, bson.M{"_id": erasure.UserID}, erasure, options.Replace().SetUpsert(true))
+	return err
+}
+
+// EraseUserRecords removes the user's documents from the store. Shared
+// expenses, groups and the other side of settlements also belong to the
+// other members of a group, so there the user is replaced by anonymousID
+// instead and the group's balances stay intact. Ledgers the user owns are deleted; from the others the user
+// is removed.
+func (r *PrivacyRepo) EraseUserRecords(ctx context.Context, store, userID, anonymousID string) (int64, error) {
+	switch store {
+	case models.StoreSharedExpenses:
+		return r.anonymize(ctx, sharedExpenseCollection, sharedFilter(userID), bson.M{
+			"payer_id": replaceUser("$payer_id", userID, anonymousID),
+			"shares": bson.M{"$map": bson.M{
+				"input": "$shares",
+				"as":    "share",
+				"in": bson.M{"$mergeObjects": bson.A{"$$share", bson.M{
+					"user_id": replaceUser("$$share.user_id", userID, anonymousID),
+				}}},
+			}},
+		})
+	case models.StoreGroups:
+		groups, err := r.anonymize(ctx, groupCollection, groupFilter(userID), bson.M{
+			"owner_id": replaceUser("$owner_id", userID, anonymousID),
+			"members": bson.M{"$map": bson.M{
+				"input": "$members",
+				"as":    "member",
+				"in":    replaceUser("$$member", userID, anonymousID),
+			}},
+		})
+		if err != nil {
+			return 0, err
+		}
+		legs, err := r.db.Collection(transactionCollection).UpdateMany(ctx, bson.M{"counterparty_id": userID},
+			bson.M{"$set": bson.M{"counterparty_id": anonymousID}})
+		if err != nil {
+			return 0, err
+		}
+		return groups + legs.ModifiedCount, nil
+	case models.StoreLedgers:
+		ledgers := r.db.Collection(ledgerCollection)
+		deleted, err := ledgers.DeleteMany(ctx, bson.M{"owner_id": userID})
+		if err != nil {
+			return 0, err
+		}
+		left, err := ledgers.UpdateMany(ctx, ledgerFilter(userID), bson.M{"$pull": bson.M{
+			"members": bson.M{"user_id": userID},
+			"invites": bson.M{"user_id": userID},
+		}})
+		if err != nil {
+			return 0, err
+		}
+		return deleted.DeletedCount + left.ModifiedCount, nil
+	}
+	owned, ok := ownedStores[store]
+	if !ok {
+		return 0, fmt.Errorf("unknown store %q", store)
+	}
+	result, err := r.db.Collection(owned.collection).DeleteMany(ctx, bson.M{owned.field: userID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// CountUserRecords counts the documents of the store that still refer to
+// the user.
+func (r *PrivacyRepo) CountUserRecords(ctx context.Context, store, userID string) (int64, error) {
+	switch store {
+	case models.StoreSharedExpenses:
+		return r.db.Collection(sharedExpenseCollection).CountDocuments(ctx, sharedFilter(userID))
+	case models.StoreGroups:
+		groups, err := r.db.Collection(groupCollection).CountDocuments(ctx, groupFilter(userID))
+		if err != nil {
+			return 0, err
+		}
+		legs, err := r.db.Collection(transactionCollection).CountDocuments(ctx, bson.M{"counterparty_id": userID})
+		return groups + legs, err
+	case models.StoreLedgers:
+		return r.db.Collection(ledgerCollection).CountDocuments(ctx, ledgerFilter(userID))
+	}
+	owned, ok := ownedStores[store]
+	if !ok {
+		return 0, fmt.Errorf("unknown store %q", store)
+	}
+	return r.db.Collection(owned.collection).CountDocuments(ctx, bson.M{owned.field: userID})
+}
+
+func (r *PrivacyRepo) anonymize(ctx context.Context, collection string, filter, set bson.M) (int64, error) {
+	result, err := r.db.Collection(collection).UpdateMany(ctx, filter, mongo.Pipeline{{{Key: "$set", Value: set}}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func replaceUser(field, userID, anonymousID string) bson.M {
+	return bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{field, userID}}, anonymousID, field}}
+}
+
+func sharedFilter(userID string) bson.M {
+	return bson.M{"$or": bson.A{bson.M{"payer_id": userID}, bson.M{"shares.user_id": userID}}}
+}
+
+func groupFilter(userID string) bson.M {
+	return bson.M{"$or": bson.A{bson.M{"owner_id": userID}, bson.M{"members": userID}}}
+}
+
+func ledgerFilter(userID string) bson.M {
+	return bson.M{"$or": bson.A{bson.M{"owner_id": userID}, bson.M{"members.user_id": userID}, bson.M{"invites.user_id": userID}}}
+}
+
+func (r *PrivacyRepo) StreamAuditEntries(ctx context.Context, userID string, fn func(models.AuditEntry) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.db.Collection(auditCollection).Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var entry models.AuditEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return err
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RuleRepo struct {
+	collection *mongo.Collection
+}
+
+func NewRuleRepository(db *mongo.Client) *RuleRepo {
+	return &RuleRepo{
+		collection: db.Database(dbname).Collection(ruleCollection),
+	}
+}
+
+func (r *RuleRepo) AddRule(ctx context.Context, rule models.Rule) (string, error) {
+	result, err := r.collection.InsertOne(ctx, rule)
+	if err != nil {
+		return "", err
+	}
+	return result.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (r *RuleRepo) GetRule(ctx context.Context, ruleID, userID string) (*models.Rule, error) {
+	oid, err := convertToObjectIDs(ruleID)
+	if err != nil {
+		return nil, fmt.Errorf("InvalidID: %v", err)
+	}
+	var rule models.Rule
+	err = r.collection.FindOne(ctx, bson.M{"_id": oid[0], "user_id": userID}).Decode(&rule)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *RuleRepo) GetRules(ctx context.Context, userID string) ([]models.Rule, error) {
+	rules := []models.Rule{}
+	opts := options.Find().SetSort(bson.D{{Key: "priority", Value: -1}, {Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &rules)
+	if err != nil {
+		return nil, err
+	}
+	return rules, err
+}
+
+func (r *RuleRepo) UpdateRule(ctx context.Context, rule models.Rule) error {
+	oid, err := convertToObjectIDs(rule.ID)
+	if err != nil {
+		return fmt.Errorf("InvalidID: %v", err)
+	}
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": oid[0], "user_id": rule.UserID}, bson.M{
+		"$set": bson.M{
+			"name":       rule.Name,
+			"priority":   rule.Priority,
+			"enabled":    rule.Enabled,
+			"conditions": rule.Conditions,
+			"actions":    rule.Actions,
+		},
+	})
+	return err
+}
+
+func (r *RuleRepo) DeleteRule(ctx context.Context, ruleID, userID string) error {
+	oid, err := convertToObjectIDs(ruleID)
+	if err != nil {
+		return fmt.Errorf("InvalidID: %v", err)
+	}
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": oid[0], "user_id": userID})
+	return err
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SharedExpenseRepo struct {
+	groups       *mongo.Collection
+	expenses     *mongo.Collection
+	transactions *TransactionRepo
+}
+
+func NewSharedExpenseRepository(db *mongo.Client) *SharedExpenseRepo {
+	return &SharedExpenseRepo{
+		groups:       db.Database(dbname).Collection(groupCollection),
+		expenses:     db.Database(dbname).Collection(sharedExpenseCollection),
+		transactions: NewTransactionRepository(db),
+	}
+}
+
+func (r *SharedExpenseRepo) AddGroup(ctx context.Context, group models.Group) (string, error) {
+	result, err := r.groups.InsertOne(ctx, group)
+	if err != nil {
+		return "", err
+	}
+	return result.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (r *SharedExpenseRepo) GetGroup(ctx context.Context, groupID string) (*models.Group, error) {
+	oid, err := convertToObjectIDs(groupID)
+	if err != nil {
+		return nil, fmt.Errorf("InvalidID: %v", err)
+	}
+	var group models.Group
+	err = r.groups.FindOne(ctx, bson.M{"_id": oid[0]}).Decode(&group)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *SharedExpenseRepo) AddGroupMember(ctx context.Context, groupID, memberID string) error {
+	oid, err := convertToObjectIDs(groupID)
+	if err != nil {
+		return fmt.Errorf("InvalidID: %v", err)
+	}
+	_, err = r.groups.UpdateOne(ctx, bson.M{"_id": oid[0]}, bson.M{"$addToSet": bson.M{"members": memberID}})
+	return err
+}
+
+// AddSharedExpense stores the expense and moves the group to a new version.
+func (r *SharedExpenseRepo) AddSharedExpense(ctx context.Context, expense models.SharedExpense) (string, error) {
+	oid, err := convertToObjectIDs(expense.GroupID)
+	if err != nil {
+		return "", fmt.Errorf("InvalidID: %v", err)
+	}
+	result, err := r.expenses.InsertOne(ctx, expense)
+	if err != nil {
+		return "", err
+	}
+	if _, err := r.groups.UpdateOne(ctx, bson.M{"_id": oid[0]}, bson.M{"$inc": bson.M{"version": 1}}); err != nil {
+		return "", err
+	}
+	return result.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (r *SharedExpenseRepo) GetGroupExpenses(ctx context.Context, groupID string) ([]models.SharedExpense, error) {
+	expenses := []models.SharedExpense{}
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.expenses.Find(ctx, bson.M{"group_id": groupID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &expenses)
+	if err != nil {
+		return nil, err
+	}
+	return expenses, err
+}
+
+// SettleGroup records the settlements as transaction legs if the group is
+// still at the version they were computed for, and moves it to the next
+// one. It returns false, recording nothing, when the version has moved on.
+func (r *SharedExpenseRepo) SettleGroup(ctx context.Context, group models.Group, userID string, settlements []models.Settlement) ([]models.Settlement, bool, error) {
+	oid, err := convertToObjectIDs(group.ID)
+	if err != nil {
+		return nil, false, fmt.Errorf("InvalidID: %v", err)
+	}
+	settled := make([]models.Settlement, len(settlements))
+	ids := make([]string, len(settlements))
+	for i, s := range settlements {
+		s.ID = primitive.NewObjectID().Hex()
+		settled[i] = s
+		ids[i] = s.ID
+	}
+	applied := false
+	err = r.transactions.withOutbox(ctx, func(sc mongo.SessionContext) ([]models.Event, error) {
+		applied = false
+		result, err := r.groups.UpdateOne(sc, bson.M{"_id": oid[0], "version": group.Version}, bson.M{
+			"$inc": bson.M{"version": 1},
+			"$set": bson.M{"last_settlement": models.GroupSettlement{Version: group.Version, UserID: userID, IDs: ids}},
+		})
+		if err != nil || result.MatchedCount == 0 {
+			return nil, err
+		}
+		applied = true
+		var events []models.Event
+		for _, s := range settled {
+			outgoing, incoming := s.Legs("Settle up: " + group.Name)
+			for _, leg := range []models.Transaction{outgoing, incoming} {
+				inserted, err := r.transactions.collection.InsertOne(sc, leg)
+				if err != nil {
+					return nil, err
+				}
+				leg.ID = inserted.InsertedID.(primitive.ObjectID).Hex()
+				events = append(events, newEvent(models.EventTransactionCreated, leg))
+			}
+		}
+		return events, nil
+	})
+	if err != nil || !applied {
+		return nil, false, err
+	}
+	return settled, true, nil
+}
+
+// GetGroupSettlements rebuilds the group's settlements, or just those with
+// the given IDs, from their legs. Either leg is enough, so a settlement
+// survives one of its parties being erased.
+func (r *SharedExpenseRepo) GetGroupSettlements(ctx context.Context, groupID string, settlementIDs ...string) ([]models.Settlement, error) {
+	filter := active(bson.M{
+		"group_id": groupID,
+		"kind":     bson.M{"$in": bson.A{models.KindSettlementOut, models.KindSettlementIn}},
+	})
+	if len(settlementIDs) > 0 {
+		filter["transfer_id"] = bson.M{"$in": settlementIDs}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.transactions.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var legs []models.Transaction
+	if err := cursor.All(ctx, &legs); err != nil {
+		return nil, err
+	}
+	settlements := []models.Settlement{}
+	seen := make(map[string]bool, len(legs))
+	for _, leg := range legs {
+		if seen[leg.TransferID] {
+			continue
+		}
+		seen[leg.TransferID] = true
+		s := models.Settlement{ID: leg.TransferID, GroupID: leg.GroupID, Amount: leg.Cost, Date: leg.Date}
+		if leg.Kind == models.KindSettlementOut {
+			s.FromUserID, s.ToUserID = leg.UserID, leg.CounterpartyID
+		} else {
+			s.FromUserID, s.ToUserID = leg.CounterpartyID, leg.UserID
+		}
+		settlements = append(settlements, s)
+	}
+	return settlements, nil
+}
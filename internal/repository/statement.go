@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type StatementRepo struct {
+	collection   *mongo.Collection
+	transactions *mongo.Collection
+}
+
+func NewStatementRepository(db *mongo.Client) *StatementRepo {
+	return &StatementRepo{
+		collection:   db.Database(dbname).Collection(statementCollection),
+		transactions: db.Database(dbname).Collection(transactionCollection),
+	}
+}
+
+// GetStatementUsers returns the users with transactions in the time frame.
+func (r *StatementRepo) GetStatementUsers(ctx context.Context, dateFrame models.TimeFrame) ([]string, error) {
+	values, err := r.transactions.Distinct(ctx, "user_id", active(bson.M{
+		"date": bson.M{
+			"$gte": dateFrame.StartDate,
+			"$lt":  dateFrame.EndDate,
+		},
+	}))
+	if err != nil {
+		return nil, err
+	}
+	users := make([]string, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(string); ok {
+			users = append(users, id)
+		}
+	}
+	return users, nil
+}
+
+func (r *StatementRepo) IsStatementDelivered(ctx context.Context, userID, period string) (bool, error) {
+	err := r.collection.FindOne(ctx, bson.M{"_id": statementKey(userID, period)}).Err()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *StatementRepo) MarkStatementDelivered(ctx context.Context, userID, period, format string) error {
+	_, err := r.collection.InsertOne(ctx, bson.M{
+		"_id":          statementKey(userID, period),
+		"user_id":      userID,
+		"period":       period,
+		"format":       format,
+		"delivered_at": time.Now().UTC(),
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+func statementKey(userID, period string) string {
+	return userID + ":" + period
+}
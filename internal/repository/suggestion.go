@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CategoryModelRepo struct {
+	collection *mongo.Collection
+}
+
+func NewCategoryModelRepository(db *mongo.Client) *CategoryModelRepo {
+	return &CategoryModelRepo{
+		collection: db.Database(dbname).Collection(categoryModelCollection),
+	}
+}
+
+func (r *CategoryModelRepo) GetCategoryModel(ctx context.Context, userID string) (*models.CategoryModel, error) {
+	var model models.CategoryModel
+	err := r.collection.FindOne(ctx, bson.M{"_id": userID}).Decode(&model)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &model, nil
+}
+
+// InsertCategoryModel stores a freshly trained model unless the user already
+// has one, which may carry increments made while this one was trained. It
+// reports whether the model was inserted.
+func (r *CategoryModelRepo) InsertCategoryModel(ctx context.Context, model models.CategoryModel) (bool, error) {
+	update := bson.M{"$setOnInsert": bson.M{
+		"docs":       model.Docs,
+		"categories": model.Categories,
+		"trained_at": model.TrainedAt,
+	}}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": model.UserID}, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return false, err
+	}
+	return result.UpsertedCount > 0, nil
+}
+
+// IncrementCategoryModel applies counter changes to an already trained
+// model. Users without a model are skipped; theirs is trained from the full
+// history on first use.
+func (r *CategoryModelRepo) IncrementCategoryModel(ctx context.Context, userID string, inc map[string]float64) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": userID}, bson.M{"$inc": inc})
+	return err
+}
+
+func (r *CategoryModelRepo) DeleteCategoryModel(ctx context.Context, userID string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": userID})
+	return err
+}
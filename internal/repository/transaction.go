@@ -4,29 +4,87 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type TransactionRepo struct {
 	collection *mongo.Collection
+	outbox     *mongo.Collection
+	audit      *mongo.Collection
 }
 
 func NewTransactionRepository(db *mongo.Client) *TransactionRepo {
 	return &TransactionRepo{
 		collection: db.Database(dbname).Collection(transactionCollection),
+		outbox:     db.Database(dbname).Collection(outboxCollection),
+		audit:      db.Database(dbname).Collection(auditCollection),
+	}
+}
+
+// withOutbox runs fn in a session transaction and stores the events it
+// returns in the outbox within the same transaction.
+func (r *TransactionRepo) withOutbox(ctx context.Context, fn func(sc mongo.SessionContext) ([]models.Event, error)) error {
+	session, err := r.collection.Database().Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		events, err := fn(sc)
+		if err != nil || len(events) == 0 {
+			return nil, err
+		}
+		docs := make([]interface{}, len(events))
+		for i := range events {
+			docs[i] = events[i]
+		}
+		_, err = r.outbox.InsertMany(sc, docs)
+		return nil, err
+	})
+	return err
+}
+
+// active restricts a filter to transactions that were not merged away.
+func active(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$exists": false}
+	return filter
+}
+
+func newEvent(eventType string, tx models.Transaction) models.Event {
+	return models.Event{
+		Type:          eventType,
+		UserID:        tx.UserID,
+		TransactionID: tx.ID,
+		Transaction:   &tx,
+		OccurredAt:    time.Now().UTC(),
 	}
 }
 
 func (r *TransactionRepo) AddTransaction(ctx context.Context, transaction models.Transaction) (string, error) {
-	result, err := r.collection.InsertOne(ctx, transaction)
+	var id string
+	err := r.withOutbox(ctx, func(sc mongo.SessionContext) ([]models.Event, error) {
+		result, err := r.collection.InsertOne(sc, transaction)
+		if err != nil {
+			return nil, err
+		}
+		id = result.InsertedID.(primitive.ObjectID).Hex()
+		transaction.ID = id
+		events := []models.Event{newEvent(models.EventTransactionCreated, transaction)}
+		if transaction.Anomaly != nil && transaction.Anomaly.Flagged {
+			events = append(events, newEvent(models.EventTransactionAnomaly, transaction))
+		}
+		return events, nil
+	})
 	if err != nil {
 		return "", err
 	}
-	return result.InsertedID.(primitive.ObjectID).Hex(), nil
+	return id, nil
 }
 
 func (r *TransactionRepo) GetTransaction(ctx context.Context, transactionID, userID string) (*models.Transaction, error) {
@@ -35,7 +93,7 @@ func (r *TransactionRepo) GetTransaction(ctx context.Context, transactionID, use
 		return nil, fmt.Errorf("InvalidID: %v", err)
 	}
 	var transaction models.Transaction
-	err = r.collection.FindOne(ctx, bson.M{"_id": oid[0], "user_id": userID}).Decode(&transaction)
+	err = r.collection.FindOne(ctx, active(bson.M{"_id": oid[0], "user_id": userID})).Decode(&transaction)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, nil
@@ -45,16 +103,58 @@ func (r *TransactionRepo) GetTransaction(ctx context.Context, transactionID, use
 	return &transaction, err
 }
 
+// GetTransactionByID finds a transaction whoever wrote it; callers check
+// access themselves.
+func (r *TransactionRepo) GetTransactionByID(ctx context.Context, transactionID string) (*models.Transaction, error) {
+	oid, err := convertToObjectIDs(transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("InvalidID: %v", err)
+	}
+	var transaction models.Transaction
+	err = r.collection.FindOne(ctx, active(bson.M{"_id": oid[0]})).Decode(&transaction)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &transaction, err
+}
+
+// GetLedgerTransactions returns the transactions every member wrote in the
+// ledger.
+func (r *TransactionRepo) GetLedgerTransactions(ctx context.Context, ledgerID string, dateFrame models.TimeFrame) ([]models.Transaction, error) {
+	transactions := []models.Transaction{}
+	filter := active(bson.M{
+		"ledger_id": ledgerID,
+		"date": bson.M{
+			"$gt": dateFrame.StartDate,
+			"$lt": dateFrame.EndDate,
+		},
+	})
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &transactions)
+	if err != nil {
+		return nil, err
+	}
+	return transactions, err
+}
+
 func (r *TransactionRepo) GetTXByTimeFrame(ctx context.Context, userID string, dateFrame models.TimeFrame) ([]models.Transaction, error) {
 	transactions := []models.Transaction{}
-	filter := bson.M{
+	filter := active(bson.M{
 		"user_id": userID,
 		"date": bson.M{
 			"$gt": dateFrame.StartDate,
 			"$lt": dateFrame.EndDate,
 		},
-	}
-	cursor, err := r.collection.Find(ctx, filter)
+	})
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +167,7 @@ func (r *TransactionRepo) GetTXByTimeFrame(ctx context.Context, userID string, d
 
 func (r *TransactionRepo) GetAllTransactions(ctx context.Context, userID string) ([]models.Transaction, error) {
 	transactions := []models.Transaction{}
-	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID})
+	cursor, err := r.collection.Find(ctx, active(bson.M{"user_id": userID}))
 	if err != nil {
 		return nil, err
 	}
@@ -83,33 +183,482 @@ func (r *TransactionRepo) DeleteTx(ctx context.Context, userID, txID string) err
 	if err != nil {
 		return fmt.Errorf("InvalidID: %v", err)
 	}
-	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": oid[0],"user_id": userID})
-	if err != nil {
-		return err
-	}
-	return err
+	return r.withOutbox(ctx, func(sc mongo.SessionContext) ([]models.Event, error) {
+		var deleted models.Transaction
+		err := r.collection.FindOneAndDelete(sc, bson.M{"_id": oid[0], "user_id": userID}).Decode(&deleted)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return []models.Event{newEvent(models.EventTransactionDeleted, deleted)}, nil
+	})
 }
+
 func (r *TransactionRepo) UpdateTx(ctx context.Context, updates models.Transaction) error {
 	oid, err := convertToObjectIDs(updates.ID)
 	if err != nil {
 		return fmt.Errorf("InvalidID: %v", err)
 	}
-	filter := bson.M{"_id": oid[0], "user_id": updates.UserID}
+	filter := active(bson.M{"_id": oid[0], "user_id": updates.UserID})
 	update := bson.M{
 		"$set": bson.M{
 			"name":     updates.Name,
 			"cost":     updates.Cost,
 			"category": updates.Category,
 			"date":     updates.Date,
+			"splits":   updates.Splits,
+			"tags":     updates.Tags,
+			"note":     updates.Note,
+		},
+	}
+	unset := bson.M{}
+	if updates.AccountID != "" {
+		update["$set"].(bson.M)["account_id"] = updates.AccountID
+	} else {
+		unset["account_id"] = ""
+	}
+	if updates.PayeeID != "" {
+		update["$set"].(bson.M)["payee_id"] = updates.PayeeID
+	} else {
+		unset["payee_id"] = ""
+	}
+	if updates.CategorySetBy != "" {
+		update["$set"].(bson.M)["category_set_by"] = updates.CategorySetBy
+	} else {
+		unset["category_set_by"] = ""
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	return r.withOutbox(ctx, func(sc mongo.SessionContext) ([]models.Event, error) {
+		result, err := r.collection.UpdateOne(sc, filter, update)
+		if err != nil {
+			return nil, err
+		}
+		if result.ModifiedCount == 0 {
+			return nil, errors.New("UpdateTx error: not updated")
+		}
+		return []models.Event{newEvent(models.EventTransactionUpdated, updates)}, nil
+	})
+}
+
+func (r *TransactionRepo) GetCategoryTotals(ctx context.Context, userID string, dateFrame models.TimeFrame) ([]models.CategoryTotal, error) {
+	totals := []models.CategoryTotal{}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: active(bson.M{
+			"user_id": userID,
+			"date": bson.M{
+				"$gt": dateFrame.StartDate,
+				"$lt": dateFrame.EndDate,
+			},
+			"kind": bson.M{"$nin": nonSpendingKinds()},
+		})}},
+		{{Key: "$project", Value: bson.M{"lines": splitLines()}}},
+		{{Key: "$unwind", Value: "$lines"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$lines.category",
+			"total": bson.M{"$sum": "$lines.amount"},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "total", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &totals)
+	if err != nil {
+		return nil, err
+	}
+	return totals, err
+}
+
+func (r *TransactionRepo) GetPayeeTotals(ctx context.Context, userID string, dateFrame models.TimeFrame) ([]models.PayeeTotal, error) {
+	totals := []models.PayeeTotal{}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: active(bson.M{
+			"user_id": userID,
+			"date": bson.M{
+				"$gt": dateFrame.StartDate,
+				"$lt": dateFrame.EndDate,
+			},
+			"kind": bson.M{"$nin": nonSpendingKinds()},
+		})}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"$ifNull": bson.A{"$payee_id", ""}},
+			"total": bson.M{"$sum": "$cost"},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "total", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &totals)
+	if err != nil {
+		return nil, err
+	}
+	return totals, err
+}
+
+func nonSpendingKinds() bson.A {
+	return bson.A{models.KindTransferOut, models.KindTransferIn, models.KindSettlementOut, models.KindSettlementIn, models.KindContribution}
+}
+
+// splitLines expands a transaction into {category, amount} lines: its splits
+// when present, otherwise a single line for the parent category and cost.
+func splitLines() bson.M {
+	return bson.M{"$cond": bson.A{
+		bson.M{"$gt": bson.A{bson.M{"$size": bson.M{"$ifNull": bson.A{"$splits", bson.A{}}}}, 0}},
+		bson.M{"$map": bson.M{
+			"input": "$splits",
+			"as":    "s",
+			"in":    bson.M{"category": "$$s.category", "amount": "$$s.amount"},
+		}},
+		bson.A{bson.M{"category": "$category", "amount": "$cost"}},
+	}}
+}
+
+func (r *TransactionRepo) AddTransfer(ctx context.Context, outgoing, incoming models.Transaction) (*models.Transfer, error) {
+	transfer := &models.Transfer{ID: primitive.NewObjectID().Hex()}
+	outgoing.TransferID = transfer.ID
+	incoming.TransferID = transfer.ID
+
+	err := r.withOutbox(ctx, func(sc mongo.SessionContext) ([]models.Event, error) {
+		out, err := r.collection.InsertOne(sc, outgoing)
+		if err != nil {
+			return nil, err
+		}
+		in, err := r.collection.InsertOne(sc, incoming)
+		if err != nil {
+			return nil, err
+		}
+		transfer.OutgoingTxID = out.InsertedID.(primitive.ObjectID).Hex()
+		transfer.IncomingTxID = in.InsertedID.(primitive.ObjectID).Hex()
+		outgoing.ID = transfer.OutgoingTxID
+		incoming.ID = transfer.IncomingTxID
+		return []models.Event{
+			newEvent(models.EventTransactionCreated, outgoing),
+			newEvent(models.EventTransactionCreated, incoming),
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// DeleteTransfer removes both legs of a transfer and returns their IDs.
+func (r *TransactionRepo) DeleteTransfer(ctx context.Context, userID, transferID string) ([]string, error) {
+	filter := bson.M{"user_id": userID, "transfer_id": transferID}
+	var ids []string
+	err := r.withOutbox(ctx, func(sc mongo.SessionContext) ([]models.Event, error) {
+		cursor, err := r.collection.Find(sc, filter)
+		if err != nil {
+			return nil, err
+		}
+		var legs []models.Transaction
+		if err := cursor.All(sc, &legs); err != nil {
+			return nil, err
+		}
+		if _, err := r.collection.DeleteMany(sc, filter); err != nil {
+			return nil, err
+		}
+		ids = make([]string, len(legs))
+		events := make([]models.Event, len(legs))
+		for i, leg := range legs {
+			ids[i] = leg.ID
+			events[i] = newEvent(models.EventTransactionDeleted, leg)
+		}
+		return events, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// GetCashFlow returns the transactions in the time frame ordered by date then
+// _id, each with its signed amount and the running sum of amounts up to and
+// including it.
+func (r *TransactionRepo) GetCashFlow(ctx context.Context, userID, accountID string, dateFrame models.TimeFrame) ([]models.CashFlowEntry, error) {
+	entries := []models.CashFlowEntry{}
+	match := active(bson.M{
+		"user_id": userID,
+		"date": bson.M{
+			"$gt": dateFrame.StartDate,
+			"$lt": dateFrame.EndDate,
+		},
+	})
+	if accountID != "" {
+		match["account_id"] = accountID
+	}
+	order := bson.D{{Key: "date", Value: 1}, {Key: "_id", Value: 1}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$set", Value: bson.M{"amount": signedCost()}}},
+		{{Key: "$setWindowFields", Value: bson.M{
+			"sortBy": order,
+			"output": bson.M{
+				"balance": bson.M{
+					"$sum":   "$amount",
+					"window": bson.M{"documents": bson.A{"unbounded", "current"}},
+				},
+			},
+		}}},
+		{{Key: "$sort", Value: order}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &entries)
+	if err != nil {
+		return nil, err
+	}
+	return entries, err
+}
+
+// signedCost is the effect of a transaction on its account, mirroring
+// models.Transaction.AccountEffect.
+func signedCost() bson.M {
+	kind := bson.M{"$ifNull": bson.A{"$kind", ""}}
+	return bson.M{"$switch": bson.M{
+		"branches": bson.A{
+			bson.M{"case": bson.M{"$in": bson.A{kind, models.InflowKinds}}, "then": "$cost"},
+			bson.M{"case": bson.M{"$in": bson.A{kind, models.OutflowKinds}}, "then": bson.M{"$multiply": bson.A{"$cost", -1}}},
+		},
+		"default": 0,
+	}}
+}
+
+func (r *TransactionRepo) GetAccountFlow(ctx context.Context, userID, accountID string, until time.Time) (float64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: active(bson.M{
+			"user_id":    userID,
+			"account_id": accountID,
+			"date":       bson.M{"$lte": until},
+		})}},
+		{{Key: "$group", Value: bson.M{
+			"_id":  nil,
+			"flow": bson.M{"$sum": signedCost()},
+		}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	var result []struct {
+		Flow float64 `bson:"flow"`
+	}
+	if err := cursor.All(ctx, &result); err != nil {
+		return 0, err
+	}
+	if len(result) == 0 {
+		return 0, nil
+	}
+	return result[0].Flow, nil
+}
+
+// GetTagContributions sums the user's goal contributions tagged with tag up
+// to until.
+func (r *TransactionRepo) GetTagContributions(ctx context.Context, userID, tag string, until time.Time) (float64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: active(bson.M{
+			"user_id": userID,
+			"kind":    models.KindContribution,
+			"tags":    tag,
+			"date":    bson.M{"$lte": until},
+		})}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   nil,
+			"saved": bson.M{"$sum": "$cost"},
+		}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	var result []struct {
+		Saved float64 `bson:"saved"`
+	}
+	if err := cursor.All(ctx, &result); err != nil {
+		return 0, err
+	}
+	if len(result) == 0 {
+		return 0, nil
+	}
+	return result[0].Saved, nil
+}
+
+func (r *TransactionRepo) CountAccountTransactions(ctx context.Context, userID, accountID string) (int64, error) {
+	return r.collection.CountDocuments(ctx, active(bson.M{"user_id": userID, "account_id": accountID}))
+}
+
+// MergeTransactions saves keep and soft-deletes the transactions in removed,
+// pointing them at keep and recording entry in the audit log.
+func (r *TransactionRepo) MergeTransactions(ctx context.Context, keep models.Transaction, removed []models.Transaction, entry models.AuditEntry) error {
+	keepID, err := convertToObjectIDs(keep.ID)
+	if err != nil {
+		return fmt.Errorf("InvalidID: %v", err)
+	}
+	ids := make([]string, len(removed))
+	for i, tx := range removed {
+		ids[i] = tx.ID
+	}
+	oids, err := convertToObjectIDs(ids...)
+	if err != nil {
+		return fmt.Errorf("InvalidID: %v", err)
+	}
+	now := time.Now().UTC()
+	return r.withOutbox(ctx, func(sc mongo.SessionContext) ([]models.Event, error) {
+		result, err := r.collection.UpdateOne(sc, active(bson.M{"_id": keepID[0], "user_id": keep.UserID}),
+			bson.M{"$set": bson.M{"tags": keep.Tags, "note": keep.Note}})
+		if err != nil {
+			return nil, err
+		}
+		if result.MatchedCount == 0 {
+			return nil, errors.New("MergeTransactions error: kept transaction not found")
+		}
+		result, err = r.collection.UpdateMany(sc, active(bson.M{"_id": bson.M{"$in": oids}, "user_id": keep.UserID}),
+			bson.M{"$set": bson.M{"deleted_at": now, "merged_into": keep.ID}})
+		if err != nil {
+			return nil, err
+		}
+		if result.ModifiedCount != int64(len(oids)) {
+			return nil, errors.New("MergeTransactions error: transactions changed during merge")
+		}
+		entry.CreatedAt = now
+		if _, err := r.audit.InsertOne(sc, entry); err != nil {
+			return nil, err
+		}
+		events := []models.Event{newEvent(models.EventTransactionUpdated, keep)}
+		for _, tx := range removed {
+			events = append(events, newEvent(models.EventTransactionDeleted, tx))
+		}
+		return events, nil
+	})
+}
+
+// GetRecentCosts returns the costs of the user's latest expenses, limited to
+// one category unless category is empty.
+func (r *TransactionRepo) GetRecentCosts(ctx context.Context, userID, category string, limit int) ([]float64, error) {
+	filter := active(bson.M{
+		"user_id": userID,
+		"kind":    bson.M{"$nin": nonSpendingKinds()},
+	})
+	if category != "" {
+		filter["category"] = category
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "date", Value: -1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"cost": 1})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var docs []struct {
+		Cost float64 `bson:"cost"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	costs := make([]float64, len(docs))
+	for i, d := range docs {
+		costs[i] = d.Cost
+	}
+	return costs, nil
+}
+
+func (r *TransactionRepo) CountPayeeTransactions(ctx context.Context, userID, payeeID string) (int64, error) {
+	return r.collection.CountDocuments(ctx, active(bson.M{"user_id": userID, "payee_id": payeeID}))
+}
+
+func (r *TransactionRepo) GetAnomalies(ctx context.Context, userID string, minScore float64, dateFrame models.TimeFrame, limit int) ([]models.Transaction, error) {
+	transactions := []models.Transaction{}
+	filter := active(bson.M{
+		"user_id":       userID,
+		"anomaly.score": bson.M{"$gte": minScore},
+		"date": bson.M{
+			"$gt": dateFrame.StartDate,
+			"$lt": dateFrame.EndDate,
 		},
+	})
+	opts := options.Find().
+		SetSort(bson.D{{Key: "anomaly.score", Value: -1}, {Key: "date", Value: -1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
 	}
+	err = cursor.All(ctx, &transactions)
+	if err != nil {
+		return nil, err
+	}
+	return transactions, err
+}
 
-	result, err := r.collection.UpdateOne(ctx, filter, update)
+// StreamUserTransactions passes every transaction of the user to fn,
+// including soft deleted ones.
+func (r *TransactionRepo) StreamUserTransactions(ctx context.Context, userID string, fn func(models.Transaction) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
 	if err != nil {
 		return err
 	}
-	if result.ModifiedCount == 0 {
-		return errors.New("UpdateTx error: not updated")
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var tx models.Transaction
+		if err := cursor.Decode(&tx); err != nil {
+			return err
+		}
+		if err := fn(tx); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// DeleteUserTransactions removes every transaction of the user for good. It
+// publishes no events, as those would carry the erased data.
+func (r *TransactionRepo) DeleteUserTransactions(ctx context.Context, userID string) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (r *TransactionRepo) CountUserTransactions(ctx context.Context, userID string) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"user_id": userID})
+}
+
+// GetUserIDs returns every user that has transactions, soft deleted ones
+// included.
+func (r *TransactionRepo) GetUserIDs(ctx context.Context) ([]string, error) {
+	values, err := r.collection.Distinct(ctx, "user_id", bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(string); ok {
+			ids = append(ids, id)
+		}
 	}
-	return nil
+	return ids, nil
+}
+
+// CountTransactionsSince counts the transactions the user created since the
+// given time, by the creation time in their IDs. Deleted ones count too.
+func (r *TransactionRepo) CountTransactionsSince(ctx context.Context, userID string, since time.Time) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{
+		"user_id": userID,
+		"_id":     bson.M{"$gte": primitive.NewObjectIDFromTimestamp(since)},
+	})
 }
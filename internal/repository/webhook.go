@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WebhookRepo struct {
+	webhooks   *mongo.Collection
+	deliveries *mongo.Collection
+}
+
+func NewWebhookRepository(db *mongo.Client) *WebhookRepo {
+	return &WebhookRepo{
+		webhooks:   db.Database(dbname).Collection(webhookCollection),
+		deliveries: db.Database(dbname).Collection(deliveryCollection),
+	}
+}
+
+func (r *WebhookRepo) AddWebhook(ctx context.Context, webhook models.Webhook) (string, error) {
+	result, err := r.webhooks.InsertOne(ctx, webhook)
+	if err != nil {
+		return "", err
+	}
+	return result.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (r *WebhookRepo) GetWebhook(ctx context.Context, webhookID, userID string) (*models.Webhook, error) {
+	oid, err := convertToObjectIDs(webhookID)
+	if err != nil {
+		return nil, fmt.Errorf("InvalidID: %v", err)
+	}
+	filter := bson.M{"_id": oid[0]}
+	if userID != "" {
+		filter["user_id"] = userID
+	}
+	var webhook models.Webhook
+	err = r.webhooks.FindOne(ctx, filter).Decode(&webhook)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (r *WebhookRepo) GetWebhooks(ctx context.Context, userID string) ([]models.Webhook, error) {
+	webhooks := []models.Webhook{}
+	cursor, err := r.webhooks.Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &webhooks)
+	if err != nil {
+		return nil, err
+	}
+	return webhooks, err
+}
+
+// DeleteWebhook removes the webhook along with its pending deliveries; the
+// delivered and dead ones stay in the log.
+func (r *WebhookRepo) DeleteWebhook(ctx context.Context, webhookID, userID string) error {
+	oid, err := convertToObjectIDs(webhookID)
+	if err != nil {
+		return fmt.Errorf("InvalidID: %v", err)
+	}
+	_, err = r.webhooks.DeleteOne(ctx, bson.M{"_id": oid[0], "user_id": userID})
+	if err != nil {
+		return err
+	}
+	_, err = r.deliveries.DeleteMany(ctx, bson.M{"webhook_id": webhookID, "status": models.DeliveryPending})
+	return err
+}
+
+// EnqueueDelivery stores a delivery once per event and webhook, so relaying
+// the same event again does not deliver it twice. The unique index on both
+// keeps concurrent relays from inserting it twice; the loser's duplicate
+// key error means the delivery is already queued.
+func (r *WebhookRepo) EnqueueDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	filter := bson.M{"event_id": delivery.EventID, "webhook_id": delivery.WebhookID}
+	_, err := r.deliveries.UpdateOne(ctx, filter, bson.M{"$setOnInsert": delivery}, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+// ClaimDueDeliveries picks up to limit pending deliveries that are due and
+// pushes their next attempt back by lease, so that other workers skip them
+// while they are being sent.
+func (r *WebhookRepo) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	deliveries := []models.WebhookDelivery{}
+	filter := bson.M{"status": models.DeliveryPending, "next_attempt_at": bson.M{"$lte": now}}
+	update := bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetReturnDocument(options.After)
+	for len(deliveries) < limit {
+		var delivery models.WebhookDelivery
+		err := r.deliveries.FindOneAndUpdate(ctx, filter, update, opts).Decode(&delivery)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return deliveries, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+func (r *WebhookRepo) MarkDelivered(ctx context.Context, deliveryID string, responseCode int) error {
+	oid, err := convertToObjectIDs(deliveryID)
+	if err != nil {
+		return fmt.Errorf("InvalidID: %v", err)
+	}
+	_, err = r.deliveries.UpdateByID(ctx, oid[0], bson.M{
+		"$set": bson.M{
+			"status":        models.DeliveryDelivered,
+			"response_code": responseCode,
+			"delivered_at":  time.Now().UTC(),
+		},
+		"$inc":   bson.M{"attempts": 1},
+		"$unset": bson.M{"last_error": ""},
+	})
+	return err
+}
+
+// MarkFailed records a failed attempt. The delivery is retried at
+// nextAttempt, or moved to the dead letters when nextAttempt is nil.
+func (r *WebhookRepo) MarkFailed(ctx context.Context, deliveryID string, responseCode int, cause string, nextAttempt *time.Time) error {
+	oid, err := convertToObjectIDs(deliveryID)
+	if err != nil {
+		return fmt.Errorf("InvalidID: %v", err)
+	}
+	set := bson.M{"response_code": responseCode, "last_error": cause}
+	if nextAttempt != nil {
+		set["next_attempt_at"] = *nextAttempt
+	} else {
+		set["status"] = models.DeliveryDead
+	}
+	_, err = r.deliveries.UpdateByID(ctx, oid[0], bson.M{"$set": set, "$inc": bson.M{"attempts": 1}})
+	return err
+}
+
+func (r *WebhookRepo) GetDeliveries(ctx context.Context, filter models.DeliveryFilter) ([]models.WebhookDelivery, error) {
+	deliveries := []models.WebhookDelivery{}
+	query := bson.M{"user_id": filter.UserID, "webhook_id": filter.WebhookID}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(filter.Limit)).
+		SetProjection(bson.M{"payload": 0})
+	cursor, err := r.deliveries.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &deliveries)
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, err
+}
+
+// RetryDelivery moves a dead delivery back to the queue with a fresh attempt
+// budget.
+func (r *WebhookRepo) RetryDelivery(ctx context.Context, deliveryID, userID string) (bool, error) {
+	oid, err := convertToObjectIDs(deliveryID)
+	if err != nil {
+		return false, fmt.Errorf("InvalidID: %v", err)
+	}
+	result, err := r.deliveries.UpdateOne(ctx,
+		bson.M{"_id": oid[0], "user_id": userID, "status": models.DeliveryDead},
+		bson.M{"$set": bson.M{
+			"status":          models.DeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now().UTC(),
+		}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
@@ -0,0 +1,107 @@
+package rules
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+// Engine evaluates a user's enabled rules in priority order, highest first.
+// The first matching rule that sets a category decides it; tags of all
+// matching rules are collected.
+type Engine struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	models.Rule
+	contains string
+	pattern  *regexp.Regexp
+}
+
+type Result struct {
+	Category string
+	Tags     []string
+	RuleIDs  []string
+}
+
+func New(rules []models.Rule) (*Engine, error) {
+	e := &Engine{}
+	for _, r := range rules {
+		if !r.Enabled {
+			continue
+		}
+		c := compiledRule{Rule: r, contains: strings.ToLower(r.Conditions.NameContains)}
+		if r.Conditions.NamePattern != "" {
+			pattern, err := Compile(r.Conditions.NamePattern)
+			if err != nil {
+				return nil, err
+			}
+			c.pattern = pattern
+		}
+		e.rules = append(e.rules, c)
+	}
+	sort.SliceStable(e.rules, func(i, j int) bool {
+		if e.rules[i].Priority != e.rules[j].Priority {
+			return e.rules[i].Priority > e.rules[j].Priority
+		}
+		return e.rules[i].CreatedAt.Before(e.rules[j].CreatedAt)
+	})
+	return e, nil
+}
+
+// Compile compiles a name pattern the way rules match it: case-insensitively.
+func Compile(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + pattern)
+}
+
+func (e *Engine) Apply(tx models.Transaction) Result {
+	var result Result
+	for _, r := range e.rules {
+		if !r.matches(tx) {
+			continue
+		}
+		result.RuleIDs = append(result.RuleIDs, r.ID)
+		if result.Category == "" {
+			result.Category = r.Actions.Category
+		}
+		result.Tags = MergeTags(result.Tags, r.Actions.Tags)
+	}
+	return result
+}
+
+func (r compiledRule) matches(tx models.Transaction) bool {
+	cond := r.Conditions
+	if r.contains != "" && !strings.Contains(strings.ToLower(tx.Name), r.contains) {
+		return false
+	}
+	if r.pattern != nil && !r.pattern.MatchString(tx.Name) {
+		return false
+	}
+	if cond.MinAmount != nil && tx.Cost < *cond.MinAmount {
+		return false
+	}
+	if cond.MaxAmount != nil && tx.Cost > *cond.MaxAmount {
+		return false
+	}
+	if len(cond.Weekdays) > 0 && !slices.Contains(cond.Weekdays, int(tx.Date.Weekday())) {
+		return false
+	}
+	if cond.AccountID != "" && tx.AccountID != cond.AccountID {
+		return false
+	}
+	return true
+}
+
+// MergeTags appends the tags missing from existing, keeping their order.
+func MergeTags(existing, tags []string) []string {
+	for _, tag := range tags {
+		if !slices.Contains(existing, tag) {
+			existing = append(existing, tag)
+		}
+	}
+	return existing
+}
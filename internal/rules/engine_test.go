@@ -0,0 +1,88 @@
+package rules
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+func amount(v float64) *float64 { return &v }
+
+func TestApply(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	engine, err := New([]models.Rule{
+		{ID: "coffee", Enabled: true, Priority: 1, CreatedAt: created,
+			Conditions: models.RuleConditions{NameContains: "COFFEE"},
+			Actions:    models.RuleActions{Category: "cafe", Tags: []string{"coffee"}}},
+		{ID: "big-coffee", Enabled: true, Priority: 5, CreatedAt: created,
+			Conditions: models.RuleConditions{NamePattern: `^coffee\b`, MinAmount: amount(20)},
+			Actions:    models.RuleActions{Category: "treats"}},
+		{ID: "weekend", Enabled: true, Priority: 1, CreatedAt: created.Add(time.Hour),
+			Conditions: models.RuleConditions{Weekdays: []int{0, 6}},
+			Actions:    models.RuleActions{Category: "leisure", Tags: []string{"weekend", "coffee"}}},
+		{ID: "card", Enabled: true, CreatedAt: created,
+			Conditions: models.RuleConditions{AccountID: "card", MaxAmount: amount(5)},
+			Actions:    models.RuleActions{Tags: []string{"small"}}},
+		{ID: "disabled", Priority: 100,
+			Conditions: models.RuleConditions{NameContains: "coffee"},
+			Actions:    models.RuleActions{Category: "never"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	saturday := time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC)
+	monday := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		tx   models.Transaction
+		want Result
+	}{
+		{
+			name: "substring matches case-insensitively",
+			tx:   models.Transaction{Name: "Morning coffee", Cost: 3, Date: monday},
+			want: Result{Category: "cafe", Tags: []string{"coffee"}, RuleIDs: []string{"coffee"}},
+		},
+		{
+			name: "higher priority decides the category, tags are merged",
+			tx:   models.Transaction{Name: "Coffee beans", Cost: 25, Date: saturday},
+			want: Result{Category: "treats", Tags: []string{"coffee", "weekend"}, RuleIDs: []string{"big-coffee", "coffee", "weekend"}},
+		},
+		{
+			name: "equal priority goes to the older rule",
+			tx:   models.Transaction{Name: "coffee", Cost: 3, Date: saturday},
+			want: Result{Category: "cafe", Tags: []string{"coffee", "weekend"}, RuleIDs: []string{"coffee", "weekend"}},
+		},
+		{
+			name: "account and amount range",
+			tx:   models.Transaction{Name: "Bus", Cost: 2, AccountID: "card", Date: monday},
+			want: Result{Tags: []string{"small"}, RuleIDs: []string{"card"}},
+		},
+		{
+			name: "nothing matches",
+			tx:   models.Transaction{Name: "Bus", Cost: 10, AccountID: "card", Date: monday},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := engine.Apply(tt.tx); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRejectsInvalidPattern(t *testing.T) {
+	_, err := New([]models.Rule{{Enabled: true, Conditions: models.RuleConditions{NamePattern: "("}}})
+	if err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	got := MergeTags([]string{"a", "b"}, []string{"b", "c", "a", "d"})
+	if want := []string{"a", "b", "c", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type AccountRepository interface {
+	AddAccount(ctx context.Context, account models.Account) (string, error)
+	GetAccount(ctx context.Context, accountID, userID string) (*models.Account, error)
+	GetAccounts(ctx context.Context, userID string) ([]models.Account, error)
+	UpdateAccount(ctx context.Context, account models.Account) error
+	DeleteAccount(ctx context.Context, accountID, userID string) error
+}
+
+type AccountTransactionRepository interface {
+	AddTransfer(ctx context.Context, outgoing, incoming models.Transaction) (*models.Transfer, error)
+	GetAccountFlow(ctx context.Context, userID, accountID string, until time.Time) (float64, error)
+	CountAccountTransactions(ctx context.Context, userID, accountID string) (int64, error)
+	CountTransactionsSince(ctx context.Context, userID string, since time.Time) (int64, error)
+}
+
+type AccountService struct {
+	AccountRepo     AccountRepository
+	TransactionRepo AccountTransactionRepository
+	User            UserService
+	Access          AccessPolicy
+	Quota           TransactionQuota
+	validate        *Validator
+}
+
+func NewAccountService(accountRepo AccountRepository, txRepo AccountTransactionRepository, user UserService, access AccessPolicy, quota TransactionQuota) *AccountService {
+	return &AccountService{AccountRepo: accountRepo, TransactionRepo: txRepo,
+		User: user, Access: access, Quota: quota, validate: NewValidator()}
+}
+
+var errAccountNotFound = status.Error(codes.NotFound, "account is not found")
+
+func (s *AccountService) CreateAccount(ctx context.Context, account models.CreateAccount) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "AccountService.CreateAccount", trace.WithAttributes(attribute.String("user.id", account.UserID)))
+	defer func() { endSpan(span, err) }()
+	account.Currency = strings.ToUpper(account.Currency)
+	if err := s.validate.Struct(account); err != nil {
+		return "", err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, account.UserID); err != nil {
+		return "", err
+	}
+	id, err := s.AccountRepo.AddAccount(ctx, models.Account{
+		UserID:         account.UserID,
+		Name:           account.Name,
+		Type:           account.Type,
+		Currency:       account.Currency,
+		OpeningBalance: account.OpeningBalance,
+		CreatedAt:      time.Now().UTC(),
+	})
+	if err != nil {
+		log.Println(err)
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *AccountService) GetAccount(ctx context.Context, accountID, userID string) (_ *models.Account, err error) {
+	ctx, span := tracer.Start(ctx, "AccountService.GetAccount", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.AccountKey{ID: accountID, UserID: userID}); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, userID); err != nil {
+		return nil, err
+	}
+	return s.getAccount(ctx, accountID, userID)
+}
+
+func (s *AccountService) GetAccounts(ctx context.Context, userID string) (_ []models.Account, err error) {
+	ctx, span := tracer.Start(ctx, "AccountService.GetAccounts", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.UserKey{UserID: userID}); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, userID); err != nil {
+		return nil, err
+	}
+	accounts, err := s.AccountRepo.GetAccounts(ctx, userID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (s *AccountService) UpdateAccount(ctx context.Context, updates models.UpdateAccount) (_ *models.Account, err error) {
+	ctx, span := tracer.Start(ctx, "AccountService.UpdateAccount", trace.WithAttributes(attribute.String("user.id", updates.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(updates); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, updates.UserID); err != nil {
+		return nil, err
+	}
+	account, err := s.getAccount(ctx, updates.ID, updates.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if updates.Name != nil {
+		account.Name = *updates.Name
+	}
+	if updates.Type != nil {
+		account.Type = *updates.Type
+	}
+	if err := s.AccountRepo.UpdateAccount(ctx, *account); err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return account, nil
+}
+
+func (s *AccountService) DeleteAccount(ctx context.Context, accountID, userID string) (err error) {
+	ctx, span := tracer.Start(ctx, "AccountService.DeleteAccount", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.AccountKey{ID: accountID, UserID: userID}); err != nil {
+		return err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, userID); err != nil {
+		return err
+	}
+	if _, err := s.getAccount(ctx, accountID, userID); err != nil {
+		return err
+	}
+	n, err := s.TransactionRepo.CountAccountTransactions(ctx, userID, accountID)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	if n > 0 {
+		return status.Error(codes.FailedPrecondition, "account still has transactions")
+	}
+	if err := s.AccountRepo.DeleteAccount(ctx, accountID, userID); err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (s *AccountService) Transfer(ctx context.Context, transfer models.CreateTransfer) (_ *models.Transfer, err error) {
+	ctx, span := tracer.Start(ctx, "AccountService.Transfer", trace.WithAttributes(attribute.String("user.id", transfer.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(transfer); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, transfer.UserID); err != nil {
+		return nil, err
+	}
+	from, err := s.getAccount(ctx, transfer.FromAccountID, transfer.UserID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.getAccount(ctx, transfer.ToAccountID, transfer.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if from.Currency != to.Currency {
+		return nil, status.Error(codes.FailedPrecondition, "transfers between accounts in different currencies are not supported")
+	}
+	if err := checkTransactionQuota(ctx, s.TransactionRepo, s.Quota, transfer.UserID, 2); err != nil {
+		return nil, err
+	}
+	date := time.Now().UTC()
+	if transfer.Date != nil {
+		date, err = time.Parse(DateTimeformat, *transfer.Date)
+		if err != nil {
+			return nil, err
+		}
+	}
+	name := transfer.Note
+	if name == "" {
+		name = from.Name + " → " + to.Name
+	}
+	leg := func(accountID, kind string) models.Transaction {
+		return models.Transaction{
+			UserID:    transfer.UserID,
+			Category:  models.TransferCategory,
+			Name:      name,
+			Cost:      transfer.Amount,
+			Date:      date,
+			AccountID: accountID,
+			Kind:      kind,
+		}
+	}
+	result, err := s.TransactionRepo.AddTransfer(ctx, leg(from.ID, models.KindTransferOut), leg(to.ID, models.KindTransferIn))
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *AccountService) GetAccountBalance(ctx context.Context, accountID, userID, asOf string) (_ *models.AccountBalance, err error) {
+	ctx, span := tracer.Start(ctx, "AccountService.GetAccountBalance", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.AccountKey{ID: accountID, UserID: userID}, models.CreateTimeFrame{EndDate: asOf}); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, userID); err != nil {
+		return nil, err
+	}
+	account, err := s.getAccount(ctx, accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+	until := time.Now().UTC()
+	if asOf != "" {
+		tf, err := parseTimeFrame(models.CreateTimeFrame{EndDate: asOf})
+		if err != nil {
+			return nil, err
+		}
+		until = tf.EndDate
+	}
+	flow, err := s.TransactionRepo.GetAccountFlow(ctx, userID, accountID, until)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return &models.AccountBalance{
+		AccountID: account.ID,
+		Currency:  account.Currency,
+		AsOf:      until,
+		Balance:   account.OpeningBalance + flow,
+	}, nil
+}
+
+func (s *AccountService) getAccount(ctx context.Context, accountID, userID string) (*models.Account, error) {
+	account, err := s.AccountRepo.GetAccount(ctx, accountID, userID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if account == nil {
+		return nil, errAccountNotFound
+	}
+	return account, nil
+}
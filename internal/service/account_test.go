@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeAccountRepo struct {
+	accounts map[string]models.Account
+}
+
+func (r *fakeAccountRepo) AddAccount(_ context.Context, account models.Account) (string, error) {
+	account.ID = fmt.Sprintf("6650a1f1c2a4b5e6f7a8b9%02x", len(r.accounts)+1)
+	r.accounts[account.ID] = account
+	return account.ID, nil
+}
+
+func (r *fakeAccountRepo) GetAccount(_ context.Context, accountID, userID string) (*models.Account, error) {
+	account, ok := r.accounts[accountID]
+	if !ok || account.UserID != userID {
+		return nil, nil
+	}
+	return &account, nil
+}
+
+func (r *fakeAccountRepo) GetAccounts(context.Context, string) ([]models.Account, error) {
+	return nil, nil
+}
+
+func (r *fakeAccountRepo) UpdateAccount(_ context.Context, account models.Account) error {
+	r.accounts[account.ID] = account
+	return nil
+}
+
+func (r *fakeAccountRepo) DeleteAccount(_ context.Context, accountID, _ string) error {
+	delete(r.accounts, accountID)
+	return nil
+}
+
+func newAccountFixture(t *testing.T) (*AccountService, *memTxRepo) {
+	t.Helper()
+	ledger := &memTxRepo{}
+	srv := NewAccountService(&fakeAccountRepo{accounts: map[string]models.Account{}}, ledger, knownUsers{"alice", "bob"}, allowAll{}, TransactionQuota{})
+	return srv, ledger
+}
+
+func createAccount(t *testing.T, srv *AccountService, userID, name, currency string, opening float64) string {
+	t.Helper()
+	id, err := srv.CreateAccount(context.Background(), models.CreateAccount{UserID: userID, Name: name, Type: "bank", Currency: currency, OpeningBalance: opening})
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	return id
+}
+
+func TestTransfer(t *testing.T) {
+	ctx := context.Background()
+	srv, ledger := newAccountFixture(t)
+	checking := createAccount(t, srv, "alice", "Checking", "usd", 100)
+	savings := createAccount(t, srv, "alice", "Savings", "USD", 0)
+	euros := createAccount(t, srv, "alice", "Euros", "EUR", 0)
+	bobs := createAccount(t, srv, "bob", "Bob's", "USD", 0)
+
+	if _, err := srv.Transfer(ctx, models.CreateTransfer{UserID: "alice", FromAccountID: checking, ToAccountID: savings, Amount: 40}); err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+	if len(ledger.txs) != 2 || ledger.txs[0].TransferID != ledger.txs[1].TransferID {
+		t.Fatalf("want two linked legs, got %+v", ledger.txs)
+	}
+	out, in := ledger.txs[0], ledger.txs[1]
+	if out.Kind != models.KindTransferOut || out.AccountID != checking || in.Kind != models.KindTransferIn || in.AccountID != savings {
+		t.Errorf("legs: got %+v and %+v", out, in)
+	}
+	if out.Name != "Checking → Savings" || out.IsSpending() || in.IsSpending() {
+		t.Errorf("legs must be named after the accounts and not count as spending: %+v", out)
+	}
+
+	if _, err := srv.Transfer(ctx, models.CreateTransfer{UserID: "alice", FromAccountID: checking, ToAccountID: euros, Amount: 1}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("cross-currency transfer: got %v, want FailedPrecondition", err)
+	}
+	if _, err := srv.Transfer(ctx, models.CreateTransfer{UserID: "alice", FromAccountID: checking, ToAccountID: bobs, Amount: 1}); status.Code(err) != codes.NotFound {
+		t.Errorf("transfer to another user's account: got %v, want NotFound", err)
+	}
+	srv.Quota = TransactionQuota{PerDay: 3}
+	if _, err := srv.Transfer(ctx, models.CreateTransfer{UserID: "alice", FromAccountID: savings, ToAccountID: checking, Amount: 1}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("transfer over quota: got %v, want ResourceExhausted", err)
+	}
+}
+
+func TestGetAccountBalance(t *testing.T) {
+	ctx := context.Background()
+	srv, ledger := newAccountFixture(t)
+	checking := createAccount(t, srv, "alice", "Checking", "USD", 100)
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 12, 0, 0, 0, time.UTC) }
+	ledger.txs = []models.Transaction{
+		{UserID: "alice", AccountID: checking, Cost: 10, Date: day(1)},
+		{UserID: "alice", AccountID: checking, Cost: 5, Kind: models.KindExpense, Date: day(2)},
+		{UserID: "alice", AccountID: checking, Cost: 20, Kind: models.KindTransferOut, Date: day(3)},
+		{UserID: "alice", AccountID: checking, Cost: 50, Kind: models.KindTransferIn, Date: day(4)},
+		{UserID: "alice", AccountID: checking, Cost: 7, Kind: models.KindSettlementIn, Date: day(5)},
+		{UserID: "alice", AccountID: checking, Cost: 1000, Kind: "unknown", Date: day(5)},
+	}
+	tests := []struct {
+		asOf string
+		want float64
+	}{
+		{asOf: "2024-03-01", want: 90},
+		{asOf: "2024-03-03", want: 65},
+		{asOf: "2024-03-05", want: 122},
+	}
+	for _, tt := range tests {
+		balance, err := srv.GetAccountBalance(ctx, checking, "alice", tt.asOf)
+		if err != nil {
+			t.Fatalf("GetAccountBalance(%s): %v", tt.asOf, err)
+		}
+		if balance.Balance != tt.want {
+			t.Errorf("balance as of %s: got %v, want %v", tt.asOf, balance.Balance, tt.want)
+		}
+	}
+}
+
+func TestDeleteAccountWithTransactions(t *testing.T) {
+	ctx := context.Background()
+	srv, ledger := newAccountFixture(t)
+	checking := createAccount(t, srv, "alice", "Checking", "USD", 0)
+	ledger.txs = []models.Transaction{{UserID: "alice", AccountID: checking, Cost: 1}}
+	if err := srv.DeleteAccount(ctx, checking, "alice"); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("got %v, want FailedPrecondition", err)
+	}
+	ledger.txs = nil
+	if err := srv.DeleteAccount(ctx, checking, "alice"); err != nil {
+		t.Errorf("deleting an empty account: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/anomaly"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type AnomalyConfig struct {
+	Threshold      float64
+	MinSamples     int
+	History        int
+	NewPayeeFactor float64
+}
+
+const defaultAnomalyLimit = 50
+
+// scoreAnomaly compares a new transaction with the user's recent spending
+// in its category and overall. Scoring is advisory, so failures are logged
+// and leave the transaction unscored.
+func (s *TransactionService) scoreAnomaly(ctx context.Context, tx *models.Transaction) {
+	if s.Anomalies.History == 0 {
+		return
+	}
+	categoryCosts, err := s.TransactionRepo.GetRecentCosts(ctx, tx.UserID, tx.Category, s.Anomalies.History)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	overallCosts, err := s.TransactionRepo.GetRecentCosts(ctx, tx.UserID, "", s.Anomalies.History)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	newPayee := false
+	if tx.PayeeID != "" {
+		n, err := s.TransactionRepo.CountPayeeTransactions(ctx, tx.UserID, tx.PayeeID)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		newPayee = n == 0
+	}
+	detector := anomaly.Detector{
+		Threshold:      s.Anomalies.Threshold,
+		MinSamples:     s.Anomalies.MinSamples,
+		NewPayeeFactor: s.Anomalies.NewPayeeFactor,
+	}
+	tx.Anomaly = detector.Evaluate(tx.Cost, anomaly.Compute(categoryCosts), anomaly.Compute(overallCosts), newPayee)
+}
+
+// ListAnomalies returns transactions scored at or above minScore, highest
+// first. A zero minScore means the configured threshold.
+func (s *TransactionService) ListAnomalies(ctx context.Context, req models.ListAnomalies, timeframe models.CreateTimeFrame) (_ []models.Transaction, err error) {
+	ctx, span := tracer.Start(ctx, "TransactionService.ListAnomalies", trace.WithAttributes(attribute.String("user.id", req.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(req, timeframe); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, req.UserID); err != nil {
+		return nil, err
+	}
+	tf, err := parseTimeFrame(timeframe)
+	if err != nil {
+		return nil, err
+	}
+	if req.MinScore == 0 {
+		req.MinScore = s.Anomalies.Threshold
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultAnomalyLimit
+	}
+	txs, err := s.TransactionRepo.GetAnomalies(ctx, req.UserID, req.MinScore, tf, req.Limit)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return txs, nil
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type AttachmentRepository interface {
+	AddAttachment(ctx context.Context, attachment models.Attachment, src io.Reader) (string, error)
+	GetAttachment(ctx context.Context, attachmentID, userID string) (*models.Attachment, error)
+	GetAttachments(ctx context.Context, userID string, txIDs ...string) ([]models.Attachment, error)
+	OpenAttachment(ctx context.Context, attachmentID string) (io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, attachmentID string) error
+	GetAttachmentsSize(ctx context.Context, userID string) (int64, error)
+	GetOrphanedAttachments(ctx context.Context, uploadedBefore time.Time, limit int) ([]models.Attachment, error)
+}
+
+type AttachmentTransactionRepository interface {
+	GetTransaction(ctx context.Context, transactionID, userID string) (*models.Transaction, error)
+}
+
+type AttachmentLimits struct {
+	MaxSize      int64
+	ContentTypes []string
+	Quota        int64
+}
+
+type AttachmentService struct {
+	AttachmentRepo  AttachmentRepository
+	TransactionRepo AttachmentTransactionRepository
+	User            UserService
+	Access          AccessPolicy
+	Limits          AttachmentLimits
+	validate        *Validator
+}
+
+func NewAttachmentService(attachmentRepo AttachmentRepository, txRepo AttachmentTransactionRepository, user UserService, access AccessPolicy, limits AttachmentLimits) *AttachmentService {
+	return &AttachmentService{AttachmentRepo: attachmentRepo, TransactionRepo: txRepo,
+		User: user, Access: access, Limits: limits, validate: NewValidator()}
+}
+
+var (
+	errAttachmentNotFound = status.Error(codes.NotFound, "attachment is not found")
+	errAttachmentQuota    = status.Error(codes.ResourceExhausted, "attachment storage quota exceeded")
+)
+
+func (s *AttachmentService) UploadAttachment(ctx context.Context, upload models.UploadAttachment, src io.Reader) (_ *models.Attachment, err error) {
+	ctx, span := tracer.Start(ctx, "AttachmentService.UploadAttachment", trace.WithAttributes(attribute.String("user.id", upload.UserID)))
+	defer func() { endSpan(span, err) }()
+	upload.ContentType = strings.ToLower(upload.ContentType)
+	upload.SHA256 = strings.ToLower(upload.SHA256)
+	if err := s.validate.Struct(upload); err != nil {
+		return nil, err
+	}
+	if !slices.Contains(s.Limits.ContentTypes, upload.ContentType) {
+		return nil, &ValidationError{Violations: []FieldViolation{{
+			Field:       "contentType",
+			Description: "must be one of " + strings.Join(s.Limits.ContentTypes, ", "),
+		}}}
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, upload.UserID); err != nil {
+		return nil, err
+	}
+	tx, err := s.TransactionRepo.GetTransaction(ctx, upload.TxID, upload.UserID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if tx == nil {
+		return nil, status.Error(codes.NotFound, "transaction is not found")
+	}
+	var remaining int64
+	if s.Limits.Quota > 0 {
+		used, err := s.AttachmentRepo.GetAttachmentsSize(ctx, upload.UserID)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		if remaining = s.Limits.Quota - used; remaining <= 0 {
+			return nil, errAttachmentQuota
+		}
+	}
+	attachment := models.Attachment{
+		UserID:      upload.UserID,
+		TxID:        upload.TxID,
+		FileName:    upload.FileName,
+		ContentType: upload.ContentType,
+		SHA256:      upload.SHA256,
+	}
+	src = &checksumReader{
+		r:        src,
+		hash:     sha256.New(),
+		max:      s.Limits.MaxSize,
+		quota:    remaining,
+		expected: upload.SHA256,
+		mismatch: status.Error(codes.InvalidArgument, "sha256 does not match the uploaded content"),
+	}
+	attachment.ID, err = s.AttachmentRepo.AddAttachment(ctx, attachment, src)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return s.AttachmentRepo.GetAttachment(ctx, attachment.ID, upload.UserID)
+}
+
+// OpenAttachment returns the attachment and a reader over its content. The
+// stored bytes are checked against their checksum before the reader is
+// returned, so a corrupted attachment fails with DataLoss before any of it
+// reaches the caller.
+func (s *AttachmentService) OpenAttachment(ctx context.Context, attachmentID, userID string) (_ *models.Attachment, _ io.ReadCloser, err error) {
+	ctx, span := tracer.Start(ctx, "AttachmentService.OpenAttachment", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	attachment, err := s.getAttachment(ctx, attachmentID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.verify(ctx, *attachment); err != nil {
+		return nil, nil, err
+	}
+	content, err := s.AttachmentRepo.OpenAttachment(ctx, attachment.ID)
+	if err != nil {
+		log.Println(err)
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+// verify reads the attachment's content once and compares it with its
+// checksum.
+func (s *AttachmentService) verify(ctx context.Context, attachment models.Attachment) error {
+	content, err := s.AttachmentRepo.OpenAttachment(ctx, attachment.ID)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer content.Close()
+	_, err = io.Copy(io.Discard, &checksumReader{
+		r:        content,
+		hash:     sha256.New(),
+		expected: attachment.SHA256,
+		mismatch: status.Error(codes.DataLoss, "stored attachment is corrupted"),
+	})
+	if err != nil {
+		log.Printf("attachment %s: %v", attachment.ID, err)
+		return err
+	}
+	return nil
+}
+
+func (s *AttachmentService) GetAttachments(ctx context.Context, txID, userID string) (_ []models.Attachment, err error) {
+	ctx, span := tracer.Start(ctx, "AttachmentService.GetAttachments", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.TransactionKey{ID: txID, UserID: userID}); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, userID); err != nil {
+		return nil, err
+	}
+	attachments, err := s.AttachmentRepo.GetAttachments(ctx, userID, txID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, attachmentID, userID string) (err error) {
+	ctx, span := tracer.Start(ctx, "AttachmentService.DeleteAttachment", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	attachment, err := s.getAttachment(ctx, attachmentID, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.AttachmentRepo.DeleteAttachment(ctx, attachment.ID); err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+// SweepOrphanedAttachments deletes up to limit attachments uploaded before
+// uploadedBefore whose transaction is gone, which happens when deleting
+// them alongside the transaction failed. It returns how many it deleted.
+func (s *AttachmentService) SweepOrphanedAttachments(ctx context.Context, uploadedBefore time.Time, limit int) (_ int, err error) {
+	ctx, span := tracer.Start(ctx, "AttachmentService.SweepOrphanedAttachments")
+	defer func() { endSpan(span, err) }()
+	orphans, err := s.AttachmentRepo.GetOrphanedAttachments(ctx, uploadedBefore, limit)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	deleted := 0
+	for _, a := range orphans {
+		if err := s.AttachmentRepo.DeleteAttachment(ctx, a.ID); err != nil {
+			log.Println(err)
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+func (s *AttachmentService) getAttachment(ctx context.Context, attachmentID, userID string) (*models.Attachment, error) {
+	if err := s.validate.Struct(models.AttachmentKey{ID: attachmentID, UserID: userID}); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, userID); err != nil {
+		return nil, err
+	}
+	attachment, err := s.AttachmentRepo.GetAttachment(ctx, attachmentID, userID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if attachment == nil {
+		return nil, errAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+// checksumReader hashes everything read through it. It fails once more than
+// max bytes, or more than the remaining quota, have been read (each when
+// > 0), and at EOF if the content is empty or its digest differs from
+// expected.
+type checksumReader struct {
+	r        io.Reader
+	hash     hash.Hash
+	n        int64
+	max      int64
+	quota    int64
+	expected string
+	mismatch error
+}
+
+func (c *checksumReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.hash.Write(p[:n])
+	c.n += int64(n)
+	if c.max > 0 && c.n > c.max {
+		return 0, status.Errorf(codes.InvalidArgument, "attachment exceeds %d bytes", c.max)
+	}
+	if c.quota > 0 && c.n > c.quota {
+		return 0, errAttachmentQuota
+	}
+	if err == io.EOF {
+		if c.n == 0 {
+			return n, status.Error(codes.InvalidArgument, "attachment is empty")
+		}
+		if hex.EncodeToString(c.hash.Sum(nil)) != c.expected {
+			return n, c.mismatch
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type storedAttachment struct {
+	models.Attachment
+	content []byte
+}
+
+type memAttachmentRepo struct {
+	files []storedAttachment
+	opens int
+}
+
+func (r *memAttachmentRepo) AddAttachment(_ context.Context, a models.Attachment, src io.Reader) (string, error) {
+	content, err := io.ReadAll(src)
+	if err != nil {
+		return "", err
+	}
+	a.ID = fmt.Sprintf("6650a1f1c2a4b5e6f7a8c%03x", len(r.files)+1)
+	a.Size = int64(len(content))
+	a.UploadedAt = time.Now().UTC()
+	r.files = append(r.files, storedAttachment{Attachment: a, content: content})
+	return a.ID, nil
+}
+
+func (r *memAttachmentRepo) GetAttachment(_ context.Context, id, userID string) (*models.Attachment, error) {
+	for _, f := range r.files {
+		if f.ID == id && f.UserID == userID {
+			return &f.Attachment, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memAttachmentRepo) GetAttachments(_ context.Context, userID string, txIDs ...string) ([]models.Attachment, error) {
+	var found []models.Attachment
+	for _, f := range r.files {
+		if f.UserID == userID && (len(txIDs) == 0 || slices.Contains(txIDs, f.TxID)) {
+			found = append(found, f.Attachment)
+		}
+	}
+	return found, nil
+}
+
+func (r *memAttachmentRepo) OpenAttachment(_ context.Context, id string) (io.ReadCloser, error) {
+	for _, f := range r.files {
+		if f.ID == id {
+			r.opens++
+			return io.NopCloser(bytes.NewReader(f.content)), nil
+		}
+	}
+	return nil, fmt.Errorf("file %s not found", id)
+}
+
+func (r *memAttachmentRepo) DeleteAttachment(_ context.Context, id string) error {
+	r.files = slices.DeleteFunc(r.files, func(f storedAttachment) bool { return f.ID == id })
+	return nil
+}
+
+func (r *memAttachmentRepo) GetAttachmentsSize(_ context.Context, userID string) (int64, error) {
+	var size int64
+	for _, f := range r.files {
+		if f.UserID == userID {
+			size += f.Size
+		}
+	}
+	return size, nil
+}
+
+// txIDs is set by the test to the transactions that still exist.
+type orphanAwareRepo struct {
+	*memAttachmentRepo
+	txIDs []string
+}
+
+func (r orphanAwareRepo) GetOrphanedAttachments(_ context.Context, uploadedBefore time.Time, limit int) ([]models.Attachment, error) {
+	var orphans []models.Attachment
+	for _, f := range r.files {
+		if len(orphans) < limit && f.UploadedAt.Before(uploadedBefore) && !slices.Contains(r.txIDs, f.TxID) {
+			orphans = append(orphans, f.Attachment)
+		}
+	}
+	return orphans, nil
+}
+
+func digest(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func newAttachmentFixture(t *testing.T, limits AttachmentLimits) (*AttachmentService, orphanAwareRepo, string) {
+	t.Helper()
+	txs := &memTxRepo{}
+	txID, _ := txs.AddTransaction(context.Background(), models.Transaction{UserID: "alice", Name: "Groceries", Cost: 10})
+	repo := orphanAwareRepo{memAttachmentRepo: &memAttachmentRepo{}, txIDs: []string{txID}}
+	if limits.ContentTypes == nil {
+		limits.ContentTypes = []string{"image/png"}
+	}
+	return NewAttachmentService(repo, txs, knownUsers{"alice"}, allowAll{}, limits), repo, txID
+}
+
+func TestUploadAttachment(t *testing.T) {
+	ctx := context.Background()
+	srv, _, txID := newAttachmentFixture(t, AttachmentLimits{MaxSize: 8, Quota: 14})
+	upload := func(content, sum string) (*models.Attachment, error) {
+		return srv.UploadAttachment(ctx, models.UploadAttachment{UserID: "alice", TxID: txID, FileName: "receipt.png", ContentType: "IMAGE/PNG", SHA256: sum}, strings.NewReader(content))
+	}
+
+	a, err := upload("receipt", strings.ToUpper(digest("receipt")))
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if a.Size != 7 || a.ContentType != "image/png" || a.SHA256 != digest("receipt") {
+		t.Errorf("stored %+v", a)
+	}
+	tests := []struct {
+		name    string
+		content string
+		sum     string
+		want    codes.Code
+	}{
+		{name: "checksum mismatch", content: "receipt", sum: digest("other"), want: codes.InvalidArgument},
+		{name: "too large", content: "a long receipt", sum: digest("a long receipt"), want: codes.InvalidArgument},
+		{name: "empty", content: "", sum: digest(""), want: codes.InvalidArgument},
+		{name: "over quota", content: "receipt2", sum: digest("receipt2"), want: codes.ResourceExhausted},
+	}
+	for _, tt := range tests {
+		if _, err := upload(tt.content, tt.sum); status.Code(err) != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestOpenCorruptedAttachment(t *testing.T) {
+	ctx := context.Background()
+	srv, repo, txID := newAttachmentFixture(t, AttachmentLimits{})
+	a, err := srv.UploadAttachment(ctx, models.UploadAttachment{UserID: "alice", TxID: txID, FileName: "r.png", ContentType: "image/png", SHA256: digest("receipt")}, strings.NewReader("receipt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, content, err := srv.OpenAttachment(ctx, a.ID, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(content)
+	if err != nil || string(got) != "receipt" {
+		t.Errorf("read %q, %v", got, err)
+	}
+
+	repo.files[0].content = []byte("rec3ipt")
+	repo.opens = 0
+	if _, _, err := srv.OpenAttachment(ctx, a.ID, "alice"); status.Code(err) != codes.DataLoss {
+		t.Errorf("corrupted attachment: got %v, want DataLoss", err)
+	}
+	if repo.opens != 1 {
+		t.Errorf("opened the content %d times, want it checked once and never handed out", repo.opens)
+	}
+}
+
+func TestSweepOrphanedAttachments(t *testing.T) {
+	ctx := context.Background()
+	srv, repo, txID := newAttachmentFixture(t, AttachmentLimits{})
+	for _, tx := range []string{txID, "6650a1f1c2a4b5e6f7a8b9c0", "6650a1f1c2a4b5e6f7a8b9c1"} {
+		repo.files = append(repo.files, storedAttachment{Attachment: models.Attachment{ID: "file-" + tx, UserID: "alice", TxID: tx}})
+	}
+	deleted, err := srv.SweepOrphanedAttachments(ctx, time.Now(), 1)
+	if err != nil || deleted != 1 {
+		t.Fatalf("first batch: deleted %d, %v", deleted, err)
+	}
+	deleted, err = srv.SweepOrphanedAttachments(ctx, time.Now(), 10)
+	if err != nil || deleted != 1 {
+		t.Fatalf("second batch: deleted %d, %v", deleted, err)
+	}
+	if len(repo.files) != 1 || repo.files[0].TxID != txID {
+		t.Errorf("left %+v, want only the attachment of the existing transaction", repo.files)
+	}
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/dedupe"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/rules"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultDuplicateWindow     = 10 * time.Minute
+	defaultDuplicateSimilarity = 0.8
+	maxNoteLength              = 500
+)
+
+func (s *TransactionService) FindDuplicates(ctx context.Context, req models.FindDuplicates, timeframe models.CreateTimeFrame) (_ []models.DuplicateCluster, err error) {
+	ctx, span := tracer.Start(ctx, "TransactionService.FindDuplicates", trace.WithAttributes(attribute.String("user.id", req.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(req, timeframe); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, req.UserID); err != nil {
+		return nil, err
+	}
+	tf, err := parseTimeFrame(timeframe)
+	if err != nil {
+		return nil, err
+	}
+	txs, err := s.TransactionRepo.GetTXByTimeFrame(ctx, req.UserID, tf)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	tol := dedupe.Tolerance{
+		Amount:        req.AmountTolerance,
+		Window:        time.Duration(req.WindowMinutes) * time.Minute,
+		MinSimilarity: req.NameSimilarity,
+	}
+	if tol.Window == 0 {
+		tol.Window = defaultDuplicateWindow
+	}
+	if tol.MinSimilarity == 0 {
+		tol.MinSimilarity = defaultDuplicateSimilarity
+	}
+	return dedupe.Find(txs, tol), nil
+}
+
+// MergeTransactions keeps one transaction and soft-deletes the others,
+// optionally carrying their tags and notes over to the kept one.
+func (s *TransactionService) MergeTransactions(ctx context.Context, req models.MergeTransactions) (_ *models.Transaction, err error) {
+	ctx, span := tracer.Start(ctx, "TransactionService.MergeTransactions", trace.WithAttributes(attribute.String("user.id", req.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(req); err != nil {
+		return nil, err
+	}
+	if slices.Contains(req.MergeIDs, req.KeepID) {
+		return nil, &ValidationError{Violations: []FieldViolation{{Field: "mergeIds", Description: "must not contain keepId"}}}
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, req.UserID); err != nil {
+		return nil, err
+	}
+	keep, err := s.mergeCandidate(ctx, req.KeepID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	original := *keep
+	removed := make([]models.Transaction, len(req.MergeIDs))
+	var notes []string
+	if keep.Note != "" {
+		notes = append(notes, keep.Note)
+	}
+	for i, id := range req.MergeIDs {
+		tx, err := s.mergeCandidate(ctx, id, req.UserID)
+		if err != nil {
+			return nil, err
+		}
+		removed[i] = *tx
+		if req.CombineTags {
+			keep.Tags = rules.MergeTags(slices.Clone(keep.Tags), tx.Tags)
+		}
+		if req.CombineNotes && tx.Note != "" && !slices.Contains(notes, tx.Note) {
+			notes = append(notes, tx.Note)
+		}
+	}
+	if req.CombineNotes {
+		keep.Note = strings.Join(notes, "\n")
+		if len([]rune(keep.Note)) > maxNoteLength {
+			return nil, status.Errorf(codes.FailedPrecondition, "combined notes exceed %d characters", maxNoteLength)
+		}
+	}
+	entry := models.AuditEntry{
+		UserID:        req.UserID,
+		Action:        models.AuditMerge,
+		TransactionID: keep.ID,
+		RelatedIDs:    req.MergeIDs,
+		Before:        append([]models.Transaction{original}, removed...),
+	}
+	if err := s.TransactionRepo.MergeTransactions(ctx, *keep, removed, entry); err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	for i := range removed {
+		s.learn(ctx, &removed[i], nil)
+	}
+	return keep, nil
+}
+
+func (s *TransactionService) mergeCandidate(ctx context.Context, txID, userID string) (*models.Transaction, error) {
+	tx, err := s.TransactionRepo.GetTransaction(ctx, txID, userID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if tx == nil {
+		return nil, status.Errorf(codes.NotFound, "transaction %s is not found", txID)
+	}
+	if tx.IsTransfer() || tx.IsSettlement() {
+		return nil, status.Errorf(codes.FailedPrecondition, "transaction %s is a transfer entry and cannot be merged", txID)
+	}
+	return tx, nil
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type allowAll struct{}
+
+func (allowAll) CheckAccess(context.Context, string) error { return nil }
+
+type knownUsers []string
+
+func (u knownUsers) GetUser(_ context.Context, id string) (string, string, error) {
+	if slices.Contains(u, id) {
+		return id, "", nil
+	}
+	return "", "", status.Error(codes.NotFound, "user not found")
+}
+
+// memTxRepo keeps transactions in memory. It implements the parts of the
+// transaction repository the tests use; calling any other method panics on
+// the nil embedded interface.
+type memTxRepo struct {
+	TransactionRepository
+	txs []models.Transaction
+}
+
+func (r *memTxRepo) AddTransaction(_ context.Context, tx models.Transaction) (string, error) {
+	tx.ID = fmt.Sprintf("6650a1f1c2a4b5e6f7a8%04x", len(r.txs)+1)
+	r.txs = append(r.txs, tx)
+	return tx.ID, nil
+}
+
+func (r *memTxRepo) AddTransfer(_ context.Context, outgoing, incoming models.Transaction) (*models.Transfer, error) {
+	id := fmt.Sprintf("transfer-%d", len(r.txs))
+	outgoing.TransferID, incoming.TransferID = id, id
+	out, _ := r.AddTransaction(context.Background(), outgoing)
+	in, _ := r.AddTransaction(context.Background(), incoming)
+	return &models.Transfer{ID: id, OutgoingTxID: out, IncomingTxID: in}, nil
+}
+
+func (r *memTxRepo) GetTransaction(_ context.Context, txID, userID string) (*models.Transaction, error) {
+	for _, tx := range r.txs {
+		if tx.ID == txID && tx.UserID == userID {
+			return &tx, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memTxRepo) GetTransactionByID(_ context.Context, txID string) (*models.Transaction, error) {
+	for _, tx := range r.txs {
+		if tx.ID == txID {
+			return &tx, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memTxRepo) GetAllTransactions(_ context.Context, userID string) ([]models.Transaction, error) {
+	var found []models.Transaction
+	for _, tx := range r.txs {
+		if tx.UserID == userID {
+			found = append(found, tx)
+		}
+	}
+	return found, nil
+}
+
+func (r *memTxRepo) GetTXByTimeFrame(_ context.Context, userID string, frame models.TimeFrame) ([]models.Transaction, error) {
+	var found []models.Transaction
+	for _, tx := range r.txs {
+		if tx.UserID == userID && !tx.Date.Before(frame.StartDate) && !tx.Date.After(frame.EndDate) {
+			found = append(found, tx)
+		}
+	}
+	return found, nil
+}
+
+func (r *memTxRepo) GetLedgerTransactions(_ context.Context, ledgerID string, frame models.TimeFrame) ([]models.Transaction, error) {
+	var found []models.Transaction
+	for _, tx := range r.txs {
+		if tx.LedgerID == ledgerID && !tx.Date.Before(frame.StartDate) && !tx.Date.After(frame.EndDate) {
+			found = append(found, tx)
+		}
+	}
+	return found, nil
+}
+
+func (r *memTxRepo) UpdateTx(_ context.Context, updates models.Transaction) error {
+	for i, tx := range r.txs {
+		if tx.ID == updates.ID && tx.UserID == updates.UserID {
+			r.txs[i] = updates
+			return nil
+		}
+	}
+	return fmt.Errorf("transaction %s is not found", updates.ID)
+}
+
+func (r *memTxRepo) DeleteTx(_ context.Context, userID, txID string) error {
+	r.txs = slices.DeleteFunc(r.txs, func(tx models.Transaction) bool { return tx.ID == txID && tx.UserID == userID })
+	return nil
+}
+
+// GetCashFlow mirrors the repository's pipeline: entries in the frame in
+// date, then id order, each with its signed amount and running balance.
+func (r *memTxRepo) GetCashFlow(_ context.Context, userID, accountID string, frame models.TimeFrame) ([]models.CashFlowEntry, error) {
+	var entries []models.CashFlowEntry
+	for _, tx := range r.txs {
+		if tx.UserID != userID || !tx.Date.After(frame.StartDate) || !tx.Date.Before(frame.EndDate) {
+			continue
+		}
+		if accountID != "" && tx.AccountID != accountID {
+			continue
+		}
+		entries = append(entries, models.CashFlowEntry{Transaction: tx, Amount: tx.AccountEffect()})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].Date.Equal(entries[j].Date) {
+			return entries[i].Date.Before(entries[j].Date)
+		}
+		return entries[i].ID < entries[j].ID
+	})
+	var balance float64
+	for i := range entries {
+		balance += entries[i].Amount
+		entries[i].Balance = balance
+	}
+	return entries, nil
+}
+
+func (r *memTxRepo) GetAccountFlow(_ context.Context, userID, accountID string, until time.Time) (float64, error) {
+	var flow float64
+	for _, tx := range r.txs {
+		if tx.UserID == userID && tx.AccountID == accountID && !tx.Date.After(until) {
+			flow += tx.AccountEffect()
+		}
+	}
+	return flow, nil
+}
+
+func (r *memTxRepo) GetTagContributions(_ context.Context, userID, tag string, until time.Time) (float64, error) {
+	var saved float64
+	for _, tx := range r.txs {
+		if tx.UserID == userID && tx.Kind == models.KindContribution && slices.Contains(tx.Tags, tag) && !tx.Date.After(until) {
+			saved += tx.Cost
+		}
+	}
+	return saved, nil
+}
+
+func (r *memTxRepo) CountAccountTransactions(_ context.Context, userID, accountID string) (int64, error) {
+	var n int64
+	for _, tx := range r.txs {
+		if tx.UserID == userID && tx.AccountID == accountID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *memTxRepo) CountTransactionsSince(_ context.Context, userID string, since time.Time) (int64, error) {
+	var n int64
+	for _, tx := range r.txs {
+		if tx.UserID == userID && !tx.Date.Before(since) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// memCategoryModels keeps one user's category model.
+type memCategoryModels struct {
+	CategoryModelRepository
+	model   *models.CategoryModel
+	deleted bool
+	// beforeInsert runs inside InsertCategoryModel, before the existence check.
+	beforeInsert func()
+}
+
+func (m *memCategoryModels) GetCategoryModel(context.Context, string) (*models.CategoryModel, error) {
+	return m.model, nil
+}
+
+func (m *memCategoryModels) InsertCategoryModel(_ context.Context, model models.CategoryModel) (bool, error) {
+	if hook := m.beforeInsert; hook != nil {
+		m.beforeInsert = nil
+		hook()
+	}
+	if m.model != nil {
+		return false, nil
+	}
+	m.model = &model
+	return true, nil
+}
+
+func (m *memCategoryModels) DeleteCategoryModel(context.Context, string) error {
+	m.model, m.deleted = nil, true
+	return nil
+}
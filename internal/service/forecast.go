@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/forecast"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type BudgetProvider interface {
+	GetBudgets(ctx context.Context, userID string) ([]models.Budget, error)
+}
+
+const (
+	defaultLookbackWeeks = 12
+	maxForecastDays      = 366
+)
+
+// ForecastSpending projects per-category totals for the period, which
+// defaults to the calendar month of asOf, and compares them with the user's
+// budgets when there are any.
+func (s *TransactionService) ForecastSpending(ctx context.Context, req models.ForecastSpending, timeframe models.CreateTimeFrame) (_ *models.SpendingForecast, err error) {
+	ctx, span := tracer.Start(ctx, "TransactionService.ForecastSpending", trace.WithAttributes(attribute.String("user.id", req.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(req, timeframe); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, req.UserID); err != nil {
+		return nil, err
+	}
+	asOf := time.Now().UTC()
+	if req.AsOf != "" {
+		date, err := time.Parse(Dateformat, req.AsOf)
+		if err != nil {
+			return nil, err
+		}
+		asOf = date.AddDate(0, 0, 1)
+	}
+	period, err := forecastPeriod(timeframe, asOf)
+	if err != nil {
+		return nil, err
+	}
+	if req.LookbackWeeks == 0 {
+		req.LookbackWeeks = defaultLookbackWeeks
+	}
+	lookback := time.Duration(req.LookbackWeeks) * 7 * 24 * time.Hour
+	from := asOf.Add(-max(lookback, forecast.RecurringLookback))
+	if period.StartDate.Before(from) {
+		from = period.StartDate
+	}
+	history, err := s.TransactionRepo.GetTXByTimeFrame(ctx, req.UserID, models.TimeFrame{StartDate: from, EndDate: period.EndDate})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	result := forecast.Project(history, period, asOf, lookback)
+	budgets, err := s.Budgets.GetBudgets(ctx, req.UserID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	applyBudgets(&result, budgets)
+	return &result, nil
+}
+
+func forecastPeriod(timeframe models.CreateTimeFrame, asOf time.Time) (models.TimeFrame, error) {
+	if timeframe.StartDate == "" {
+		timeframe.StartDate = time.Date(asOf.Year(), asOf.Month(), 1, 0, 0, 0, 0, time.UTC).Format(Dateformat)
+	}
+	if timeframe.EndDate == "" {
+		start, err := time.Parse(Dateformat, timeframe.StartDate)
+		if err != nil {
+			return models.TimeFrame{}, err
+		}
+		timeframe.EndDate = time.Date(start.Year(), start.Month()+1, 0, 0, 0, 0, 0, time.UTC).Format(Dateformat)
+	}
+	period, err := parseTimeFrame(timeframe)
+	if err != nil {
+		return period, err
+	}
+	if period.EndDate.Before(period.StartDate) {
+		return period, &ValidationError{Violations: []FieldViolation{{Field: "endDate", Description: "must not be before startDate"}}}
+	}
+	if period.EndDate.Sub(period.StartDate) > maxForecastDays*24*time.Hour {
+		return period, status.Errorf(codes.InvalidArgument, "forecast period must not exceed %d days", maxForecastDays)
+	}
+	return period, nil
+}
+
+func applyBudgets(result *models.SpendingForecast, budgets []models.Budget) {
+	if len(budgets) == 0 {
+		return
+	}
+	limits := make(map[string]float64, len(budgets))
+	var total float64
+	for _, b := range budgets {
+		limits[b.Category] += b.Limit
+		total += b.Limit
+	}
+	for i := range result.Categories {
+		if limit, ok := limits[result.Categories[i].Category]; ok {
+			result.Categories[i].Budget = &limit
+			delete(limits, result.Categories[i].Category)
+		}
+	}
+	for category, limit := range limits {
+		result.Categories = append(result.Categories, models.CategoryForecast{Category: category, Budget: &limit})
+	}
+	result.Total.Budget = &total
+}
@@ -0,0 +1,379 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type GoalRepository interface {
+	AddGoal(ctx context.Context, goal models.Goal) (string, error)
+	GetGoal(ctx context.Context, goalID, userID string) (*models.Goal, error)
+	GetGoals(ctx context.Context, userID string) ([]models.Goal, error)
+	UpdateGoal(ctx context.Context, goal models.Goal) error
+	DeleteGoal(ctx context.Context, goalID, userID string) error
+}
+
+type GoalTransactionRepository interface {
+	AddTransaction(ctx context.Context, transaction models.Transaction) (string, error)
+	AddTransfer(ctx context.Context, outgoing, incoming models.Transaction) (*models.Transfer, error)
+	GetAccountFlow(ctx context.Context, userID, accountID string, until time.Time) (float64, error)
+	GetTagContributions(ctx context.Context, userID, tag string, until time.Time) (float64, error)
+	CountTransactionsSince(ctx context.Context, userID string, since time.Time) (int64, error)
+}
+
+type GoalService struct {
+	GoalRepo        GoalRepository
+	TransactionRepo GoalTransactionRepository
+	Accounts        AccountLookup
+	User            UserService
+	Access          AccessPolicy
+	Quota           TransactionQuota
+	validate        *Validator
+}
+
+func NewGoalService(goalRepo GoalRepository, txRepo GoalTransactionRepository, accounts AccountLookup, user UserService, access AccessPolicy, quota TransactionQuota) *GoalService {
+	return &GoalService{GoalRepo: goalRepo, TransactionRepo: txRepo, Accounts: accounts,
+		User: user, Access: access, Quota: quota, validate: NewValidator()}
+}
+
+const (
+	maxGoalsPerUser = 50
+	daysPerMonth    = 365.25 / 12
+)
+
+var errGoalNotFound = status.Error(codes.NotFound, "goal is not found")
+
+func (s *GoalService) CreateGoal(ctx context.Context, create models.CreateGoal) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "GoalService.CreateGoal", trace.WithAttributes(attribute.String("user.id", create.UserID)))
+	defer func() { endSpan(span, err) }()
+	deadline, err := s.validateGoal(create)
+	if err != nil {
+		return "", err
+	}
+	if err := checkDeadline(deadline); err != nil {
+		return "", err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, create.UserID); err != nil {
+		return "", err
+	}
+	if err := s.checkAccount(ctx, create.AccountID, create.UserID); err != nil {
+		return "", err
+	}
+	existing, err := s.GoalRepo.GetGoals(ctx, create.UserID)
+	if err != nil {
+		log.Println(err)
+		return "", err
+	}
+	if len(existing) >= maxGoalsPerUser {
+		return "", status.Errorf(codes.FailedPrecondition, "user already has %d goals", maxGoalsPerUser)
+	}
+	id, err := s.GoalRepo.AddGoal(ctx, models.Goal{
+		UserID:    create.UserID,
+		Name:      create.Name,
+		Target:    create.Target,
+		Deadline:  deadline,
+		AccountID: create.AccountID,
+		Tag:       create.Tag,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		log.Println(err)
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *GoalService) GetGoal(ctx context.Context, goalID, userID string) (_ *models.GoalProgress, err error) {
+	ctx, span := tracer.Start(ctx, "GoalService.GetGoal", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.GoalKey{ID: goalID, UserID: userID}); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, userID); err != nil {
+		return nil, err
+	}
+	goal, err := s.getGoal(ctx, goalID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.progress(ctx, *goal, time.Now().UTC())
+}
+
+func (s *GoalService) GetGoals(ctx context.Context, userID string) (_ []models.GoalProgress, err error) {
+	ctx, span := tracer.Start(ctx, "GoalService.GetGoals", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.UserKey{UserID: userID}); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, userID); err != nil {
+		return nil, err
+	}
+	goals, err := s.GoalRepo.GetGoals(ctx, userID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	now := time.Now().UTC()
+	progress := make([]models.GoalProgress, len(goals))
+	for i, goal := range goals {
+		p, err := s.progress(ctx, goal, now)
+		if err != nil {
+			return nil, err
+		}
+		progress[i] = *p
+	}
+	return progress, nil
+}
+
+// UpdateGoal replaces everything but the owner and creation time. The
+// deadline has to be in the future only when it changes, so a goal past its
+// deadline can still be renamed or retargeted.
+func (s *GoalService) UpdateGoal(ctx context.Context, update models.CreateGoal) (_ *models.GoalProgress, err error) {
+	ctx, span := tracer.Start(ctx, "GoalService.UpdateGoal", trace.WithAttributes(attribute.String("user.id", update.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.GoalKey{ID: update.ID, UserID: update.UserID}); err != nil {
+		return nil, err
+	}
+	deadline, err := s.validateGoal(update)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, update.UserID); err != nil {
+		return nil, err
+	}
+	goal, err := s.getGoal(ctx, update.ID, update.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !deadline.Equal(goal.Deadline) {
+		if err := checkDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.checkAccount(ctx, update.AccountID, update.UserID); err != nil {
+		return nil, err
+	}
+	goal.Name = update.Name
+	goal.Target = update.Target
+	goal.Deadline = deadline
+	goal.AccountID = update.AccountID
+	goal.Tag = update.Tag
+	if err := s.GoalRepo.UpdateGoal(ctx, *goal); err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return s.progress(ctx, *goal, time.Now().UTC())
+}
+
+// DeleteGoal removes the goal only; its contributions stay in the history.
+func (s *GoalService) DeleteGoal(ctx context.Context, goalID, userID string) (err error) {
+	ctx, span := tracer.Start(ctx, "GoalService.DeleteGoal", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.GoalKey{ID: goalID, UserID: userID}); err != nil {
+		return err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, userID); err != nil {
+		return err
+	}
+	if _, err := s.getGoal(ctx, goalID, userID); err != nil {
+		return err
+	}
+	if err := s.GoalRepo.DeleteGoal(ctx, goalID, userID); err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+// AddContribution records money put toward (or, when negative, taken from) a
+// goal. For an account goal it is a transfer between FromAccountID and the
+// goal's account, so no balance grows out of thin air; for a tag goal it is
+// a contribution carrying the tag and no account.
+func (s *GoalService) AddContribution(ctx context.Context, contribution models.GoalContribution) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "GoalService.AddContribution", trace.WithAttributes(attribute.String("user.id", contribution.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(contribution); err != nil {
+		return "", err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, contribution.UserID); err != nil {
+		return "", err
+	}
+	goal, err := s.getGoal(ctx, contribution.GoalID, contribution.UserID)
+	if err != nil {
+		return "", err
+	}
+	var goalAccount, source *models.Account
+	switch {
+	case goal.AccountID == "" && contribution.FromAccountID != "":
+		return "", &ValidationError{Violations: []FieldViolation{{Field: "fromAccountId", Description: "must be empty for a goal without an account"}}}
+	case goal.AccountID != "" && contribution.FromAccountID == "":
+		return "", &ValidationError{Violations: []FieldViolation{{Field: "fromAccountId", Description: "is required for a goal with an account"}}}
+	case goal.AccountID != "" && contribution.FromAccountID == goal.AccountID:
+		return "", &ValidationError{Violations: []FieldViolation{{Field: "fromAccountId", Description: "must differ from the goal's account"}}}
+	case goal.AccountID != "":
+		if goalAccount, err = s.lookupAccount(ctx, goal.AccountID, goal.UserID, "accountId"); err != nil {
+			return "", err
+		}
+		if source, err = s.lookupAccount(ctx, contribution.FromAccountID, goal.UserID, "fromAccountId"); err != nil {
+			return "", err
+		}
+		if source.Currency != goalAccount.Currency {
+			return "", status.Error(codes.FailedPrecondition, "contributions between accounts in different currencies are not supported")
+		}
+	}
+	legs := 1
+	if source != nil {
+		legs = 2
+	}
+	if err := checkTransactionQuota(ctx, s.TransactionRepo, s.Quota, goal.UserID, legs); err != nil {
+		return "", err
+	}
+	date := time.Now().UTC()
+	if contribution.Date != nil {
+		date, err = time.Parse(DateTimeformat, *contribution.Date)
+		if err != nil {
+			return "", err
+		}
+	}
+	tx := models.Transaction{
+		UserID:   goal.UserID,
+		Category: models.GoalCategory,
+		Name:     "Contribution: " + goal.Name,
+		Cost:     contribution.Amount,
+		Date:     date,
+		Kind:     models.KindContribution,
+		Note:     contribution.Note,
+		GoalID:   goal.ID,
+	}
+	if goal.Tag != "" {
+		tx.Tags = []string{goal.Tag}
+	}
+	if source == nil {
+		id, err := s.TransactionRepo.AddTransaction(ctx, tx)
+		if err != nil {
+			log.Println(err)
+			return "", err
+		}
+		return id, nil
+	}
+
+	from, to := source.ID, goalAccount.ID
+	if contribution.Amount < 0 {
+		tx.Name = "Withdrawal: " + goal.Name
+		tx.Cost = -contribution.Amount
+		from, to = to, from
+	}
+	outgoing, incoming := tx, tx
+	outgoing.AccountID, outgoing.Kind = from, models.KindTransferOut
+	incoming.AccountID, incoming.Kind = to, models.KindTransferIn
+	transfer, err := s.TransactionRepo.AddTransfer(ctx, outgoing, incoming)
+	if err != nil {
+		log.Println(err)
+		return "", err
+	}
+	if contribution.Amount < 0 {
+		return transfer.OutgoingTxID, nil
+	}
+	return transfer.IncomingTxID, nil
+}
+
+// progress measures a goal at now. Saved is the linked account's balance or
+// the sum of contributions with the goal's tag. A goal is on track when it
+// is at least where saving the same amount every day since its creation
+// would have brought it.
+func (s *GoalService) progress(ctx context.Context, goal models.Goal, now time.Time) (*models.GoalProgress, error) {
+	p := &models.GoalProgress{Goal: goal}
+	if goal.AccountID != "" {
+		account, err := s.Accounts.GetAccount(ctx, goal.AccountID, goal.UserID)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		if account != nil {
+			flow, err := s.TransactionRepo.GetAccountFlow(ctx, goal.UserID, goal.AccountID, now)
+			if err != nil {
+				log.Println(err)
+				return nil, err
+			}
+			p.Saved = account.OpeningBalance + flow
+		}
+	} else {
+		saved, err := s.TransactionRepo.GetTagContributions(ctx, goal.UserID, goal.Tag, now)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		p.Saved = saved
+	}
+	p.Remaining = math.Max(0, goal.Target-p.Saved)
+	p.Achieved = p.Remaining == 0
+
+	end := goal.Deadline.AddDate(0, 0, 1)
+	total := end.Sub(goal.CreatedAt)
+	elapsed := now.Sub(goal.CreatedAt)
+	if elapsed >= total || total <= 0 {
+		p.Expected = goal.Target
+		p.RequiredMonthly = p.Remaining
+	} else {
+		p.Expected = goal.Target * math.Max(0, elapsed.Hours()/total.Hours())
+		monthsLeft := end.Sub(now).Hours() / 24 / daysPerMonth
+		p.RequiredMonthly = p.Remaining / math.Max(1, monthsLeft)
+	}
+	p.OnTrack = p.Achieved || p.Saved+centTolerance >= p.Expected
+	return p, nil
+}
+
+func (s *GoalService) validateGoal(goal models.CreateGoal) (time.Time, error) {
+	if err := s.validate.Struct(goal); err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(Dateformat, goal.Deadline)
+}
+
+func checkDeadline(deadline time.Time) error {
+	if !deadline.After(time.Now().UTC()) {
+		return &ValidationError{Violations: []FieldViolation{{Field: "deadline", Description: "must be in the future"}}}
+	}
+	return nil
+}
+
+func (s *GoalService) getGoal(ctx context.Context, goalID, userID string) (*models.Goal, error) {
+	goal, err := s.GoalRepo.GetGoal(ctx, goalID, userID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if goal == nil {
+		return nil, errGoalNotFound
+	}
+	return goal, nil
+}
+
+func (s *GoalService) checkAccount(ctx context.Context, accountID, userID string) error {
+	if accountID == "" {
+		return nil
+	}
+	_, err := s.lookupAccount(ctx, accountID, userID, "accountId")
+	return err
+}
+
+// lookupAccount reports a missing account as a violation on field.
+func (s *GoalService) lookupAccount(ctx context.Context, accountID, userID, field string) (*models.Account, error) {
+	account, err := s.Accounts.GetAccount(ctx, accountID, userID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if account == nil {
+		return nil, &ValidationError{Violations: []FieldViolation{{Field: field, Description: "account not found"}}}
+	}
+	return account, nil
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	goalAccountID   = "6650a1f1c2a4b5e6f7a8b901"
+	sourceAccountID = "6650a1f1c2a4b5e6f7a8b902"
+	euroAccountID   = "6650a1f1c2a4b5e6f7a8b903"
+)
+
+type memGoalRepo struct {
+	GoalRepository
+	goals map[string]models.Goal
+}
+
+func (r *memGoalRepo) GetGoal(_ context.Context, goalID, userID string) (*models.Goal, error) {
+	goal, ok := r.goals[goalID]
+	if !ok || goal.UserID != userID {
+		return nil, nil
+	}
+	return &goal, nil
+}
+
+func (r *memGoalRepo) UpdateGoal(_ context.Context, goal models.Goal) error {
+	r.goals[goal.ID] = goal
+	return nil
+}
+
+func newGoalFixture(goals ...models.Goal) (*GoalService, *memTxRepo) {
+	repo := &memGoalRepo{goals: map[string]models.Goal{}}
+	for _, g := range goals {
+		repo.goals[g.ID] = g
+	}
+	ledger := &memTxRepo{}
+	accounts := accountsByID{
+		goalAccountID:   {ID: goalAccountID, UserID: "alice", Currency: "USD"},
+		sourceAccountID: {ID: sourceAccountID, UserID: "alice", Currency: "USD", OpeningBalance: 1000},
+		euroAccountID:   {ID: euroAccountID, UserID: "alice", Currency: "EUR", OpeningBalance: 1000},
+	}
+	return NewGoalService(repo, ledger, accounts, knownUsers{"alice"}, allowAll{}, TransactionQuota{}), ledger
+}
+
+func TestUpdateGoalDeadline(t *testing.T) {
+	ctx := context.Background()
+	past := time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC)
+	srv, _ := newGoalFixture(models.Goal{ID: "6650a1f1c2a4b5e6f7a8b9a1", UserID: "alice", Name: "Bike", Target: 500, Deadline: past,
+		Tag: "bike", CreatedAt: past.AddDate(-1, 0, 0)})
+	update := models.CreateGoal{ID: "6650a1f1c2a4b5e6f7a8b9a1", UserID: "alice", Name: "E-bike", Target: 900, Deadline: "2020-01-31", Tag: "bike"}
+
+	goal, err := srv.UpdateGoal(ctx, update)
+	if err != nil {
+		t.Fatalf("renaming a goal past its deadline: %v", err)
+	}
+	if goal.Name != "E-bike" || goal.Target != 900 {
+		t.Errorf("got %+v", goal.Goal)
+	}
+	update.Deadline = "2020-06-30"
+	_, err = srv.UpdateGoal(ctx, update)
+	if got := violations(t, err); got["deadline"] != "must be in the future" {
+		t.Errorf("moving the deadline into the past: got %v", got)
+	}
+}
+
+func TestAddContributionToAccountGoal(t *testing.T) {
+	ctx := context.Background()
+	goalID := "6650a1f1c2a4b5e6f7a8b9a2"
+	srv, ledger := newGoalFixture(models.Goal{ID: goalID, UserID: "alice", Name: "Car", Target: 5000,
+		Deadline: time.Now().UTC().AddDate(1, 0, 0), AccountID: goalAccountID, CreatedAt: time.Now().UTC()})
+	contribute := func(amount float64, from string) (string, error) {
+		return srv.AddContribution(ctx, models.GoalContribution{GoalID: goalID, UserID: "alice", Amount: amount, FromAccountID: from})
+	}
+
+	tests := []struct {
+		from  string
+		field string
+		want  string
+	}{
+		{from: "", field: "fromAccountId", want: "is required for a goal with an account"},
+		{from: goalAccountID, field: "fromAccountId", want: "must differ from the goal's account"},
+		{from: "6650a1f1c2a4b5e6f7a8b9ff", field: "fromAccountId", want: "account not found"},
+	}
+	for _, tt := range tests {
+		_, err := contribute(100, tt.from)
+		if got := violations(t, err); got[tt.field] != tt.want {
+			t.Errorf("from %q: got %v, want %s %s", tt.from, got, tt.field, tt.want)
+		}
+	}
+	if _, err := contribute(100, euroAccountID); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("cross-currency contribution: got %v, want FailedPrecondition", err)
+	}
+
+	id, err := contribute(300, sourceAccountID)
+	if err != nil {
+		t.Fatalf("AddContribution: %v", err)
+	}
+	if _, err := contribute(-50, sourceAccountID); err != nil {
+		t.Fatalf("withdrawal: %v", err)
+	}
+	if len(ledger.txs) != 4 || ledger.txs[1].ID != id || ledger.txs[1].AccountID != goalAccountID {
+		t.Fatalf("want two transfers with the goal-side leg returned, got %+v", ledger.txs)
+	}
+	balance := func(accountID string) float64 {
+		flow, _ := ledger.GetAccountFlow(ctx, "alice", accountID, time.Now().UTC())
+		return flow
+	}
+	if got, want := []float64{balance(goalAccountID), balance(sourceAccountID)}, []float64{250, -250}; !reflect.DeepEqual(got, want) {
+		t.Errorf("account flows: got %v, want %v", got, want)
+	}
+	progress, err := srv.GetGoal(ctx, goalID, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if progress.Saved != 250 || progress.Remaining != 4750 {
+		t.Errorf("progress %+v, want 250 saved", progress)
+	}
+	for _, tx := range ledger.txs {
+		if tx.GoalID != goalID || tx.IsSpending() {
+			t.Errorf("leg %+v should be linked to the goal and not count as spending", tx)
+		}
+	}
+}
+
+func TestAddContributionToTagGoal(t *testing.T) {
+	ctx := context.Background()
+	goalID := "6650a1f1c2a4b5e6f7a8b9a3"
+	srv, ledger := newGoalFixture(models.Goal{ID: goalID, UserID: "alice", Name: "Trip", Target: 1000,
+		Deadline: time.Now().UTC().AddDate(1, 0, 0), Tag: "trip", CreatedAt: time.Now().UTC()})
+
+	_, err := srv.AddContribution(ctx, models.GoalContribution{GoalID: goalID, UserID: "alice", Amount: 100, FromAccountID: sourceAccountID})
+	if got := violations(t, err); got["fromAccountId"] != "must be empty for a goal without an account" {
+		t.Errorf("got %v", got)
+	}
+	if _, err := srv.AddContribution(ctx, models.GoalContribution{GoalID: goalID, UserID: "alice", Amount: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if tx := ledger.txs[0]; tx.Kind != models.KindContribution || tx.AccountID != "" || !reflect.DeepEqual(tx.Tags, []string{"trip"}) {
+		t.Errorf("got %+v, want a tagged contribution without an account", tx)
+	}
+	progress, err := srv.GetGoal(ctx, goalID, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if progress.Saved != 100 {
+		t.Errorf("saved %v, want 100", progress.Saved)
+	}
+}
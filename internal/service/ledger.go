@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type LedgerRepository interface {
+	AddLedger(ctx context.Context, ledger models.Ledger) (string, error)
+	GetLedger(ctx context.Context, ledgerID string) (*models.Ledger, error)
+	GetLedgers(ctx context.Context, userID string) ([]models.Ledger, error)
+	AddInvite(ctx context.Context, ledgerID string, invite models.LedgerInvite) error
+	AcceptInvite(ctx context.Context, ledgerID string, member models.LedgerMember) (bool, error)
+	RemoveMember(ctx context.Context, ledgerID, userID string) (bool, error)
+}
+
+type LedgerTransactionRepository interface {
+	GetLedgerTransactions(ctx context.Context, ledgerID string, dateFrame models.TimeFrame) ([]models.Transaction, error)
+}
+
+type LedgerService struct {
+	LedgerRepo      LedgerRepository
+	TransactionRepo LedgerTransactionRepository
+	User            UserService
+	Access          AccessPolicy
+	validate        *Validator
+}
+
+func NewLedgerService(ledgerRepo LedgerRepository, txRepo LedgerTransactionRepository, user UserService, access AccessPolicy) *LedgerService {
+	return &LedgerService{LedgerRepo: ledgerRepo, TransactionRepo: txRepo, User: user, Access: access, validate: NewValidator()}
+}
+
+const (
+	maxLedgerMembers   = 20
+	maxLedgersPerOwner = 20
+)
+
+var errLedgerNotFound = status.Error(codes.NotFound, "ledger is not found")
+
+// roleRank orders roles so that a higher one includes every permission of
+// the lower ones.
+var roleRank = map[string]int{
+	models.RoleViewer: 1,
+	models.RoleEditor: 2,
+	models.RoleOwner:  3,
+}
+
+func (s *LedgerService) CreateLedger(ctx context.Context, create models.CreateLedger) (_ *models.Ledger, err error) {
+	ctx, span := tracer.Start(ctx, "LedgerService.CreateLedger", trace.WithAttributes(attribute.String("user.id", create.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(create); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, create.UserID); err != nil {
+		return nil, err
+	}
+	existing, err := s.LedgerRepo.GetLedgers(ctx, create.UserID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	owned := 0
+	for _, l := range existing {
+		if l.OwnerID == create.UserID {
+			owned++
+		}
+	}
+	if owned >= maxLedgersPerOwner {
+		return nil, status.Errorf(codes.FailedPrecondition, "user already owns %d ledgers", maxLedgersPerOwner)
+	}
+	now := time.Now().UTC()
+	ledger := models.Ledger{
+		Name:      create.Name,
+		OwnerID:   create.UserID,
+		Members:   []models.LedgerMember{{UserID: create.UserID, Role: models.RoleOwner, JoinedAt: now}},
+		Invites:   []models.LedgerInvite{},
+		CreatedAt: now,
+	}
+	ledger.ID, err = s.LedgerRepo.AddLedger(ctx, ledger)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return &ledger, nil
+}
+
+func (s *LedgerService) GetLedgers(ctx context.Context, userID string) (_ []models.Ledger, err error) {
+	ctx, span := tracer.Start(ctx, "LedgerService.GetLedgers", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.UserKey{UserID: userID}); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, userID); err != nil {
+		return nil, err
+	}
+	ledgers, err := s.LedgerRepo.GetLedgers(ctx, userID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return ledgers, nil
+}
+
+// InviteMember invites a user of the user service to the ledger. Only the
+// owner invites; inviting again replaces the pending invite's role.
+func (s *LedgerService) InviteMember(ctx context.Context, invite models.InviteMember) (_ *models.Ledger, err error) {
+	ctx, span := tracer.Start(ctx, "LedgerService.InviteMember", trace.WithAttributes(attribute.String("user.id", invite.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(invite); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, invite.UserID); err != nil {
+		return nil, err
+	}
+	ledger, err := s.ledgerWithRole(ctx, invite.LedgerID, invite.UserID, models.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := ledger.Member(invite.MemberID); ok {
+		return nil, &ValidationError{Violations: []FieldViolation{{Field: "memberId", Description: "user is already a member"}}}
+	}
+	if _, pending := ledger.Invite(invite.MemberID); !pending && len(ledger.Members)+len(ledger.Invites) >= maxLedgerMembers {
+		return nil, status.Errorf(codes.FailedPrecondition, "ledger already has %d members and invites", maxLedgerMembers)
+	}
+	id, _, err := s.User.GetUser(ctx, invite.MemberID)
+	if err != nil || id == "" {
+		log.Println(err)
+		return nil, &ValidationError{Violations: []FieldViolation{{Field: "memberId", Description: "user not found"}}}
+	}
+	err = s.LedgerRepo.AddInvite(ctx, invite.LedgerID, models.LedgerInvite{
+		UserID:    invite.MemberID,
+		Role:      invite.Role,
+		InvitedBy: invite.UserID,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return s.getLedger(ctx, invite.LedgerID)
+}
+
+func (s *LedgerService) AcceptInvite(ctx context.Context, key models.LedgerKey) (_ *models.Ledger, err error) {
+	ctx, span := tracer.Start(ctx, "LedgerService.AcceptInvite", trace.WithAttributes(attribute.String("user.id", key.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(key); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, key.UserID); err != nil {
+		return nil, err
+	}
+	ledger, err := s.getLedger(ctx, key.ID)
+	if err != nil {
+		return nil, err
+	}
+	invite, ok := ledger.Invite(key.UserID)
+	if !ok {
+		return nil, status.Error(codes.NotFound, "invite is not found")
+	}
+	accepted, err := s.LedgerRepo.AcceptInvite(ctx, key.ID, models.LedgerMember{
+		UserID:   key.UserID,
+		Role:     invite.Role,
+		JoinedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if !accepted {
+		return nil, status.Error(codes.NotFound, "invite is not found")
+	}
+	return s.getLedger(ctx, key.ID)
+}
+
+// RemoveMember removes a member or withdraws an invite. The owner may remove
+// anyone else; everyone else may only remove themselves.
+func (s *LedgerService) RemoveMember(ctx context.Context, remove models.RemoveMember) (err error) {
+	ctx, span := tracer.Start(ctx, "LedgerService.RemoveMember", trace.WithAttributes(attribute.String("user.id", remove.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(remove); err != nil {
+		return err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, remove.UserID); err != nil {
+		return err
+	}
+	ledger, err := s.getLedger(ctx, remove.LedgerID)
+	if err != nil {
+		return err
+	}
+	_, member := ledger.Member(remove.UserID)
+	_, invited := ledger.Invite(remove.UserID)
+	if !member && !invited {
+		return errLedgerNotFound
+	}
+	if remove.MemberID == ledger.OwnerID {
+		return status.Error(codes.FailedPrecondition, "the owner cannot be removed from the ledger")
+	}
+	if remove.MemberID != remove.UserID && remove.UserID != ledger.OwnerID {
+		return status.Error(codes.PermissionDenied, "only the owner can remove other members")
+	}
+	removed, err := s.LedgerRepo.RemoveMember(ctx, remove.LedgerID, remove.MemberID)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	if !removed {
+		return status.Error(codes.NotFound, "member is not found")
+	}
+	return nil
+}
+
+func (s *LedgerService) GetLedgerTransactions(ctx context.Context, key models.LedgerKey, timeframe models.CreateTimeFrame) (_ []models.Transaction, err error) {
+	ctx, span := tracer.Start(ctx, "LedgerService.GetLedgerTransactions", trace.WithAttributes(attribute.String("user.id", key.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(key, timeframe); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, key.UserID); err != nil {
+		return nil, err
+	}
+	if _, err := s.ledgerWithRole(ctx, key.ID, key.UserID, models.RoleViewer); err != nil {
+		return nil, err
+	}
+	tf, err := parseTimeFrame(timeframe)
+	if err != nil {
+		return nil, err
+	}
+	txs, err := s.TransactionRepo.GetLedgerTransactions(ctx, key.ID, tf)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return txs, nil
+}
+
+// CheckLedgerRole reports whether the user is a member of the ledger with
+// at least the given role. Non-members get NotFound so that ledgers are not
+// revealed to them.
+func (s *LedgerService) CheckLedgerRole(ctx context.Context, ledgerID, userID, role string) error {
+	_, err := s.ledgerWithRole(ctx, ledgerID, userID, role)
+	return err
+}
+
+func (s *LedgerService) ledgerWithRole(ctx context.Context, ledgerID, userID, role string) (*models.Ledger, error) {
+	ledger, err := s.getLedger(ctx, ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	member, ok := ledger.Member(userID)
+	if !ok {
+		return nil, errLedgerNotFound
+	}
+	if roleRank[member.Role] < roleRank[role] {
+		return nil, status.Errorf(codes.PermissionDenied, "ledger role %s is required", role)
+	}
+	return ledger, nil
+}
+
+func (s *LedgerService) getLedger(ctx context.Context, ledgerID string) (*models.Ledger, error) {
+	ledger, err := s.LedgerRepo.GetLedger(ctx, ledgerID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if ledger == nil {
+		return nil, errLedgerNotFound
+	}
+	return ledger, nil
+}
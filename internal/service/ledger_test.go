@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const ledgerID = "6650a1f1c2a4b5e6f7a8b9d0"
+
+// memLedgerRepo keeps one ledger in memory.
+type memLedgerRepo struct {
+	ledger *models.Ledger
+}
+
+func (r *memLedgerRepo) AddLedger(_ context.Context, ledger models.Ledger) (string, error) {
+	ledger.ID = ledgerID
+	r.ledger = &ledger
+	return ledger.ID, nil
+}
+
+func (r *memLedgerRepo) GetLedger(_ context.Context, id string) (*models.Ledger, error) {
+	if r.ledger == nil || r.ledger.ID != id {
+		return nil, nil
+	}
+	ledger := *r.ledger
+	ledger.Members = slices.Clone(r.ledger.Members)
+	ledger.Invites = slices.Clone(r.ledger.Invites)
+	return &ledger, nil
+}
+
+func (r *memLedgerRepo) GetLedgers(_ context.Context, userID string) ([]models.Ledger, error) {
+	if r.ledger == nil {
+		return nil, nil
+	}
+	if _, ok := r.ledger.Member(userID); !ok {
+		return nil, nil
+	}
+	return []models.Ledger{*r.ledger}, nil
+}
+
+func (r *memLedgerRepo) AddInvite(_ context.Context, _ string, invite models.LedgerInvite) error {
+	r.ledger.Invites = slices.DeleteFunc(r.ledger.Invites, func(i models.LedgerInvite) bool { return i.UserID == invite.UserID })
+	r.ledger.Invites = append(r.ledger.Invites, invite)
+	return nil
+}
+
+func (r *memLedgerRepo) AcceptInvite(_ context.Context, _ string, member models.LedgerMember) (bool, error) {
+	if _, ok := r.ledger.Invite(member.UserID); !ok {
+		return false, nil
+	}
+	r.ledger.Invites = slices.DeleteFunc(r.ledger.Invites, func(i models.LedgerInvite) bool { return i.UserID == member.UserID })
+	r.ledger.Members = append(r.ledger.Members, member)
+	return true, nil
+}
+
+func (r *memLedgerRepo) RemoveMember(_ context.Context, _ string, userID string) (bool, error) {
+	members, invites := len(r.ledger.Members), len(r.ledger.Invites)
+	r.ledger.Members = slices.DeleteFunc(r.ledger.Members, func(m models.LedgerMember) bool { return m.UserID == userID })
+	r.ledger.Invites = slices.DeleteFunc(r.ledger.Invites, func(i models.LedgerInvite) bool { return i.UserID == userID })
+	return len(r.ledger.Members) != members || len(r.ledger.Invites) != invites, nil
+}
+
+type noAttachments struct{}
+
+func (noAttachments) DeleteTxAttachments(context.Context, string, ...string) error { return nil }
+
+// newLedgerFixture creates alice's ledger with bob as an editor and carol as
+// a viewer. dave exists but is not a member.
+func newLedgerFixture(t *testing.T, txRepo *memTxRepo) (*LedgerService, *memLedgerRepo) {
+	t.Helper()
+	ctx := context.Background()
+	repo := &memLedgerRepo{}
+	srv := NewLedgerService(repo, txRepo, knownUsers{"alice", "bob", "carol", "dave"}, allowAll{})
+	if _, err := srv.CreateLedger(ctx, models.CreateLedger{UserID: "alice", Name: "Home"}); err != nil {
+		t.Fatalf("CreateLedger: %v", err)
+	}
+	for member, role := range map[string]string{"bob": models.RoleEditor, "carol": models.RoleViewer} {
+		if _, err := srv.InviteMember(ctx, models.InviteMember{LedgerID: ledgerID, UserID: "alice", MemberID: member, Role: role}); err != nil {
+			t.Fatalf("InviteMember(%s): %v", member, err)
+		}
+		if _, err := srv.AcceptInvite(ctx, models.LedgerKey{ID: ledgerID, UserID: member}); err != nil {
+			t.Fatalf("AcceptInvite(%s): %v", member, err)
+		}
+	}
+	return srv, repo
+}
+
+func TestLedgerMembership(t *testing.T) {
+	ctx := context.Background()
+	srv, repo := newLedgerFixture(t, &memTxRepo{})
+	if member, ok := repo.ledger.Member("carol"); !ok || member.Role != models.RoleViewer || len(repo.ledger.Invites) != 0 {
+		t.Fatalf("carol must have joined as a viewer: %+v", repo.ledger)
+	}
+
+	invite := func(by, member string) models.InviteMember {
+		return models.InviteMember{LedgerID: ledgerID, UserID: by, MemberID: member, Role: models.RoleViewer}
+	}
+	if _, err := srv.InviteMember(ctx, invite("bob", "dave")); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("editor inviting: got %v, want PermissionDenied", err)
+	}
+	if _, err := srv.InviteMember(ctx, invite("dave", "dave")); status.Code(err) != codes.NotFound {
+		t.Errorf("non-member inviting: got %v, want NotFound", err)
+	}
+	_, err := srv.InviteMember(ctx, invite("alice", "carol"))
+	if got := violations(t, err); got["memberId"] != "user is already a member" {
+		t.Errorf("inviting a member: got %v", got)
+	}
+	_, err = srv.InviteMember(ctx, invite("alice", "user-404"))
+	if got := violations(t, err); got["memberId"] != "user not found" {
+		t.Errorf("inviting an unknown user: got %v", got)
+	}
+	if _, err := srv.AcceptInvite(ctx, models.LedgerKey{ID: ledgerID, UserID: "dave"}); status.Code(err) != codes.NotFound {
+		t.Errorf("accepting without an invite: got %v, want NotFound", err)
+	}
+
+	remove := func(by, member string) error {
+		return srv.RemoveMember(ctx, models.RemoveMember{LedgerID: ledgerID, UserID: by, MemberID: member})
+	}
+	if err := remove("carol", "bob"); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("viewer removing an editor: got %v, want PermissionDenied", err)
+	}
+	if err := remove("alice", "alice"); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("removing the owner: got %v, want FailedPrecondition", err)
+	}
+	if err := remove("dave", "bob"); status.Code(err) != codes.NotFound {
+		t.Errorf("non-member removing: got %v, want NotFound", err)
+	}
+	if err := remove("carol", "carol"); err != nil {
+		t.Errorf("leaving the ledger: %v", err)
+	}
+	if err := remove("alice", "bob"); err != nil {
+		t.Errorf("owner removing an editor: %v", err)
+	}
+	if len(repo.ledger.Members) != 1 {
+		t.Errorf("members left: %+v, want only the owner", repo.ledger.Members)
+	}
+}
+
+func TestLedgerTransactionAccess(t *testing.T) {
+	ctx := context.Background()
+	const shared, private = "6650a1f1c2a4b5e6f7a80001", "6650a1f1c2a4b5e6f7a80002"
+	day := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := &memTxRepo{txs: []models.Transaction{
+		{ID: shared, UserID: "alice", LedgerID: ledgerID, Name: "Groceries", Cost: 20, Date: day},
+		{ID: private, UserID: "alice", Name: "Gift", Cost: 50, Date: day},
+	}}
+	ledgers, _ := newLedgerFixture(t, repo)
+
+	march := models.CreateTimeFrame{StartDate: "2024-03-01", EndDate: "2024-03-31"}
+	txs, err := ledgers.GetLedgerTransactions(ctx, models.LedgerKey{ID: ledgerID, UserID: "carol"}, march)
+	if err != nil || len(txs) != 1 || txs[0].ID != shared {
+		t.Errorf("viewer listing the ledger: got %+v, %v, want the shared transaction", txs, err)
+	}
+	if _, err := ledgers.GetLedgerTransactions(ctx, models.LedgerKey{ID: ledgerID, UserID: "dave"}, march); status.Code(err) != codes.NotFound {
+		t.Errorf("non-member listing the ledger: got %v, want NotFound", err)
+	}
+	srv := NewTransactionService(repo, accountsByID{}, noAttachments{}, nil, nil, nil, ledgers, nil,
+		knownUsers{"alice", "bob", "carol", "dave"}, allowAll{}, AnomalyConfig{}, TransactionQuota{})
+
+	tests := []struct {
+		userID string
+		txID   string
+		found  bool
+	}{
+		{userID: "carol", txID: shared, found: true},
+		{userID: "carol", txID: private},
+		{userID: "dave", txID: shared},
+		{userID: "alice", txID: private, found: true},
+	}
+	for _, tt := range tests {
+		tx, err := srv.GetTransaction(ctx, tt.txID, tt.userID)
+		if err != nil || (tx != nil) != tt.found {
+			t.Errorf("%s reading %s: got %+v, %v, want found %v", tt.userID, tt.txID, tx, err, tt.found)
+		}
+	}
+
+	note := "split with bob"
+	update := func(userID string) (*models.Transaction, error) {
+		return srv.UpdateTx(ctx, models.UpdateTransaction{ID: shared, UserID: userID, Note: &note})
+	}
+	if _, err := update("carol"); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("viewer editing: got %v, want PermissionDenied", err)
+	}
+	if _, err := update("dave"); err == nil {
+		t.Error("non-member editing must fail")
+	}
+	tx, err := update("bob")
+	if err != nil {
+		t.Fatalf("editor editing: %v", err)
+	}
+	if tx.Note != note || tx.UserID != "alice" || tx.LedgerID != ledgerID {
+		t.Errorf("edited transaction: got %+v, want the note changed and author and ledger kept", tx)
+	}
+
+	if err := srv.DeleteTx(ctx, "carol", shared); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("viewer deleting: got %v, want PermissionDenied", err)
+	}
+	if err := srv.DeleteTx(ctx, "bob", private); err == nil {
+		t.Error("deleting another member's private transaction must fail")
+	}
+	if err := srv.DeleteTx(ctx, "bob", shared); err != nil {
+		t.Errorf("editor deleting: %v", err)
+	}
+	if len(repo.txs) != 1 || repo.txs[0].ID != private {
+		t.Errorf("transactions left: %+v, want only the private one", repo.txs)
+	}
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"github.com/justIGreK/MoneyKeeper-Transaction/pkg/client"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type OrphanTransactionRepository interface {
+	GetUserIDs(ctx context.Context) ([]string, error)
+	CountUserTransactions(ctx context.Context, userID string) (int64, error)
+}
+
+// OrphanSightingRepository remembers when users were first found missing.
+type OrphanSightingRepository interface {
+	GetOrphanSightings(ctx context.Context) (map[string]time.Time, error)
+	AddOrphanSighting(ctx context.Context, userID string, seenAt time.Time) error
+	DeleteOrphanSighting(ctx context.Context, userID string) error
+}
+
+type UserDataRemover interface {
+	WriteUserData(ctx context.Context, userID string, w io.Writer) error
+	PurgeUserData(ctx context.Context, userID string) (*models.Erasure, error)
+}
+
+// Archiver stores what write produces for the user and returns where it went.
+type Archiver interface {
+	Archive(ctx context.Context, userID string, write func(w io.Writer) error) (string, error)
+}
+
+type OrphanService struct {
+	TransactionRepo OrphanTransactionRepository
+	SightingRepo    OrphanSightingRepository
+	Data            UserDataRemover
+	Archiver        Archiver
+	User            UserService
+	Access          AdminPolicy
+	policy          string
+	// confirmAfter is how long a user must stay missing before removal.
+	confirmAfter time.Duration
+	validate     *Validator
+}
+
+func NewOrphanService(txRepo OrphanTransactionRepository, sightingRepo OrphanSightingRepository, data UserDataRemover, archiver Archiver,
+	user UserService, access AdminPolicy, policy string, confirmAfter time.Duration) *OrphanService {
+	return &OrphanService{TransactionRepo: txRepo, SightingRepo: sightingRepo, Data: data, Archiver: archiver, User: user, Access: access,
+		policy: policy, confirmAfter: confirmAfter, validate: NewValidator()}
+}
+
+func (s *OrphanService) ReconcileOrphans(ctx context.Context, req models.ReconcileOrphans) (_ *models.OrphanReport, err error) {
+	ctx, span := tracer.Start(ctx, "OrphanService.ReconcileOrphans", trace.WithAttributes(attribute.Bool("confirm", req.Confirm)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(req); err != nil {
+		return nil, err
+	}
+	if err := s.Access.CheckPrivileged(ctx); err != nil {
+		return nil, err
+	}
+	return s.Reconcile(ctx, !req.Confirm, req.Policy)
+}
+
+// Reconcile looks for users with transactions that the user service no
+// longer knows and archives or purges their data according to policy, the
+// configured one when empty. Only a definite "not found" makes a user an
+// orphan; lookup failures are counted as unverified and retried on the next
+// run. An orphan is left pending until a run at least confirmAfter after the
+// one that first found it missing still does not find it, and is forgotten
+// if it turns up again. A dry run records sightings but removes nothing. It
+// does not check access; the reconciliation job uses it directly.
+func (s *OrphanService) Reconcile(ctx context.Context, dryRun bool, policy string) (*models.OrphanReport, error) {
+	if policy == "" {
+		policy = s.policy
+	}
+	if policy != models.OrphanArchive && policy != models.OrphanPurge {
+		return nil, fmt.Errorf("unknown orphan policy %q", policy)
+	}
+	report := &models.OrphanReport{DryRun: dryRun, Policy: policy, StartedAt: time.Now().UTC()}
+	userIDs, err := s.TransactionRepo.GetUserIDs(ctx)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	sightings, err := s.SightingRepo.GetOrphanSightings(ctx)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	for _, userID := range userIDs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		report.CheckedUsers++
+		_, _, err := s.User.GetUser(ctx, userID)
+		if err == nil {
+			if _, ok := sightings[userID]; ok {
+				if err := s.SightingRepo.DeleteOrphanSighting(ctx, userID); err != nil {
+					log.Println(err)
+					return nil, err
+				}
+			}
+			continue
+		}
+		if !errors.Is(err, client.ErrUserNotFound) {
+			log.Printf("orphan check for user %s: %v", userID, err)
+			report.Unverified++
+			continue
+		}
+		firstSeen, ok := sightings[userID]
+		if !ok {
+			firstSeen = time.Now().UTC()
+			if err := s.SightingRepo.AddOrphanSighting(ctx, userID, firstSeen); err != nil {
+				log.Println(err)
+				return nil, err
+			}
+		}
+		orphan := models.OrphanedUser{UserID: userID, Action: policy, FirstSeen: firstSeen}
+		if time.Since(firstSeen) < s.confirmAfter {
+			orphan.Action = models.OrphanPending
+		}
+		orphan.Transactions, err = s.TransactionRepo.CountUserTransactions(ctx, userID)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		if !dryRun && orphan.Action != models.OrphanPending {
+			s.removeOrphan(ctx, &orphan)
+		}
+		report.Orphans = append(report.Orphans, orphan)
+	}
+	report.FinishedAt = time.Now().UTC()
+	return report, nil
+}
+
+// removeOrphan applies the orphan's action and records a failure on it, so
+// one user's failure does not stop the rest.
+func (s *OrphanService) removeOrphan(ctx context.Context, orphan *models.OrphanedUser) {
+	if orphan.Action == models.OrphanArchive {
+		location, err := s.Archiver.Archive(ctx, orphan.UserID, func(w io.Writer) error {
+			return s.Data.WriteUserData(ctx, orphan.UserID, w)
+		})
+		if err != nil {
+			log.Printf("archive orphaned user %s: %v", orphan.UserID, err)
+			orphan.Error = err.Error()
+			return
+		}
+		orphan.Archive = location
+	}
+	if _, err := s.Data.PurgeUserData(ctx, orphan.UserID); err != nil {
+		log.Printf("purge orphaned user %s: %v", orphan.UserID, err)
+		orphan.Error = err.Error()
+		return
+	}
+	if err := s.SightingRepo.DeleteOrphanSighting(ctx, orphan.UserID); err != nil {
+		log.Printf("forget orphaned user %s: %v", orphan.UserID, err)
+	}
+}
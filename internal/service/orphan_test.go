@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"github.com/justIGreK/MoneyKeeper-Transaction/pkg/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type orphanTxRepo map[string]int64
+
+func (r orphanTxRepo) GetUserIDs(context.Context) ([]string, error) {
+	ids := make([]string, 0, len(r))
+	for id := range r {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+func (r orphanTxRepo) CountUserTransactions(_ context.Context, userID string) (int64, error) {
+	return r[userID], nil
+}
+
+type memSightings map[string]time.Time
+
+func (s memSightings) GetOrphanSightings(context.Context) (map[string]time.Time, error) {
+	seen := make(map[string]time.Time, len(s))
+	for id, at := range s {
+		seen[id] = at
+	}
+	return seen, nil
+}
+
+func (s memSightings) AddOrphanSighting(_ context.Context, userID string, seenAt time.Time) error {
+	if _, ok := s[userID]; !ok {
+		s[userID] = seenAt
+	}
+	return nil
+}
+
+func (s memSightings) DeleteOrphanSighting(_ context.Context, userID string) error {
+	delete(s, userID)
+	return nil
+}
+
+type fakeRemover struct {
+	purged []string
+}
+
+func (r *fakeRemover) WriteUserData(_ context.Context, userID string, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "data of %s", userID)
+	return err
+}
+
+func (r *fakeRemover) PurgeUserData(_ context.Context, userID string) (*models.Erasure, error) {
+	r.purged = append(r.purged, userID)
+	return &models.Erasure{UserID: userID}, nil
+}
+
+type discardArchiver struct{}
+
+func (discardArchiver) Archive(_ context.Context, userID string, write func(w io.Writer) error) (string, error) {
+	return "archive/" + userID, write(io.Discard)
+}
+
+// userLookups answers each user with the given error; users not listed exist.
+type userLookups map[string]error
+
+func (u userLookups) GetUser(_ context.Context, id string) (string, string, error) {
+	if err, ok := u[id]; ok {
+		return "", "", err
+	}
+	return id, "", nil
+}
+
+type privileged struct{}
+
+func (privileged) CheckPrivileged(context.Context) error { return nil }
+
+func newOrphanFixture(users userLookups) (*OrphanService, memSightings, *fakeRemover) {
+	sightings := memSightings{}
+	remover := &fakeRemover{}
+	txs := orphanTxRepo{"alice": 3, "ghost": 7, "flaky": 1, "blank": 2}
+	srv := NewOrphanService(txs, sightings, remover, discardArchiver{}, users, privileged{}, models.OrphanArchive, time.Hour)
+	return srv, sightings, remover
+}
+
+func TestReconcileOrphansDefaultsToDryRun(t *testing.T) {
+	ctx := context.Background()
+	srv, sightings, remover := newOrphanFixture(userLookups{"ghost": client.ErrUserNotFound})
+	sightings["ghost"] = time.Now().Add(-2 * time.Hour)
+
+	report, err := srv.ReconcileOrphans(ctx, models.ReconcileOrphans{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.DryRun || len(report.Orphans) != 1 || report.Orphans[0].Action != models.OrphanArchive {
+		t.Fatalf("got %+v, want a dry run reporting ghost as to be archived", report)
+	}
+	if len(remover.purged) != 0 {
+		t.Errorf("dry run purged %v", remover.purged)
+	}
+
+	report, err = srv.ReconcileOrphans(ctx, models.ReconcileOrphans{Confirm: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.DryRun || report.Orphans[0].Archive != "archive/ghost" || !slices.Equal(remover.purged, []string{"ghost"}) {
+		t.Errorf("confirmed run: got %+v, purged %v", report, remover.purged)
+	}
+}
+
+func TestReconcileWaitsForSecondSighting(t *testing.T) {
+	ctx := context.Background()
+	srv, sightings, remover := newOrphanFixture(userLookups{"ghost": client.ErrUserNotFound})
+
+	report, err := srv.Reconcile(ctx, false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Orphans) != 1 || report.Orphans[0].Action != models.OrphanPending || report.Orphans[0].Transactions != 7 {
+		t.Fatalf("first sighting: got %+v, want ghost pending", report.Orphans)
+	}
+	firstSeen, ok := sightings["ghost"]
+	if !ok || len(remover.purged) != 0 {
+		t.Fatalf("first sighting must be recorded and nothing purged: sightings %v, purged %v", sightings, remover.purged)
+	}
+
+	// a second run soon after is not enough.
+	if report, err = srv.Reconcile(ctx, false, ""); err != nil {
+		t.Fatal(err)
+	}
+	if report.Orphans[0].Action != models.OrphanPending || !report.Orphans[0].FirstSeen.Equal(firstSeen) || len(remover.purged) != 0 {
+		t.Fatalf("early second run: got %+v, purged %v", report.Orphans, remover.purged)
+	}
+
+	sightings["ghost"] = firstSeen.Add(-time.Hour)
+	if report, err = srv.Reconcile(ctx, false, models.OrphanPurge); err != nil {
+		t.Fatal(err)
+	}
+	if o := report.Orphans[0]; o.Action != models.OrphanPurge || o.Error != "" || o.Archive != "" {
+		t.Errorf("confirmed orphan: got %+v, want purged without an archive", o)
+	}
+	if !slices.Equal(remover.purged, []string{"ghost"}) {
+		t.Errorf("purged %v, want ghost", remover.purged)
+	}
+	if _, ok := sightings["ghost"]; ok {
+		t.Error("the sighting of a purged user must be forgotten")
+	}
+}
+
+func TestReconcileOnlyTrustsNotFound(t *testing.T) {
+	ctx := context.Background()
+	srv, sightings, remover := newOrphanFixture(userLookups{
+		"flaky": status.Error(codes.Unavailable, "down"),
+		"blank": client.ErrEmptyUser,
+	})
+	sightings["blank"] = time.Now().Add(-2 * time.Hour)
+
+	report, err := srv.Reconcile(ctx, false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.CheckedUsers != 4 || report.Unverified != 2 || len(report.Orphans) != 0 {
+		t.Errorf("got %+v, want two unverified users and no orphans", report)
+	}
+	if len(remover.purged) != 0 {
+		t.Errorf("purged %v", remover.purged)
+	}
+	if _, ok := sightings["flaky"]; ok {
+		t.Error("an unverified user must not be recorded as missing")
+	}
+}
+
+func TestReconcileForgetsUsersThatReturn(t *testing.T) {
+	srv, sightings, _ := newOrphanFixture(userLookups{})
+	sightings["alice"] = time.Now().Add(-2 * time.Hour)
+	if _, err := srv.Reconcile(context.Background(), true, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sightings["alice"]; ok {
+		t.Error("a user the user service knows again must be forgotten")
+	}
+}
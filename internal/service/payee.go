@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/payee"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/rules"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type PayeeRepository interface {
+	ResolvePayee(ctx context.Context, userID, key, name string) (*models.Payee, error)
+	GetPayee(ctx context.Context, payeeID, userID string) (*models.Payee, error)
+	GetPayees(ctx context.Context, userID string) ([]models.Payee, error)
+	MergePayees(ctx context.Context, target models.Payee, sources []models.Payee) (int64, error)
+}
+
+type PayeeTotalsRepository interface {
+	GetPayeeTotals(ctx context.Context, userID string, dateFrame models.TimeFrame) ([]models.PayeeTotal, error)
+}
+
+type PayeeService struct {
+	PayeeRepo       PayeeRepository
+	TransactionRepo PayeeTotalsRepository
+	Normalizer      *payee.Normalizer
+	User            UserService
+	Access          AccessPolicy
+	validate        *Validator
+}
+
+func NewPayeeService(payeeRepo PayeeRepository, txRepo PayeeTotalsRepository, normalizer *payee.Normalizer, user UserService, access AccessPolicy) *PayeeService {
+	return &PayeeService{PayeeRepo: payeeRepo, TransactionRepo: txRepo, Normalizer: normalizer,
+		User: user, Access: access, validate: NewValidator()}
+}
+
+// LinkPayee returns the ID of the payee a transaction name belongs to,
+// creating the payee on first sight. Names that normalize to nothing are
+// left unlinked.
+func (s *PayeeService) LinkPayee(ctx context.Context, userID, name string) (string, error) {
+	key := s.Normalizer.Key(name)
+	if key == "" {
+		return "", nil
+	}
+	p, err := s.PayeeRepo.ResolvePayee(ctx, userID, key, payee.DisplayName(key))
+	if err != nil {
+		return "", err
+	}
+	return p.ID, nil
+}
+
+func (s *PayeeService) GetPayees(ctx context.Context, userID string) (_ []models.Payee, err error) {
+	ctx, span := tracer.Start(ctx, "PayeeService.GetPayees", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.UserKey{UserID: userID}); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, userID); err != nil {
+		return nil, err
+	}
+	payees, err := s.PayeeRepo.GetPayees(ctx, userID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return payees, nil
+}
+
+func (s *PayeeService) MergePayees(ctx context.Context, req models.MergePayees) (_ *models.Payee, _ int64, err error) {
+	ctx, span := tracer.Start(ctx, "PayeeService.MergePayees", trace.WithAttributes(attribute.String("user.id", req.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(req); err != nil {
+		return nil, 0, err
+	}
+	for _, id := range req.SourceIDs {
+		if id == req.TargetID {
+			return nil, 0, &ValidationError{Violations: []FieldViolation{{Field: "sourceIds", Description: "must not contain targetId"}}}
+		}
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, req.UserID); err != nil {
+		return nil, 0, err
+	}
+	target, err := s.getPayee(ctx, req.TargetID, req.UserID)
+	if err != nil {
+		return nil, 0, err
+	}
+	sources := make([]models.Payee, len(req.SourceIDs))
+	for i, id := range req.SourceIDs {
+		p, err := s.getPayee(ctx, id, req.UserID)
+		if err != nil {
+			return nil, 0, err
+		}
+		sources[i] = *p
+	}
+	if req.Name != "" {
+		target.Name = req.Name
+	}
+	moved, err := s.PayeeRepo.MergePayees(ctx, *target, sources)
+	if err != nil {
+		log.Println(err)
+		return nil, 0, err
+	}
+	for _, p := range sources {
+		target.Aliases = rules.MergeTags(target.Aliases, p.Aliases)
+	}
+	return target, moved, nil
+}
+
+func (s *PayeeService) GetPayeeSummary(ctx context.Context, userID string, timeframe models.CreateTimeFrame) (_ []models.PayeeTotal, err error) {
+	ctx, span := tracer.Start(ctx, "PayeeService.GetPayeeSummary", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.UserKey{UserID: userID}, timeframe); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, userID); err != nil {
+		return nil, err
+	}
+	tf, err := parseTimeFrame(timeframe)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	totals, err := s.TransactionRepo.GetPayeeTotals(ctx, userID, tf)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	payees, err := s.PayeeRepo.GetPayees(ctx, userID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	names := make(map[string]string, len(payees))
+	for _, p := range payees {
+		names[p.ID] = p.Name
+	}
+	for i := range totals {
+		totals[i].Name = names[totals[i].PayeeID]
+	}
+	return totals, nil
+}
+
+func (s *PayeeService) getPayee(ctx context.Context, payeeID, userID string) (*models.Payee, error) {
+	p, err := s.PayeeRepo.GetPayee(ctx, payeeID, userID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if p == nil {
+		return nil, status.Errorf(codes.NotFound, "payee %s is not found", payeeID)
+	}
+	return p, nil
+}
@@ -0,0 +1,344 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"path"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type PrivacyRepository interface {
+	GetErasure(ctx context.Context, userID string) (*models.Erasure, error)
+	SaveErasure(ctx context.Context, erasure models.Erasure) error
+	EraseUserRecords(ctx context.Context, store, userID, anonymousID string) (int64, error)
+	CountUserRecords(ctx context.Context, store, userID string) (int64, error)
+	StreamAuditEntries(ctx context.Context, userID string, fn func(models.AuditEntry) error) error
+}
+
+type PrivacyTransactionRepository interface {
+	StreamUserTransactions(ctx context.Context, userID string, fn func(models.Transaction) error) error
+	DeleteUserTransactions(ctx context.Context, userID string) (int64, error)
+	CountUserTransactions(ctx context.Context, userID string) (int64, error)
+}
+
+type PrivacyAttachmentRepository interface {
+	GetAttachments(ctx context.Context, userID string, txIDs ...string) ([]models.Attachment, error)
+	OpenAttachment(ctx context.Context, attachmentID string) (io.ReadCloser, error)
+	DeleteUserAttachments(ctx context.Context, userID string) (int64, error)
+	CountUserAttachments(ctx context.Context, userID string) (int64, error)
+}
+
+type AdminPolicy interface {
+	CheckPrivileged(ctx context.Context) error
+}
+
+type PrivacyService struct {
+	PrivacyRepo     PrivacyRepository
+	TransactionRepo PrivacyTransactionRepository
+	AttachmentRepo  PrivacyAttachmentRepository
+	Budgets         BudgetProvider
+	Access          AdminPolicy
+	validate        *Validator
+}
+
+func NewPrivacyService(privacyRepo PrivacyRepository, txRepo PrivacyTransactionRepository, attachmentRepo PrivacyAttachmentRepository, budgets BudgetProvider, access AdminPolicy) *PrivacyService {
+	return &PrivacyService{PrivacyRepo: privacyRepo, TransactionRepo: txRepo, AttachmentRepo: attachmentRepo,
+		Budgets: budgets, Access: access, validate: NewValidator()}
+}
+
+// erasureOrder lists the stores in the order they are erased. Attachments
+// go before the transactions they belong to and the outbox after everything
+// that could still add events to it. Budgets come from configuration and are
+// not stored per user, so there is nothing to erase for them.
+var erasureOrder = []string{
+	models.StoreAttachments,
+	models.StoreTransactions,
+	models.StoreAccounts,
+	models.StoreGoals,
+	models.StoreLedgers,
+	models.StorePayees,
+	models.StoreRules,
+	models.StoreCategoryModels,
+	models.StoreWebhooks,
+	models.StoreDeliveries,
+	models.StoreStatements,
+	models.StoreAudit,
+	models.StoreSharedExpenses,
+	models.StoreGroups,
+	models.StoreOutbox,
+}
+
+// ExportUserData writes a zip archive with everything stored for the user:
+// transactions, attachment metadata and content, budgets and audit entries.
+// Records are JSON, one per line, in the same encoding as published events.
+func (s *PrivacyService) ExportUserData(ctx context.Context, userID string, w io.Writer) (err error) {
+	ctx, span := tracer.Start(ctx, "PrivacyService.ExportUserData", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.UserKey{UserID: userID}); err != nil {
+		return err
+	}
+	if err := s.Access.CheckPrivileged(ctx); err != nil {
+		return err
+	}
+	return s.WriteUserData(ctx, userID, w)
+}
+
+// WriteUserData writes the export archive without checking access; the
+// orphan reconciler uses it directly.
+func (s *PrivacyService) WriteUserData(ctx context.Context, userID string, w io.Writer) error {
+	archive := zip.NewWriter(w)
+	manifest := models.ExportManifest{UserID: userID, ExportedAt: time.Now().UTC(), Files: map[string]int{}}
+
+	err := s.exportLines(archive, manifest, "transactions.jsonl", func(emit func(any) error) error {
+		return s.TransactionRepo.StreamUserTransactions(ctx, userID, func(tx models.Transaction) error { return emit(tx) })
+	})
+	if err != nil {
+		return err
+	}
+	attachments, err := s.AttachmentRepo.GetAttachments(ctx, userID)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	err = s.exportLines(archive, manifest, "attachments.jsonl", func(emit func(any) error) error {
+		for _, a := range attachments {
+			if err := emit(a); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, a := range attachments {
+		if err := s.exportAttachment(ctx, archive, a); err != nil {
+			return err
+		}
+	}
+	manifest.Files["attachments/"] = len(attachments)
+	budgets, err := s.Budgets.GetBudgets(ctx, userID)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	err = s.exportLines(archive, manifest, "budgets.jsonl", func(emit func(any) error) error {
+		for _, b := range budgets {
+			if err := emit(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	err = s.exportLines(archive, manifest, "audit.jsonl", func(emit func(any) error) error {
+		return s.PrivacyRepo.StreamAuditEntries(ctx, userID, func(entry models.AuditEntry) error { return emit(entry) })
+	})
+	if err != nil {
+		return err
+	}
+
+	f, err := archive.Create("manifest.json")
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// exportLines adds a JSON lines file filled by the records produce emits and
+// counts them in the manifest.
+func (s *PrivacyService) exportLines(archive *zip.Writer, manifest models.ExportManifest, name string, produce func(emit func(any) error) error) error {
+	f, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	n := 0
+	err = produce(func(record any) error {
+		n++
+		return enc.Encode(record)
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	manifest.Files[name] = n
+	return nil
+}
+
+func (s *PrivacyService) exportAttachment(ctx context.Context, archive *zip.Writer, a models.Attachment) error {
+	content, err := s.AttachmentRepo.OpenAttachment(ctx, a.ID)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer content.Close()
+	name := path.Base(a.FileName)
+	if name == "." || name == ".." || name == "/" {
+		name = a.ID
+	}
+	f, err := archive.Create(path.Join("attachments", a.ID, name))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, content)
+	return err
+}
+
+// EraseUserData deletes everything stored for the user and returns the
+// receipt. Each store is recorded once it is verified empty, so calling it
+// again after an interruption resumes with the remaining stores. After
+// completion it starts a new erasure if data was stored for the user since,
+// and fails with FailedPrecondition otherwise.
+func (s *PrivacyService) EraseUserData(ctx context.Context, userID string) (_ *models.Erasure, err error) {
+	ctx, span := tracer.Start(ctx, "PrivacyService.EraseUserData", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.UserKey{UserID: userID}); err != nil {
+		return nil, err
+	}
+	if err := s.Access.CheckPrivileged(ctx); err != nil {
+		return nil, err
+	}
+	return s.PurgeUserData(ctx, userID)
+}
+
+// PurgeUserData runs the erasure without checking access; the orphan
+// reconciler uses it directly. A completed erasure is replaced by a new one
+// when the user has data again.
+func (s *PrivacyService) PurgeUserData(ctx context.Context, userID string) (*models.Erasure, error) {
+	erasure, err := s.PrivacyRepo.GetErasure(ctx, userID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if erasure != nil && erasure.CompletedAt != nil {
+		remaining, err := s.hasUserData(ctx, userID)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		if !remaining {
+			return nil, status.Error(codes.FailedPrecondition, "user data is already erased")
+		}
+		erasure = nil
+	}
+	if erasure == nil {
+		erasure = &models.Erasure{
+			UserID:      userID,
+			StartedAt:   erasureTime(),
+			Steps:       []models.ErasureStep{},
+			AnonymousID: "erased-" + primitive.NewObjectID().Hex(),
+		}
+		if err := s.PrivacyRepo.SaveErasure(ctx, *erasure); err != nil {
+			log.Println(err)
+			return nil, err
+		}
+	}
+
+	for _, store := range erasureOrder {
+		if erasure.Done(store) {
+			continue
+		}
+		deleted, err := s.eraseStore(ctx, store, *erasure)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		remaining, err := s.countStore(ctx, store, userID)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		if remaining > 0 {
+			return nil, status.Errorf(codes.Aborted, "%d %s records were added during erasure, retry to continue", remaining, store)
+		}
+		erasure.Steps = append(erasure.Steps, models.ErasureStep{Store: store, Deleted: deleted, CompletedAt: erasureTime()})
+		if err := s.PrivacyRepo.SaveErasure(ctx, *erasure); err != nil {
+			log.Println(err)
+			return nil, err
+		}
+	}
+
+	completed := erasureTime()
+	erasure.CompletedAt = &completed
+	erasure.AnonymousID = ""
+	erasure.Digest, err = ReceiptDigest(*erasure)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.PrivacyRepo.SaveErasure(ctx, *erasure); err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return erasure, nil
+}
+
+func (s *PrivacyService) eraseStore(ctx context.Context, store string, erasure models.Erasure) (int64, error) {
+	switch store {
+	case models.StoreAttachments:
+		return s.AttachmentRepo.DeleteUserAttachments(ctx, erasure.UserID)
+	case models.StoreTransactions:
+		return s.TransactionRepo.DeleteUserTransactions(ctx, erasure.UserID)
+	}
+	return s.PrivacyRepo.EraseUserRecords(ctx, store, erasure.UserID, erasure.AnonymousID)
+}
+
+// hasUserData reports whether any store still holds records of the user.
+func (s *PrivacyService) hasUserData(ctx context.Context, userID string) (bool, error) {
+	for _, store := range erasureOrder {
+		n, err := s.countStore(ctx, store, userID)
+		if err != nil {
+			return false, err
+		}
+		if n > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *PrivacyService) countStore(ctx context.Context, store, userID string) (int64, error) {
+	switch store {
+	case models.StoreAttachments:
+		return s.AttachmentRepo.CountUserAttachments(ctx, userID)
+	case models.StoreTransactions:
+		return s.TransactionRepo.CountUserTransactions(ctx, userID)
+	}
+	return s.PrivacyRepo.CountUserRecords(ctx, store, userID)
+}
+
+// ReceiptDigest returns the hex SHA-256 of the receipt's JSON encoding, which
+// anyone holding the receipt can recompute.
+func ReceiptDigest(erasure models.Erasure) (string, error) {
+	raw, err := json.Marshal(erasure)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// erasureTime is truncated to what MongoDB stores, so a receipt read back hashes to
+// the same digest.
+func erasureTime() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
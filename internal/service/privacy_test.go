@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/auth"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func receipt() models.Erasure {
+	started := time.Date(2024, 3, 1, 10, 0, 0, 123000000, time.UTC)
+	completed := started.Add(time.Minute)
+	return models.Erasure{
+		UserID:      "alice",
+		StartedAt:   started,
+		CompletedAt: &completed,
+		Steps: []models.ErasureStep{
+			{Store: models.StoreTransactions, Deleted: 42, CompletedAt: started.Add(time.Second)},
+			{Store: models.StoreAttachments, Deleted: 3, CompletedAt: started.Add(2 * time.Second)},
+		},
+	}
+}
+
+func TestReceiptDigest(t *testing.T) {
+	want, err := ReceiptDigest(receipt())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(want) != 64 {
+		t.Fatalf("digest %q, want 64 hex characters", want)
+	}
+
+	// the digest and the anonymous ID are not part of the receipt.
+	withInternals := receipt()
+	withInternals.Digest, withInternals.AnonymousID = want, "erased-1"
+	if got, _ := ReceiptDigest(withInternals); got != want {
+		t.Errorf("internal fields changed the digest: %s", got)
+	}
+
+	// a holder of the published receipt recomputes the same digest.
+	published, err := json.Marshal(receipt())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded models.Erasure
+	if err := json.Unmarshal(published, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ReceiptDigest(decoded); got != want {
+		t.Errorf("digest after a JSON round trip: %s, want %s", got, want)
+	}
+
+	// and so does the service after reading the stored receipt back.
+	stored, err := bson.Marshal(receipt())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded models.Erasure
+	if err := bson.Unmarshal(stored, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ReceiptDigest(loaded); got != want {
+		t.Errorf("digest after a BSON round trip: %s, want %s", got, want)
+	}
+
+	tampered := receipt()
+	tampered.Steps[0].Deleted = 41
+	if got, _ := ReceiptDigest(tampered); got == want {
+		t.Error("changing a step should change the digest")
+	}
+}
+
+func TestPrivacyRequiresPrivilegedCaller(t *testing.T) {
+	// with authentication disabled nobody is privileged.
+	srv := NewPrivacyService(nil, nil, nil, nil, auth.NewPolicy(false, []string{"admin"}))
+	if _, err := srv.EraseUserData(context.Background(), "alice"); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("EraseUserData: got %v, want Unauthenticated", err)
+	}
+	if err := srv.ExportUserData(context.Background(), "alice", nil); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("ExportUserData: got %v, want Unauthenticated", err)
+	}
+}
+
+// memUserData counts the records kept for one user in every store and
+// serves as each of the repositories an erasure touches.
+type memUserData struct {
+	PrivacyTransactionRepository
+	PrivacyAttachmentRepository
+	PrivacyRepository
+	records map[string]int64
+	erasure *models.Erasure
+}
+
+func (d *memUserData) GetErasure(context.Context, string) (*models.Erasure, error) {
+	if d.erasure == nil {
+		return nil, nil
+	}
+	erasure := *d.erasure
+	return &erasure, nil
+}
+
+func (d *memUserData) SaveErasure(_ context.Context, erasure models.Erasure) error {
+	d.erasure = &erasure
+	return nil
+}
+
+func (d *memUserData) erase(store string) int64 {
+	n := d.records[store]
+	delete(d.records, store)
+	return n
+}
+
+func (d *memUserData) EraseUserRecords(_ context.Context, store, _, _ string) (int64, error) {
+	return d.erase(store), nil
+}
+
+func (d *memUserData) CountUserRecords(_ context.Context, store, _ string) (int64, error) {
+	return d.records[store], nil
+}
+
+func (d *memUserData) DeleteUserTransactions(context.Context, string) (int64, error) {
+	return d.erase(models.StoreTransactions), nil
+}
+
+func (d *memUserData) CountUserTransactions(context.Context, string) (int64, error) {
+	return d.records[models.StoreTransactions], nil
+}
+
+func (d *memUserData) DeleteUserAttachments(context.Context, string) (int64, error) {
+	return d.erase(models.StoreAttachments), nil
+}
+
+func (d *memUserData) CountUserAttachments(context.Context, string) (int64, error) {
+	return d.records[models.StoreAttachments], nil
+}
+
+func TestPurgeUserDataAfterCompletion(t *testing.T) {
+	ctx := context.Background()
+	data := &memUserData{records: map[string]int64{models.StoreTransactions: 2, models.StorePayees: 1}}
+	srv := NewPrivacyService(data, data, data, nil, nil)
+	deleted := func(erasure *models.Erasure, store string) int64 {
+		for _, step := range erasure.Steps {
+			if step.Store == store {
+				return step.Deleted
+			}
+		}
+		return -1
+	}
+
+	first, err := srv.PurgeUserData(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.CompletedAt == nil || deleted(first, models.StoreTransactions) != 2 || deleted(first, models.StorePayees) != 1 {
+		t.Fatalf("first erasure: got %+v", first)
+	}
+
+	if _, err := srv.PurgeUserData(ctx, "alice"); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("erasing again with nothing left: got %v, want FailedPrecondition", err)
+	}
+
+	data.records[models.StoreTransactions] = 3
+	second, err := srv.PurgeUserData(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.CompletedAt == nil || deleted(second, models.StoreTransactions) != 3 || deleted(second, models.StorePayees) != 0 {
+		t.Errorf("erasure of data written after the first: got %+v", second)
+	}
+	if len(data.records) != 0 {
+		t.Errorf("records left: %v", data.records)
+	}
+	if second.Digest == first.Digest {
+		t.Error("the new erasure must have its own receipt")
+	}
+}
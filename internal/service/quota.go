@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TransactionQuota caps the transactions a user creates per UTC day, zero
+// meaning no cap. It is a soft cap: the count and the insert are separate
+// steps, so requests racing each other may together go a few over.
+type TransactionQuota struct {
+	PerDay int
+}
+
+type TransactionCounter interface {
+	CountTransactionsSince(ctx context.Context, userID string, since time.Time) (int64, error)
+}
+
+// checkTransactionQuota fails with ResourceExhausted when adding more
+// transactions today would exceed the user's quota.
+func checkTransactionQuota(ctx context.Context, counter TransactionCounter, quota TransactionQuota, userID string, adding int) error {
+	if quota.PerDay <= 0 {
+		return nil
+	}
+	now := time.Now().UTC()
+	created, err := counter.CountTransactionsSince(ctx, userID, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	if created+int64(adding) > int64(quota.PerDay) {
+		return status.Errorf(codes.ResourceExhausted, "daily quota of %d transactions reached", quota.PerDay)
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/rules"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type RuleRepository interface {
+	AddRule(ctx context.Context, rule models.Rule) (string, error)
+	GetRule(ctx context.Context, ruleID, userID string) (*models.Rule, error)
+	GetRules(ctx context.Context, userID string) ([]models.Rule, error)
+	UpdateRule(ctx context.Context, rule models.Rule) error
+	DeleteRule(ctx context.Context, ruleID, userID string) error
+}
+
+type RuleTransactionRepository interface {
+	GetTXByTimeFrame(ctx context.Context, userID string, dateFrame models.TimeFrame) ([]models.Transaction, error)
+	UpdateTx(ctx context.Context, updates models.Transaction) error
+}
+
+type RuleService struct {
+	RuleRepo        RuleRepository
+	TransactionRepo RuleTransactionRepository
+	Categories      CategoryModelRepository
+	User            UserService
+	Access          AccessPolicy
+	validate        *Validator
+}
+
+func NewRuleService(ruleRepo RuleRepository, txRepo RuleTransactionRepository, categories CategoryModelRepository, user UserService, access AccessPolicy) *RuleService {
+	return &RuleService{RuleRepo: ruleRepo, TransactionRepo: txRepo, Categories: categories,
+		User: user, Access: access, validate: NewValidator()}
+}
+
+const maxRulesPerUser = 200
+
+var errRuleNotFound = status.Error(codes.NotFound, "rule is not found")
+
+func (s *RuleService) CreateRule(ctx context.Context, create models.CreateRule) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "RuleService.CreateRule", trace.WithAttributes(attribute.String("user.id", create.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validateRule(create); err != nil {
+		return "", err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, create.UserID); err != nil {
+		return "", err
+	}
+	existing, err := s.RuleRepo.GetRules(ctx, create.UserID)
+	if err != nil {
+		log.Println(err)
+		return "", err
+	}
+	if len(existing) >= maxRulesPerUser {
+		return "", status.Errorf(codes.FailedPrecondition, "user already has %d rules", maxRulesPerUser)
+	}
+	id, err := s.RuleRepo.AddRule(ctx, models.Rule{
+		UserID:     create.UserID,
+		Name:       create.Name,
+		Priority:   create.Priority,
+		Enabled:    create.Enabled,
+		Conditions: create.Conditions,
+		Actions:    create.Actions,
+		CreatedAt:  time.Now().UTC(),
+	})
+	if err != nil {
+		log.Println(err)
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *RuleService) GetRules(ctx context.Context, userID string) (_ []models.Rule, err error) {
+	ctx, span := tracer.Start(ctx, "RuleService.GetRules", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.UserKey{UserID: userID}); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, userID); err != nil {
+		return nil, err
+	}
+	userRules, err := s.RuleRepo.GetRules(ctx, userID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return userRules, nil
+}
+
+// UpdateRule replaces everything but the owner and creation time.
+func (s *RuleService) UpdateRule(ctx context.Context, update models.CreateRule) (_ *models.Rule, err error) {
+	ctx, span := tracer.Start(ctx, "RuleService.UpdateRule", trace.WithAttributes(attribute.String("user.id", update.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.RuleKey{ID: update.ID, UserID: update.UserID}); err != nil {
+		return nil, err
+	}
+	if err := s.validateRule(update); err != nil {
+		return nil, err
+	}
+	rule, err := s.getRule(ctx, update.ID, update.UserID)
+	if err != nil {
+		return nil, err
+	}
+	rule.Name = update.Name
+	rule.Priority = update.Priority
+	rule.Enabled = update.Enabled
+	rule.Conditions = update.Conditions
+	rule.Actions = update.Actions
+	if err := s.RuleRepo.UpdateRule(ctx, *rule); err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *RuleService) DeleteRule(ctx context.Context, ruleID, userID string) (err error) {
+	ctx, span := tracer.Start(ctx, "RuleService.DeleteRule", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.RuleKey{ID: ruleID, UserID: userID}); err != nil {
+		return err
+	}
+	if _, err := s.getRule(ctx, ruleID, userID); err != nil {
+		return err
+	}
+	if err := s.RuleRepo.DeleteRule(ctx, ruleID, userID); err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+// ReapplyRules runs the current rules over the transactions of a time frame.
+// Categories change only where a rule sets one, the transaction has no splits
+// and, unless OverwriteManual is set, the user did not choose the category;
+// rule tags are added to the existing ones. With DryRun the changes are only
+// reported. A failed update stops the run and is reported in the result next
+// to the changes already written.
+func (s *RuleService) ReapplyRules(ctx context.Context, req models.ReapplyRules, timeframe models.CreateTimeFrame) (_ *models.ReapplyResult, err error) {
+	ctx, span := tracer.Start(ctx, "RuleService.ReapplyRules", trace.WithAttributes(attribute.String("user.id", req.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(req, timeframe); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, req.UserID); err != nil {
+		return nil, err
+	}
+	tf, err := parseTimeFrame(timeframe)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	userRules, err := s.RuleRepo.GetRules(ctx, req.UserID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	engine, err := rules.New(userRules)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	txs, err := s.TransactionRepo.GetTXByTimeFrame(ctx, req.UserID, tf)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	result := &models.ReapplyResult{Scanned: len(txs), Changes: []models.RuleChange{}, Applied: !req.DryRun}
+	for _, tx := range txs {
+		if !tx.IsSpending() {
+			continue
+		}
+		matched := engine.Apply(tx)
+		updated := tx
+		if matched.Category != "" && len(tx.Splits) == 0 && (req.OverwriteManual || !userSetCategory(tx)) {
+			updated.Category = matched.Category
+			updated.CategorySetBy = models.CategoryByRule
+		}
+		updated.Tags = rules.MergeTags(slices.Clone(tx.Tags), matched.Tags)
+		if updated.Category == tx.Category && len(updated.Tags) == len(tx.Tags) {
+			continue
+		}
+		result.Changes = append(result.Changes, models.RuleChange{
+			TxID:        tx.ID,
+			Name:        tx.Name,
+			Date:        tx.Date,
+			OldCategory: tx.Category,
+			NewCategory: updated.Category,
+			OldTags:     tx.Tags,
+			NewTags:     updated.Tags,
+			RuleIDs:     matched.RuleIDs,
+		})
+		if req.DryRun {
+			continue
+		}
+		if err := s.TransactionRepo.UpdateTx(ctx, updated); err != nil {
+			log.Println(err)
+			result.Changes = result.Changes[:result.Updated]
+			result.Applied = false
+			result.Error = fmt.Sprintf("updating transaction %s failed; only the listed changes were applied", tx.ID)
+			break
+		}
+		result.Updated++
+	}
+	if result.Updated > 0 {
+		// recategorized history invalidates the learned suggestions; the
+		// model is retrained on the next SuggestCategory call.
+		if err := s.Categories.DeleteCategoryModel(ctx, req.UserID); err != nil {
+			log.Println(err)
+		}
+	}
+	return result, nil
+}
+
+// userSetCategory reports whether the user chose the transaction's category.
+// Transactions stored before the source was recorded count as user-set
+// unless they still carry the fallback category.
+func userSetCategory(tx models.Transaction) bool {
+	if tx.CategorySetBy == "" {
+		return tx.Category != NoCategory
+	}
+	return tx.CategorySetBy == models.CategoryByUser
+}
+
+func (s *RuleService) validateRule(rule models.CreateRule) error {
+	if err := s.validate.Struct(rule); err != nil {
+		return err
+	}
+	var violations []FieldViolation
+	cond := rule.Conditions
+	if cond.NameContains == "" && cond.NamePattern == "" && cond.MinAmount == nil && cond.MaxAmount == nil &&
+		len(cond.Weekdays) == 0 && cond.AccountID == "" {
+		violations = append(violations, FieldViolation{Field: "conditions", Description: "at least one condition is required"})
+	}
+	if cond.NamePattern != "" {
+		if _, err := rules.Compile(cond.NamePattern); err != nil {
+			violations = append(violations, FieldViolation{Field: "conditions.namePattern", Description: "must be a valid regular expression"})
+		}
+	}
+	if cond.MinAmount != nil && cond.MaxAmount != nil && *cond.MinAmount > *cond.MaxAmount {
+		violations = append(violations, FieldViolation{Field: "conditions.maxAmount", Description: "must not be less than minAmount"})
+	}
+	if rule.Actions.Category == "" && len(rule.Actions.Tags) == 0 {
+		violations = append(violations, FieldViolation{Field: "actions", Description: "must set a category or tags"})
+	}
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+func (s *RuleService) getRule(ctx context.Context, ruleID, userID string) (*models.Rule, error) {
+	if err := authorizeUser(ctx, s.Access, s.User, userID); err != nil {
+		return nil, err
+	}
+	rule, err := s.RuleRepo.GetRule(ctx, ruleID, userID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if rule == nil {
+		return nil, errRuleNotFound
+	}
+	return rule, nil
+}
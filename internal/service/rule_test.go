@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+type memRuleRepo struct {
+	RuleRepository
+	rules []models.Rule
+}
+
+func (r *memRuleRepo) GetRules(context.Context, string) ([]models.Rule, error) {
+	return r.rules, nil
+}
+
+// failingTxRepo fails to update one transaction.
+type failingTxRepo struct {
+	*memTxRepo
+	failID string
+}
+
+func (r failingTxRepo) UpdateTx(ctx context.Context, updates models.Transaction) error {
+	if updates.ID == r.failID {
+		return errors.New("write conflict")
+	}
+	return r.memTxRepo.UpdateTx(ctx, updates)
+}
+
+func TestReapplyRules(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	frame := models.CreateTimeFrame{StartDate: "2024-03-01", EndDate: "2024-03-31"}
+	newFixture := func() (*memTxRepo, *memCategoryModels, *RuleService) {
+		ledger := &memTxRepo{txs: []models.Transaction{
+			{ID: "tx-other", UserID: "alice", Name: "Coffee", Cost: 3, Date: day, Category: NoCategory},
+			{ID: "tx-manual", UserID: "alice", Name: "Coffee with Bob", Cost: 6, Date: day, Category: "gifts", CategorySetBy: models.CategoryByUser},
+			{ID: "tx-legacy", UserID: "alice", Name: "Coffee beans", Cost: 12, Date: day, Category: "groceries"},
+			{ID: "tx-rule", UserID: "alice", Name: "Coffee to go", Cost: 2, Date: day, Category: "snacks", CategorySetBy: models.CategoryByRule},
+			{ID: "tx-transfer", UserID: "alice", Name: "Coffee fund", Cost: 50, Date: day, Kind: models.KindTransferOut},
+		}}
+		categories := &memCategoryModels{}
+		rules := &memRuleRepo{rules: []models.Rule{{ID: "r1", Enabled: true,
+			Conditions: models.RuleConditions{NameContains: "coffee"},
+			Actions:    models.RuleActions{Category: "cafe", Tags: []string{"coffee"}}}}}
+		return ledger, categories, NewRuleService(rules, ledger, categories, knownUsers{"alice"}, allowAll{})
+	}
+	categoriesOf := func(ledger *memTxRepo) map[string]string {
+		got := map[string]string{}
+		for _, tx := range ledger.txs {
+			got[tx.ID] = tx.Category
+		}
+		return got
+	}
+
+	t.Run("keeps categories the user chose", func(t *testing.T) {
+		ledger, categories, srv := newFixture()
+		result, err := srv.ReapplyRules(ctx, models.ReapplyRules{UserID: "alice"}, frame)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]string{"tx-other": "cafe", "tx-manual": "gifts", "tx-legacy": "groceries", "tx-rule": "cafe", "tx-transfer": ""}
+		if got := categoriesOf(ledger); !reflect.DeepEqual(got, want) {
+			t.Errorf("categories: got %v, want %v", got, want)
+		}
+		if !result.Applied || result.Updated != 4 || len(result.Changes) != 4 || result.Error != "" {
+			t.Errorf("result %+v, want 4 applied changes", result)
+		}
+		if ledger.txs[1].Tags[0] != "coffee" || ledger.txs[0].CategorySetBy != models.CategoryByRule {
+			t.Errorf("manual transaction should still get the tags: %+v", ledger.txs[:2])
+		}
+		if !categories.deleted {
+			t.Error("the learned category model should be dropped")
+		}
+	})
+
+	t.Run("overwrite manual", func(t *testing.T) {
+		ledger, _, srv := newFixture()
+		if _, err := srv.ReapplyRules(ctx, models.ReapplyRules{UserID: "alice", OverwriteManual: true}, frame); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]string{"tx-other": "cafe", "tx-manual": "cafe", "tx-legacy": "cafe", "tx-rule": "cafe", "tx-transfer": ""}
+		if got := categoriesOf(ledger); !reflect.DeepEqual(got, want) {
+			t.Errorf("categories: got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		ledger, categories, srv := newFixture()
+		result, err := srv.ReapplyRules(ctx, models.ReapplyRules{UserID: "alice", DryRun: true}, frame)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Applied || result.Updated != 0 || len(result.Changes) != 4 {
+			t.Errorf("result %+v, want 4 unapplied changes", result)
+		}
+		if ledger.txs[0].Category != NoCategory || categories.deleted {
+			t.Error("a dry run must not write anything")
+		}
+	})
+
+	t.Run("failed update returns the changes already written", func(t *testing.T) {
+		ledger, categories, srv := newFixture()
+		srv.TransactionRepo = failingTxRepo{memTxRepo: ledger, failID: "tx-legacy"}
+		result, err := srv.ReapplyRules(ctx, models.ReapplyRules{UserID: "alice", OverwriteManual: true}, frame)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Applied || result.Updated != 2 || result.Error == "" {
+			t.Fatalf("result %+v, want 2 updates and an error", result)
+		}
+		var ids []string
+		for _, c := range result.Changes {
+			ids = append(ids, c.TxID)
+		}
+		if want := []string{"tx-other", "tx-manual"}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("changes %v, want %v", ids, want)
+		}
+		if !categories.deleted {
+			t.Error("the learned category model should be dropped after partial updates")
+		}
+	})
+}
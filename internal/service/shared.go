@@ -0,0 +1,448 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type SharedExpenseRepository interface {
+	AddGroup(ctx context.Context, group models.Group) (string, error)
+	GetGroup(ctx context.Context, groupID string) (*models.Group, error)
+	AddGroupMember(ctx context.Context, groupID, memberID string) error
+	AddSharedExpense(ctx context.Context, expense models.SharedExpense) (string, error)
+	GetGroupExpenses(ctx context.Context, groupID string) ([]models.SharedExpense, error)
+	SettleGroup(ctx context.Context, group models.Group, userID string, settlements []models.Settlement) ([]models.Settlement, bool, error)
+	GetGroupSettlements(ctx context.Context, groupID string, settlementIDs ...string) ([]models.Settlement, error)
+}
+
+type SharedExpenseService struct {
+	Repo     SharedExpenseRepository
+	User     UserService
+	Access   AccessPolicy
+	validate *Validator
+}
+
+func NewSharedExpenseService(repo SharedExpenseRepository, user UserService, access AccessPolicy) *SharedExpenseService {
+	return &SharedExpenseService{Repo: repo, User: user, Access: access, validate: NewValidator()}
+}
+
+const maxGroupMembers = 50
+
+var errGroupNotFound = status.Error(codes.NotFound, "group is not found")
+
+func (s *SharedExpenseService) CreateGroup(ctx context.Context, create models.CreateGroup) (_ *models.Group, err error) {
+	ctx, span := tracer.Start(ctx, "SharedExpenseService.CreateGroup", trace.WithAttributes(attribute.String("user.id", create.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(create); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, create.UserID); err != nil {
+		return nil, err
+	}
+	members := []string{create.UserID}
+	for i, memberID := range create.MemberIDs {
+		if memberID == create.UserID {
+			continue
+		}
+		if err := s.checkUserExists(ctx, fmt.Sprintf("memberIds[%d]", i), memberID); err != nil {
+			return nil, err
+		}
+		members = append(members, memberID)
+	}
+	group := models.Group{
+		Name:      create.Name,
+		OwnerID:   create.UserID,
+		Members:   members,
+		CreatedAt: time.Now().UTC(),
+	}
+	group.ID, err = s.Repo.AddGroup(ctx, group)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (s *SharedExpenseService) GetGroup(ctx context.Context, groupID, userID string) (_ *models.Group, err error) {
+	ctx, span := tracer.Start(ctx, "SharedExpenseService.GetGroup", trace.WithAttributes(attribute.String("group.id", groupID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.GroupKey{GroupID: groupID, UserID: userID}); err != nil {
+		return nil, err
+	}
+	if err := s.Access.CheckAccess(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.memberGroup(ctx, groupID, userID)
+}
+
+// AddGroupMember lets the group's owner add another user.
+func (s *SharedExpenseService) AddGroupMember(ctx context.Context, add models.AddGroupMember) (_ *models.Group, err error) {
+	ctx, span := tracer.Start(ctx, "SharedExpenseService.AddGroupMember", trace.WithAttributes(attribute.String("group.id", add.GroupID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(add); err != nil {
+		return nil, err
+	}
+	if err := s.Access.CheckAccess(ctx, add.UserID); err != nil {
+		return nil, err
+	}
+	group, err := s.memberGroup(ctx, add.GroupID, add.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if group.OwnerID != add.UserID {
+		return nil, status.Error(codes.PermissionDenied, "only the group's owner can add members")
+	}
+	if group.IsMember(add.MemberID) {
+		return group, nil
+	}
+	if len(group.Members) >= maxGroupMembers {
+		return nil, status.Errorf(codes.FailedPrecondition, "a group has at most %d members", maxGroupMembers)
+	}
+	if err := s.checkUserExists(ctx, "memberId", add.MemberID); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.AddGroupMember(ctx, add.GroupID, add.MemberID); err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	group.Members = append(group.Members, add.MemberID)
+	return group, nil
+}
+
+// AddSharedExpense records an expense logged by a member. The payer and
+// every participant must be members of the group too.
+func (s *SharedExpenseService) AddSharedExpense(ctx context.Context, expense models.CreateSharedExpense) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "SharedExpenseService.AddSharedExpense", trace.WithAttributes(attribute.String("group.id", expense.GroupID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(expense); err != nil {
+		return "", err
+	}
+	if err := s.Access.CheckAccess(ctx, expense.UserID); err != nil {
+		return "", err
+	}
+	group, err := s.memberGroup(ctx, expense.GroupID, expense.UserID)
+	if err != nil {
+		return "", err
+	}
+	var violations []FieldViolation
+	if !group.IsMember(expense.PayerID) {
+		violations = append(violations, FieldViolation{Field: "payerId", Description: "is not a member of the group"})
+	}
+	for i, p := range expense.Participants {
+		if !group.IsMember(p.UserID) {
+			violations = append(violations, FieldViolation{Field: fieldIndex("participants", i, "userId"), Description: "is not a member of the group"})
+		}
+	}
+	if len(violations) > 0 {
+		return "", &ValidationError{Violations: violations}
+	}
+	shares, err := computeShares(expense.Total, expense.SplitType, expense.Participants)
+	if err != nil {
+		return "", err
+	}
+	date := time.Now().UTC()
+	if expense.Date != nil {
+		date, err = time.Parse(DateTimeformat, *expense.Date)
+		if err != nil {
+			return "", err
+		}
+	}
+	id, err := s.Repo.AddSharedExpense(ctx, models.SharedExpense{
+		GroupID:   expense.GroupID,
+		PayerID:   expense.PayerID,
+		Name:      expense.Name,
+		Total:     expense.Total,
+		SplitType: expense.SplitType,
+		Shares:    shares,
+		Date:      date,
+	})
+	if err != nil {
+		log.Println(err)
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *SharedExpenseService) GetSharedExpenses(ctx context.Context, groupID, userID string) (_ []models.SharedExpense, err error) {
+	ctx, span := tracer.Start(ctx, "SharedExpenseService.GetSharedExpenses", trace.WithAttributes(attribute.String("group.id", groupID)))
+	defer func() { endSpan(span, err) }()
+	_, expenses, _, err := s.loadGroup(ctx, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return expenses, nil
+}
+
+func (s *SharedExpenseService) GetBalances(ctx context.Context, groupID, userID string) (_ []models.Balance, _ []models.Debt, err error) {
+	ctx, span := tracer.Start(ctx, "SharedExpenseService.GetBalances", trace.WithAttributes(attribute.String("group.id", groupID)))
+	defer func() { endSpan(span, err) }()
+	_, expenses, settlements, err := s.loadGroup(ctx, groupID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	nets := netBalances(expenses, settlements)
+	return toBalances(nets), minimizeTransfers(nets), nil
+}
+
+// SettleUp records, as settlement transactions, the transfers that clear the
+// caller's debts and claims in the group. It is idempotent: a call racing
+// or repeating one for the same group version returns the settlements that
+// call recorded.
+func (s *SharedExpenseService) SettleUp(ctx context.Context, groupID, userID string) (_ []models.Settlement, err error) {
+	ctx, span := tracer.Start(ctx, "SharedExpenseService.SettleUp", trace.WithAttributes(attribute.String("group.id", groupID)))
+	defer func() { endSpan(span, err) }()
+	group, expenses, settlements, err := s.loadGroup(ctx, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	var records []models.Settlement
+	for _, d := range minimizeTransfers(netBalances(expenses, settlements)) {
+		if d.FromUserID != userID && d.ToUserID != userID {
+			continue
+		}
+		records = append(records, models.Settlement{
+			GroupID:    groupID,
+			FromUserID: d.FromUserID,
+			ToUserID:   d.ToUserID,
+			Amount:     d.Amount,
+			Date:       now,
+		})
+	}
+	if len(records) == 0 {
+		return []models.Settlement{}, nil
+	}
+	settled, applied, err := s.Repo.SettleGroup(ctx, *group, userID, records)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if applied {
+		return settled, nil
+	}
+	current, err := s.Repo.GetGroup(ctx, groupID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	last := current.LastSettlement
+	if last == nil || last.Version != group.Version || last.UserID != userID {
+		return nil, status.Error(codes.Aborted, "group balances changed, retry the settle-up")
+	}
+	settled, err = s.Repo.GetGroupSettlements(ctx, groupID, last.IDs...)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return settled, nil
+}
+
+func (s *SharedExpenseService) loadGroup(ctx context.Context, groupID, userID string) (*models.Group, []models.SharedExpense, []models.Settlement, error) {
+	if err := s.validate.Struct(models.GroupKey{GroupID: groupID, UserID: userID}); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := s.Access.CheckAccess(ctx, userID); err != nil {
+		return nil, nil, nil, err
+	}
+	group, err := s.memberGroup(ctx, groupID, userID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	expenses, err := s.Repo.GetGroupExpenses(ctx, groupID)
+	if err != nil {
+		log.Println(err)
+		return nil, nil, nil, err
+	}
+	settlements, err := s.Repo.GetGroupSettlements(ctx, groupID)
+	if err != nil {
+		log.Println(err)
+		return nil, nil, nil, err
+	}
+	return group, expenses, settlements, nil
+}
+
+// memberGroup returns the group if the user belongs to it; to anyone else
+// it does not exist.
+func (s *SharedExpenseService) memberGroup(ctx context.Context, groupID, userID string) (*models.Group, error) {
+	group, err := s.Repo.GetGroup(ctx, groupID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if group == nil || !group.IsMember(userID) {
+		return nil, errGroupNotFound
+	}
+	return group, nil
+}
+
+func (s *SharedExpenseService) checkUserExists(ctx context.Context, field, userID string) error {
+	id, _, err := s.User.GetUser(ctx, userID)
+	if err != nil && status.Code(err) != codes.NotFound {
+		log.Println(err)
+		return err
+	}
+	if id == "" {
+		return &ValidationError{Violations: []FieldViolation{{Field: field, Description: "user not found"}}}
+	}
+	return nil
+}
+
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+func fromCents(cents int64) float64 {
+	return float64(cents) / 100
+}
+
+func computeShares(total float64, splitType string, participants []models.CreateParticipant) ([]models.Participant, error) {
+	totalCents := toCents(total)
+	cents := make([]int64, len(participants))
+	switch splitType {
+	case models.SplitEqual:
+		base := totalCents / int64(len(participants))
+		for i := range cents {
+			cents[i] = base
+		}
+		distributeRemainder(cents, totalCents)
+	case models.SplitPercentage:
+		var percent float64
+		for i, p := range participants {
+			percent += p.Value
+			cents[i] = int64(math.Floor(float64(totalCents) * p.Value / 100))
+		}
+		if math.Abs(percent-100) > 0.01 {
+			return nil, &ValidationError{Violations: []FieldViolation{{Field: "participants", Description: "percentages must add up to 100"}}}
+		}
+		distributeRemainder(cents, totalCents)
+	case models.SplitExact:
+		var sum int64
+		for i, p := range participants {
+			cents[i] = toCents(p.Value)
+			sum += cents[i]
+		}
+		if sum != totalCents {
+			return nil, &ValidationError{Violations: []FieldViolation{{Field: "participants", Description: "amounts must add up to total"}}}
+		}
+	}
+	shares := make([]models.Participant, len(participants))
+	for i, p := range participants {
+		shares[i] = models.Participant{UserID: p.UserID, Amount: fromCents(cents[i])}
+	}
+	return shares, nil
+}
+
+// distributeRemainder makes the shares add up to total. It hands out the
+// cents lost to rounding one at a time, starting with the first participant,
+// and takes any excess, which percentages summing to just over 100 leave,
+// from the largest share.
+func distributeRemainder(cents []int64, total int64) {
+	var sum int64
+	largest := 0
+	for i, c := range cents {
+		sum += c
+		if c > cents[largest] {
+			largest = i
+		}
+	}
+	if sum > total {
+		cents[largest] -= sum - total
+		return
+	}
+	for i := 0; sum < total; i = (i + 1) % len(cents) {
+		cents[i]++
+		sum++
+	}
+}
+
+func netBalances(expenses []models.SharedExpense, settlements []models.Settlement) map[string]int64 {
+	nets := make(map[string]int64)
+	for _, e := range expenses {
+		nets[e.PayerID] += toCents(e.Total)
+		for _, share := range e.Shares {
+			nets[share.UserID] -= toCents(share.Amount)
+		}
+	}
+	for _, st := range settlements {
+		nets[st.FromUserID] += toCents(st.Amount)
+		nets[st.ToUserID] -= toCents(st.Amount)
+	}
+	return nets
+}
+
+func toBalances(nets map[string]int64) []models.Balance {
+	balances := make([]models.Balance, 0, len(nets))
+	for userID, net := range nets {
+		balances = append(balances, models.Balance{UserID: userID, Net: fromCents(net)})
+	}
+	sort.Slice(balances, func(i, j int) bool { return balances[i].UserID < balances[j].UserID })
+	return balances
+}
+
+type party struct {
+	userID string
+	cents  int64
+}
+
+// minimizeTransfers settles debtors against creditors. Exactly matching
+// amounts are paired first, the rest is settled greedily from the largest
+// balances, which needs at most n-1 transfers.
+func minimizeTransfers(nets map[string]int64) []models.Debt {
+	var creditors, debtors []party
+	for userID, net := range nets {
+		switch {
+		case net > 0:
+			creditors = append(creditors, party{userID, net})
+		case net < 0:
+			debtors = append(debtors, party{userID, -net})
+		}
+	}
+	byAmount := func(p []party) {
+		sort.Slice(p, func(i, j int) bool {
+			if p[i].cents != p[j].cents {
+				return p[i].cents > p[j].cents
+			}
+			return p[i].userID < p[j].userID
+		})
+	}
+	byAmount(creditors)
+	byAmount(debtors)
+
+	var debts []models.Debt
+	transfer := func(d, c *party, cents int64) {
+		debts = append(debts, models.Debt{FromUserID: d.userID, ToUserID: c.userID, Amount: fromCents(cents)})
+		d.cents -= cents
+		c.cents -= cents
+	}
+	for i := range debtors {
+		for j := range creditors {
+			if debtors[i].cents > 0 && debtors[i].cents == creditors[j].cents {
+				transfer(&debtors[i], &creditors[j], debtors[i].cents)
+				break
+			}
+		}
+	}
+	for {
+		byAmount(creditors)
+		byAmount(debtors)
+		if len(debtors) == 0 || len(creditors) == 0 || debtors[0].cents == 0 || creditors[0].cents == 0 {
+			break
+		}
+		amount := debtors[0].cents
+		if creditors[0].cents < amount {
+			amount = creditors[0].cents
+		}
+		transfer(&debtors[0], &creditors[0], amount)
+	}
+	return debts
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const groupID = "6650a1f1c2a4b5e6f7a8b9c0"
+
+// fakeSharedRepo keeps one group in memory and applies settle-ups the way
+// the Mongo repository does: only if the group version is unchanged.
+type fakeSharedRepo struct {
+	group       *models.Group
+	expenses    []models.SharedExpense
+	settlements []models.Settlement
+	// beforeSettle runs inside SettleGroup, before the version check.
+	beforeSettle func()
+}
+
+func (r *fakeSharedRepo) AddGroup(_ context.Context, group models.Group) (string, error) {
+	group.ID = groupID
+	r.group = &group
+	return group.ID, nil
+}
+
+func (r *fakeSharedRepo) GetGroup(_ context.Context, id string) (*models.Group, error) {
+	if r.group == nil || r.group.ID != id {
+		return nil, nil
+	}
+	group := *r.group
+	group.Members = slices.Clone(r.group.Members)
+	return &group, nil
+}
+
+func (r *fakeSharedRepo) AddGroupMember(_ context.Context, _ string, memberID string) error {
+	r.group.Members = append(r.group.Members, memberID)
+	return nil
+}
+
+func (r *fakeSharedRepo) AddSharedExpense(_ context.Context, expense models.SharedExpense) (string, error) {
+	expense.ID = fmt.Sprintf("expense-%d", len(r.expenses)+1)
+	r.expenses = append(r.expenses, expense)
+	r.group.Version++
+	return expense.ID, nil
+}
+
+func (r *fakeSharedRepo) GetGroupExpenses(context.Context, string) ([]models.SharedExpense, error) {
+	return r.expenses, nil
+}
+
+func (r *fakeSharedRepo) SettleGroup(_ context.Context, group models.Group, userID string, settlements []models.Settlement) ([]models.Settlement, bool, error) {
+	if hook := r.beforeSettle; hook != nil {
+		r.beforeSettle = nil
+		hook()
+	}
+	if r.group.Version != group.Version {
+		return nil, false, nil
+	}
+	last := &models.GroupSettlement{Version: group.Version, UserID: userID}
+	for i := range settlements {
+		settlements[i].ID = fmt.Sprintf("settlement-%d", len(r.settlements)+1)
+		last.IDs = append(last.IDs, settlements[i].ID)
+		r.settlements = append(r.settlements, settlements[i])
+	}
+	r.group.Version++
+	r.group.LastSettlement = last
+	return settlements, true, nil
+}
+
+func (r *fakeSharedRepo) GetGroupSettlements(_ context.Context, _ string, ids ...string) ([]models.Settlement, error) {
+	if len(ids) == 0 {
+		return r.settlements, nil
+	}
+	var found []models.Settlement
+	for _, st := range r.settlements {
+		if slices.Contains(ids, st.ID) {
+			found = append(found, st)
+		}
+	}
+	return found, nil
+}
+
+func newSharedFixture(t *testing.T, members ...string) (*SharedExpenseService, *fakeSharedRepo) {
+	t.Helper()
+	repo := &fakeSharedRepo{}
+	srv := NewSharedExpenseService(repo, knownUsers{"alice", "bob", "carol", "dave"}, allowAll{})
+	if _, err := srv.CreateGroup(context.Background(), models.CreateGroup{UserID: "alice", Name: "Trip", MemberIDs: members}); err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	return srv, repo
+}
+
+func TestComputeShares(t *testing.T) {
+	tests := []struct {
+		name         string
+		total        float64
+		splitType    string
+		participants []models.CreateParticipant
+		want         []float64
+		wantErr      bool
+	}{
+		{
+			name:         "equal split hands out remainder cents",
+			total:        10,
+			splitType:    models.SplitEqual,
+			participants: []models.CreateParticipant{{UserID: "a"}, {UserID: "b"}, {UserID: "c"}},
+			want:         []float64{3.34, 3.33, 3.33},
+		},
+		{
+			name:         "percentage",
+			total:        80,
+			splitType:    models.SplitPercentage,
+			participants: []models.CreateParticipant{{UserID: "a", Value: 25}, {UserID: "b", Value: 75}},
+			want:         []float64{20, 60},
+		},
+		{
+			name:         "percentages just over 100 do not exceed the total",
+			total:        1000,
+			splitType:    models.SplitPercentage,
+			participants: []models.CreateParticipant{{UserID: "a", Value: 50.004}, {UserID: "b", Value: 50.004}},
+			want:         []float64{499.96, 500.04},
+		},
+		{
+			name:         "percentages must add up",
+			total:        80,
+			splitType:    models.SplitPercentage,
+			participants: []models.CreateParticipant{{UserID: "a", Value: 25}, {UserID: "b", Value: 70}},
+			wantErr:      true,
+		},
+		{
+			name:         "exact",
+			total:        0.3,
+			splitType:    models.SplitExact,
+			participants: []models.CreateParticipant{{UserID: "a", Value: 0.1}, {UserID: "b", Value: 0.2}},
+			want:         []float64{0.1, 0.2},
+		},
+		{
+			name:         "exact amounts must add up",
+			total:        10,
+			splitType:    models.SplitExact,
+			participants: []models.CreateParticipant{{UserID: "a", Value: 4}, {UserID: "b", Value: 5}},
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares, err := computeShares(tt.total, tt.splitType, tt.participants)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := make([]float64, len(shares))
+			for i, s := range shares {
+				got[i] = s.Amount
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMinimizeTransfers(t *testing.T) {
+	nets := map[string]int64{"alice": 3000, "bob": -1000, "carol": -2000, "dave": 0}
+	got := minimizeTransfers(nets)
+	want := []models.Debt{
+		{FromUserID: "carol", ToUserID: "alice", Amount: 20},
+		{FromUserID: "bob", ToUserID: "alice", Amount: 10},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestNetBalancesSettlements(t *testing.T) {
+	expenses := []models.SharedExpense{{
+		PayerID: "alice",
+		Total:   30,
+		Shares:  []models.Participant{{UserID: "alice", Amount: 10}, {UserID: "bob", Amount: 10}, {UserID: "carol", Amount: 10}},
+	}}
+	settlements := []models.Settlement{{FromUserID: "bob", ToUserID: "alice", Amount: 10}}
+	got := netBalances(expenses, settlements)
+	want := map[string]int64{"alice": 1000, "bob": 0, "carol": -1000}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestGroupMembership(t *testing.T) {
+	srv, _ := newSharedFixture(t, "bob")
+	ctx := context.Background()
+	expense := func(userID, payerID string, participants ...string) models.CreateSharedExpense {
+		e := models.CreateSharedExpense{GroupID: groupID, UserID: userID, PayerID: payerID, Name: "Dinner", Total: 30, SplitType: models.SplitEqual}
+		for _, p := range participants {
+			e.Participants = append(e.Participants, models.CreateParticipant{UserID: p})
+		}
+		return e
+	}
+
+	if _, err := srv.AddSharedExpense(ctx, expense("carol", "alice", "alice", "bob")); status.Code(err) != codes.NotFound {
+		t.Errorf("non-member adding an expense: got %v, want NotFound", err)
+	}
+	_, err := srv.AddSharedExpense(ctx, expense("bob", "carol", "alice", "dave"))
+	want := map[string]string{"payerId": "is not a member of the group", "participants[1].userId": "is not a member of the group"}
+	if got := violations(t, err); !reflect.DeepEqual(got, want) {
+		t.Errorf("non-member payer and participant: got %v, want %v", got, want)
+	}
+	if _, _, err := srv.GetBalances(ctx, groupID, "carol"); status.Code(err) != codes.NotFound {
+		t.Errorf("non-member reading balances: got %v, want NotFound", err)
+	}
+	if _, err := srv.AddGroupMember(ctx, models.AddGroupMember{GroupID: groupID, UserID: "bob", MemberID: "carol"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("member adding a member: got %v, want PermissionDenied", err)
+	}
+	_, err = srv.AddGroupMember(ctx, models.AddGroupMember{GroupID: groupID, UserID: "alice", MemberID: "user-404"})
+	if got := violations(t, err); got["memberId"] != "user not found" {
+		t.Errorf("adding an unknown user: got %v", got)
+	}
+	if _, err := srv.AddGroupMember(ctx, models.AddGroupMember{GroupID: groupID, UserID: "alice", MemberID: "carol"}); err != nil {
+		t.Fatalf("AddGroupMember: %v", err)
+	}
+	if _, err := srv.AddSharedExpense(ctx, expense("carol", "alice", "alice", "bob", "carol")); err != nil {
+		t.Errorf("new member adding an expense: %v", err)
+	}
+}
+
+func TestSettleUp(t *testing.T) {
+	ctx := context.Background()
+	dinner := models.CreateSharedExpense{
+		GroupID: groupID, UserID: "alice", PayerID: "alice", Name: "Dinner", Total: 30, SplitType: models.SplitEqual,
+		Participants: []models.CreateParticipant{{UserID: "alice"}, {UserID: "bob"}, {UserID: "carol"}},
+	}
+
+	t.Run("settles only the caller's debts", func(t *testing.T) {
+		srv, _ := newSharedFixture(t, "bob", "carol")
+		if _, err := srv.AddSharedExpense(ctx, dinner); err != nil {
+			t.Fatal(err)
+		}
+		settled, err := srv.SettleUp(ctx, groupID, "bob")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(settled) != 1 || settled[0].FromUserID != "bob" || settled[0].ToUserID != "alice" || settled[0].Amount != 10 {
+			t.Fatalf("got %+v, want bob paying alice 10", settled)
+		}
+		_, debts, err := srv.GetBalances(ctx, groupID, "alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []models.Debt{{FromUserID: "carol", ToUserID: "alice", Amount: 10}}; !reflect.DeepEqual(debts, want) {
+			t.Errorf("remaining debts: got %+v, want %+v", debts, want)
+		}
+		again, err := srv.SettleUp(ctx, groupID, "bob")
+		if err != nil || len(again) != 0 {
+			t.Errorf("second settle-up: got %+v, %v, want nothing to settle", again, err)
+		}
+	})
+
+	t.Run("racing retry returns the recorded settlements", func(t *testing.T) {
+		srv, repo := newSharedFixture(t, "bob", "carol")
+		if _, err := srv.AddSharedExpense(ctx, dinner); err != nil {
+			t.Fatal(err)
+		}
+		var first []models.Settlement
+		repo.beforeSettle = func() {
+			var err error
+			if first, err = srv.SettleUp(ctx, groupID, "alice"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		second, err := srv.SettleUp(ctx, groupID, "alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(repo.settlements) != 2 {
+			t.Errorf("recorded %d settlements, want 2", len(repo.settlements))
+		}
+		if !reflect.DeepEqual(first, second) {
+			t.Errorf("retry got %+v, want %+v", second, first)
+		}
+	})
+
+	t.Run("changed balances abort", func(t *testing.T) {
+		srv, repo := newSharedFixture(t, "bob", "carol")
+		if _, err := srv.AddSharedExpense(ctx, dinner); err != nil {
+			t.Fatal(err)
+		}
+		repo.beforeSettle = func() {
+			if _, err := srv.AddSharedExpense(ctx, dinner); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := srv.SettleUp(ctx, groupID, "alice"); status.Code(err) != codes.Aborted {
+			t.Errorf("got %v, want Aborted", err)
+		}
+		if len(repo.settlements) != 0 {
+			t.Errorf("recorded %d settlements, want none", len(repo.settlements))
+		}
+	})
+}
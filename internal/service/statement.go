@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/report"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type StatementTransactionRepository interface {
+	GetTXByTimeFrame(ctx context.Context, userID string, dateFrame models.TimeFrame) ([]models.Transaction, error)
+	GetCategoryTotals(ctx context.Context, userID string, dateFrame models.TimeFrame) ([]models.CategoryTotal, error)
+}
+
+type StatementService struct {
+	TransactionRepo StatementTransactionRepository
+	User            UserService
+	Access          AccessPolicy
+	validate        *Validator
+}
+
+func NewStatementService(txRepo StatementTransactionRepository, user UserService, access AccessPolicy) *StatementService {
+	return &StatementService{TransactionRepo: txRepo, User: user, Access: access, validate: NewValidator()}
+}
+
+const (
+	monthFormat         = "2006-01"
+	topExpenses         = 10
+	defaultStatementFmt = models.FormatPDF
+)
+
+func (s *StatementService) GenerateStatement(ctx context.Context, req models.GenerateStatement) (_ *models.Document, err error) {
+	ctx, span := tracer.Start(ctx, "StatementService.GenerateStatement", trace.WithAttributes(attribute.String("user.id", req.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(req); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, req.UserID); err != nil {
+		return nil, err
+	}
+	month := PreviousMonth(time.Now().UTC())
+	if req.Month != "" {
+		var err error
+		month, err = time.Parse(monthFormat, req.Month)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if req.Format == "" {
+		req.Format = defaultStatementFmt
+	}
+	doc, err := s.RenderStatement(ctx, req.UserID, month, req.Format)
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// RenderStatement builds and renders the statement for the month starting at
+// month without checking access; the scheduler uses it directly.
+func (s *StatementService) RenderStatement(ctx context.Context, userID string, month time.Time, format string) (models.Document, error) {
+	statement, err := s.buildStatement(ctx, userID, month)
+	if err != nil {
+		return models.Document{}, err
+	}
+	doc, err := report.Render(*statement, format)
+	if err != nil {
+		log.Println(err)
+		return models.Document{}, err
+	}
+	return doc, nil
+}
+
+func (s *StatementService) buildStatement(ctx context.Context, userID string, month time.Time) (*models.Statement, error) {
+	current := monthFrame(month)
+	previous := monthFrame(month.AddDate(0, -1, 0))
+	totals, err := s.TransactionRepo.GetCategoryTotals(ctx, userID, current)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	previousTotals, err := s.TransactionRepo.GetCategoryTotals(ctx, userID, previous)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	txs, err := s.TransactionRepo.GetTXByTimeFrame(ctx, userID, current)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	_, name, err := s.User.GetUser(ctx, userID)
+	if err != nil {
+		log.Println(err)
+	}
+
+	statement := &models.Statement{
+		UserID:       userID,
+		UserName:     name,
+		StartDate:    current.StartDate,
+		EndDate:      current.EndDate,
+		Transactions: txs,
+		GeneratedAt:  time.Now().UTC(),
+	}
+	byCategory := map[string]*models.CategoryComparison{}
+	for _, t := range totals {
+		byCategory[t.Category] = &models.CategoryComparison{Category: t.Category, Total: t.Total, Count: t.Count}
+		statement.Total += t.Total
+	}
+	for _, t := range previousTotals {
+		c, ok := byCategory[t.Category]
+		if !ok {
+			c = &models.CategoryComparison{Category: t.Category}
+			byCategory[t.Category] = c
+		}
+		c.Previous = t.Total
+		statement.PreviousTotal += t.Total
+	}
+	for _, c := range byCategory {
+		statement.Categories = append(statement.Categories, *c)
+	}
+	sort.Slice(statement.Categories, func(i, j int) bool {
+		a, b := statement.Categories[i], statement.Categories[j]
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		if a.Previous != b.Previous {
+			return a.Previous > b.Previous
+		}
+		return a.Category < b.Category
+	})
+	for _, tx := range txs {
+		if tx.IsSpending() {
+			statement.TopExpenses = append(statement.TopExpenses, tx)
+		}
+	}
+	sort.SliceStable(statement.TopExpenses, func(i, j int) bool {
+		return statement.TopExpenses[i].Cost > statement.TopExpenses[j].Cost
+	})
+	if len(statement.TopExpenses) > topExpenses {
+		statement.TopExpenses = statement.TopExpenses[:topExpenses]
+	}
+	return statement, nil
+}
+
+// PreviousMonth returns the first instant of the calendar month before t.
+func PreviousMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month()-1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func monthFrame(month time.Time) models.TimeFrame {
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	tf, _ := parseTimeFrame(models.CreateTimeFrame{
+		StartDate: start.Format(Dateformat),
+		EndDate:   start.AddDate(0, 1, -1).Format(Dateformat),
+	})
+	return tf
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/suggest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type CategoryModelRepository interface {
+	GetCategoryModel(ctx context.Context, userID string) (*models.CategoryModel, error)
+	InsertCategoryModel(ctx context.Context, model models.CategoryModel) (bool, error)
+	IncrementCategoryModel(ctx context.Context, userID string, inc map[string]float64) error
+	DeleteCategoryModel(ctx context.Context, userID string) error
+}
+
+const defaultSuggestions = 3
+
+func (s *TransactionService) SuggestCategory(ctx context.Context, req models.SuggestCategory) (_ []models.CategorySuggestion, err error) {
+	ctx, span := tracer.Start(ctx, "TransactionService.SuggestCategory", trace.WithAttributes(attribute.String("user.id", req.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(req); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, req.UserID); err != nil {
+		return nil, err
+	}
+	model, err := s.Categories.GetCategoryModel(ctx, req.UserID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if model == nil {
+		txs, err := s.TransactionRepo.GetAllTransactions(ctx, req.UserID)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		model = suggest.Train(req.UserID, txs)
+		model.TrainedAt = time.Now().UTC()
+		inserted, err := s.Categories.InsertCategoryModel(ctx, *model)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		if !inserted {
+			// a concurrent call stored its model first and transactions
+			// may have been learned into it since; rank with that one.
+			if model, err = s.Categories.GetCategoryModel(ctx, req.UserID); err != nil {
+				log.Println(err)
+				return nil, err
+			}
+		}
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultSuggestions
+	}
+	return suggest.Rank(model, req.Name, req.Cost, req.Limit), nil
+}
+
+// learn moves a transaction's counts in the user's category model from its
+// old version to the new one; either may be nil. Failures only cost
+// suggestion quality, so they are logged.
+func (s *TransactionService) learn(ctx context.Context, old, updated *models.Transaction) {
+	inc := map[string]float64{}
+	userID := ""
+	if suggest.Learnable(old) {
+		userID = old.UserID
+		for path, v := range suggest.Increments(*old, -1) {
+			inc[path] += v
+		}
+	}
+	if suggest.Learnable(updated) {
+		userID = updated.UserID
+		for path, v := range suggest.Increments(*updated, 1) {
+			inc[path] += v
+		}
+	}
+	if len(inc) == 0 {
+		return
+	}
+	if err := s.Categories.IncrementCategoryModel(ctx, userID, inc); err != nil {
+		log.Println(err)
+	}
+}
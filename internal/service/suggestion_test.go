@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/suggest"
+)
+
+func TestSuggestCategory(t *testing.T) {
+	ctx := context.Background()
+	history := []models.Transaction{
+		{UserID: "alice", Name: "Silpo", Category: "food", Cost: 40},
+		{UserID: "alice", Name: "Uber", Category: "transport", Cost: 10},
+	}
+
+	t.Run("trains and stores a model on first use", func(t *testing.T) {
+		srv, _ := newTxFixture(history...)
+		categories := &memCategoryModels{}
+		srv.Categories = categories
+		got, err := srv.SuggestCategory(ctx, models.SuggestCategory{UserID: "alice", Name: "silpo"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) == 0 || got[0].Category != "food" {
+			t.Errorf("got %+v, want food first", got)
+		}
+		if categories.model == nil || categories.model.Docs != 2 {
+			t.Errorf("stored %+v, want a model of both transactions", categories.model)
+		}
+	})
+
+	t.Run("keeps a model stored concurrently", func(t *testing.T) {
+		srv, _ := newTxFixture(history...)
+		// another call trained first and has learned a new transaction since.
+		concurrent := suggest.Train("alice", append(history, models.Transaction{UserID: "alice", Name: "Silpo", Category: "groceries", Cost: 40}))
+		categories := &memCategoryModels{}
+		categories.beforeInsert = func() { categories.model = concurrent }
+		srv.Categories = categories
+		got, err := srv.SuggestCategory(ctx, models.SuggestCategory{UserID: "alice", Name: "uber", Limit: 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if categories.model != concurrent {
+			t.Error("the concurrently stored model was replaced")
+		}
+		if len(got) != 3 {
+			t.Errorf("got %+v, want the three categories of the stored model", got)
+		}
+	})
+}
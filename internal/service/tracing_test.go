@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	otelcodes "go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestEndSpanRecordsErrors(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tr := provider.Tracer("test")
+
+	_, ok := tr.Start(context.Background(), "ok")
+	endSpan(ok, nil)
+	_, failed := tr.Start(context.Background(), "failed")
+	endSpan(failed, errors.New("boom"))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("ended %d spans", len(spans))
+	}
+	if spans[0].Status().Code != otelcodes.Unset {
+		t.Errorf("ok span status = %v", spans[0].Status())
+	}
+	if spans[1].Status().Code != otelcodes.Error || spans[1].Status().Description != "boom" {
+		t.Errorf("failed span status = %v", spans[1].Status())
+	}
+	if len(spans[1].Events()) != 1 || spans[1].Events()[0].Name != "exception" {
+		t.Errorf("failed span events = %v", spans[1].Events())
+	}
+}
@@ -7,29 +7,93 @@ import (
 	"time"
 
 	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/rules"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+var tracer = otel.Tracer("github.com/justIGreK/MoneyKeeper-Transaction/internal/service")
+
+// endSpan marks the span failed when the method returns an error.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+	span.End()
+}
+
 type TransactionRepository interface {
 	AddTransaction(ctx context.Context, transaction models.Transaction) (string, error)
 	GetTransaction(ctx context.Context, transactionID, userID string) (*models.Transaction, error)
+	GetTransactionByID(ctx context.Context, transactionID string) (*models.Transaction, error)
 	GetAllTransactions(ctx context.Context, userID string) ([]models.Transaction, error)
 	GetTXByTimeFrame(ctx context.Context, userID string, dateFrame models.TimeFrame) ([]models.Transaction, error)
+	GetCategoryTotals(ctx context.Context, userID string, dateFrame models.TimeFrame) ([]models.CategoryTotal, error)
 	UpdateTx(ctx context.Context, updates models.Transaction) error
 	DeleteTx(ctx context.Context, userID, txID string) error
+	DeleteTransfer(ctx context.Context, userID, transferID string) ([]string, error)
+	GetCashFlow(ctx context.Context, userID, accountID string, dateFrame models.TimeFrame) ([]models.CashFlowEntry, error)
+	GetAccountFlow(ctx context.Context, userID, accountID string, until time.Time) (float64, error)
+	MergeTransactions(ctx context.Context, keep models.Transaction, removed []models.Transaction, entry models.AuditEntry) error
+	GetRecentCosts(ctx context.Context, userID, category string, limit int) ([]float64, error)
+	CountPayeeTransactions(ctx context.Context, userID, payeeID string) (int64, error)
+	GetAnomalies(ctx context.Context, userID string, minScore float64, dateFrame models.TimeFrame, limit int) ([]models.Transaction, error)
+	CountTransactionsSince(ctx context.Context, userID string, since time.Time) (int64, error)
+}
+
+type AccountLookup interface {
+	GetAccount(ctx context.Context, accountID, userID string) (*models.Account, error)
+}
+
+type AttachmentCleaner interface {
+	DeleteTxAttachments(ctx context.Context, userID string, txIDs ...string) error
+}
+
+type RuleSource interface {
+	GetRules(ctx context.Context, userID string) ([]models.Rule, error)
+}
+
+type PayeeLinker interface {
+	LinkPayee(ctx context.Context, userID, name string) (string, error)
+}
+
+type LedgerAccess interface {
+	CheckLedgerRole(ctx context.Context, ledgerID, userID, role string) error
 }
 
 type TransactionService struct {
 	TransactionRepo TransactionRepository
+	Accounts        AccountLookup
+	Attachments     AttachmentCleaner
+	Rules           RuleSource
+	Categories      CategoryModelRepository
+	Payees          PayeeLinker
+	Ledgers         LedgerAccess
+	Budgets         BudgetProvider
 	User            UserService
+	Access          AccessPolicy
+	Anomalies       AnomalyConfig
+	Quota           TransactionQuota
+	validate        *Validator
 }
 
 type UserService interface {
 	GetUser(ctx context.Context, id string) (string, string, error)
 }
 
-func NewTransactionService(transRepo TransactionRepository, user UserService) *TransactionService {
-	return &TransactionService{TransactionRepo: transRepo,
-		User: user}
+type AccessPolicy interface {
+	CheckAccess(ctx context.Context, userID string) error
+}
+
+func NewTransactionService(transRepo TransactionRepository, accounts AccountLookup, attachments AttachmentCleaner, rules RuleSource, categories CategoryModelRepository, payees PayeeLinker, ledgers LedgerAccess, budgets BudgetProvider, user UserService, access AccessPolicy, anomalies AnomalyConfig, quota TransactionQuota) *TransactionService {
+	return &TransactionService{TransactionRepo: transRepo, Accounts: accounts, Attachments: attachments, Rules: rules,
+		Categories: categories, Payees: payees, Ledgers: ledgers, Budgets: budgets, User: user, Access: access, Anomalies: anomalies,
+		Quota: quota, validate: NewValidator()}
 }
 
 const (
@@ -39,9 +103,14 @@ const (
 	TimeFormat     string = "15:04"
 )
 
-func (s *TransactionService) AddTransaction(ctx context.Context, transaction models.CreateTransaction) (string, error) {
-	if transaction.Cost < 0 {
-		return "", errors.New("cost cant be below 0")
+func (s *TransactionService) AddTransaction(ctx context.Context, transaction models.CreateTransaction) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "TransactionService.AddTransaction", trace.WithAttributes(attribute.String("user.id", transaction.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(transaction); err != nil {
+		return "", err
+	}
+	if err := s.Access.CheckAccess(ctx, transaction.UserID); err != nil {
+		return "", err
 	}
 	id, _, err := s.User.GetUser(ctx, transaction.UserID)
 	if err != nil {
@@ -51,8 +120,16 @@ func (s *TransactionService) AddTransaction(ctx context.Context, transaction mod
 	if id == "" {
 		return "", errors.New("user not found")
 	}
-	if transaction.Category == "" {
-		transaction.Category = NoCategory
+	if err := s.checkAccount(ctx, transaction.AccountID, transaction.UserID); err != nil {
+		return "", err
+	}
+	if transaction.LedgerID != "" {
+		if err := s.Ledgers.CheckLedgerRole(ctx, transaction.LedgerID, transaction.UserID, models.RoleEditor); err != nil {
+			return "", err
+		}
+	}
+	if err := checkTransactionQuota(ctx, s.TransactionRepo, s.Quota, transaction.UserID, 1); err != nil {
+		return "", err
 	}
 	now := time.Now().UTC()
 	date := now
@@ -63,20 +140,38 @@ func (s *TransactionService) AddTransaction(ctx context.Context, transaction mod
 		}
 	}
 	createTransaction := models.Transaction{
-		UserID:   transaction.UserID,
-		Category: transaction.Category,
-		Name:     transaction.Name,
-		Cost:     transaction.Cost,
-		Date:     date,
-	}
+		UserID:    transaction.UserID,
+		Category:  transaction.Category,
+		Name:      transaction.Name,
+		Cost:      transaction.Cost,
+		Date:      date,
+		Splits:    transaction.Splits,
+		AccountID: transaction.AccountID,
+		Kind:      models.KindExpense,
+		Tags:      transaction.Tags,
+		Note:      transaction.Note,
+		LedgerID:  transaction.LedgerID,
+	}
+	s.applyRules(ctx, &createTransaction)
+	createTransaction.PayeeID = s.linkPayee(ctx, createTransaction.UserID, createTransaction.Name)
+	s.scoreAnomaly(ctx, &createTransaction)
 	id, err = s.TransactionRepo.AddTransaction(ctx, createTransaction)
 	if err != nil {
 		return "", err
 	}
+	s.learn(ctx, nil, &createTransaction)
 	return id, nil
 }
 
-func (s *TransactionService) GetTransaction(ctx context.Context, transactionID, userID string) (*models.Transaction, error) {
+func (s *TransactionService) GetTransaction(ctx context.Context, transactionID, userID string) (_ *models.Transaction, err error) {
+	ctx, span := tracer.Start(ctx, "TransactionService.GetTransaction", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.TransactionKey{ID: transactionID, UserID: userID}); err != nil {
+		return nil, err
+	}
+	if err := s.Access.CheckAccess(ctx, userID); err != nil {
+		return nil, err
+	}
 	user, _, err := s.User.GetUser(ctx, userID)
 	if err != nil {
 		log.Println(err)
@@ -85,15 +180,18 @@ func (s *TransactionService) GetTransaction(ctx context.Context, transactionID,
 	if user == "" {
 		return nil, errors.New("user not found")
 	}
-	trans, err := s.TransactionRepo.GetTransaction(ctx, transactionID, userID)
-	if err != nil {
-		log.Println(err)
-		return nil, err
-	}
-	return trans, nil
+	return s.accessibleTransaction(ctx, transactionID, userID, models.RoleViewer)
 }
 
-func (s *TransactionService) GetAllTransactions(ctx context.Context, userID string) ([]models.Transaction, error) {
+func (s *TransactionService) GetAllTransactions(ctx context.Context, userID string) (_ []models.Transaction, err error) {
+	ctx, span := tracer.Start(ctx, "TransactionService.GetAllTransactions", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.UserKey{UserID: userID}); err != nil {
+		return nil, err
+	}
+	if err := s.Access.CheckAccess(ctx, userID); err != nil {
+		return nil, err
+	}
 	user, _, err := s.User.GetUser(ctx, userID)
 	if err != nil {
 		log.Println(err)
@@ -110,7 +208,15 @@ func (s *TransactionService) GetAllTransactions(ctx context.Context, userID stri
 	}
 	return txs, nil
 }
-func (s *TransactionService) GetTXByTimeFrame(ctx context.Context, userID string, timeframe models.CreateTimeFrame) ([]models.Transaction, error) {
+func (s *TransactionService) GetTXByTimeFrame(ctx context.Context, userID string, timeframe models.CreateTimeFrame) (_ []models.Transaction, err error) {
+	ctx, span := tracer.Start(ctx, "TransactionService.GetTXByTimeFrame", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.UserKey{UserID: userID}, timeframe); err != nil {
+		return nil, err
+	}
+	if err := s.Access.CheckAccess(ctx, userID); err != nil {
+		return nil, err
+	}
 	user, _, err := s.User.GetUser(ctx, userID)
 	if err != nil {
 		log.Println(err)
@@ -119,26 +225,10 @@ func (s *TransactionService) GetTXByTimeFrame(ctx context.Context, userID string
 	if user == "" {
 		return nil, errors.New("user not found")
 	}
-	var tf models.TimeFrame
-	if timeframe.StartDate == "" {
-		tf.StartDate = time.Unix(0, 0)
-	} else {
-		date, err := time.Parse(Dateformat, timeframe.StartDate)
-		if err != nil {
-			log.Println(err)
-			return nil, err
-		}
-		tf.StartDate = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 1, date.Location())
-	}
-	if timeframe.EndDate == "" {
-		tf.EndDate = time.Now().AddDate(10000, 0, 0)
-	} else {
-		date, err := time.Parse(Dateformat, timeframe.EndDate)
-		if err != nil {
-			log.Println(err)
-			return nil, err
-		}
-		tf.EndDate = time.Date(date.Year(), date.Month(), date.Day(), 23, 59, 59, 9999999, date.Location())
+	tf, err := parseTimeFrame(timeframe)
+	if err != nil {
+		log.Println(err)
+		return nil, err
 	}
 
 	txs, err := s.TransactionRepo.GetTXByTimeFrame(ctx, userID, tf)
@@ -148,7 +238,15 @@ func (s *TransactionService) GetTXByTimeFrame(ctx context.Context, userID string
 	}
 	return txs, nil
 }
-func (s *TransactionService) DeleteTx(ctx context.Context, userID, txID string) error {
+func (s *TransactionService) DeleteTx(ctx context.Context, userID, txID string) (err error) {
+	ctx, span := tracer.Start(ctx, "TransactionService.DeleteTx", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.TransactionKey{ID: txID, UserID: userID}); err != nil {
+		return err
+	}
+	if err := s.Access.CheckAccess(ctx, userID); err != nil {
+		return err
+	}
 	user, _, err := s.User.GetUser(ctx, userID)
 	if err != nil {
 		log.Println(err)
@@ -158,22 +256,41 @@ func (s *TransactionService) DeleteTx(ctx context.Context, userID, txID string)
 		return errors.New("user not found")
 	}
 
-	tx, err := s.TransactionRepo.GetTransaction(ctx, txID, userID)
+	tx, err := s.accessibleTransaction(ctx, txID, userID, models.RoleEditor)
 	if err != nil {
-		log.Println(err)
 		return err
 	}
-	if tx == nil{
+	if tx == nil {
 		return errors.New("transaction is not found")
 	}
-	err = s.TransactionRepo.DeleteTx(ctx, txID, userID)
+	if tx.IsSettlement() {
+		return status.Error(codes.FailedPrecondition, "settlement entries belong to their group and cannot be deleted")
+	}
+	deleted := []string{txID}
+	if tx.IsTransfer() {
+		deleted, err = s.TransactionRepo.DeleteTransfer(ctx, tx.UserID, tx.TransferID)
+	} else {
+		err = s.TransactionRepo.DeleteTx(ctx, tx.UserID, txID)
+	}
 	if err != nil {
 		log.Println(err)
 		return err
 	}
+	if err := s.Attachments.DeleteTxAttachments(ctx, tx.UserID, deleted...); err != nil {
+		log.Printf("failed to delete attachments of %v, leaving them to the sweeper: %v", deleted, err)
+	}
+	s.learn(ctx, tx, nil)
 	return nil
 }
-func (s *TransactionService) UpdateTx(ctx context.Context, updates models.UpdateTransaction) (*models.Transaction, error) {
+func (s *TransactionService) UpdateTx(ctx context.Context, updates models.UpdateTransaction) (_ *models.Transaction, err error) {
+	ctx, span := tracer.Start(ctx, "TransactionService.UpdateTx", trace.WithAttributes(attribute.String("user.id", updates.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(updates); err != nil {
+		return nil, err
+	}
+	if err := s.Access.CheckAccess(ctx, updates.UserID); err != nil {
+		return nil, err
+	}
 	user, _, err := s.User.GetUser(ctx, updates.UserID)
 	if err != nil {
 		log.Println(err)
@@ -182,20 +299,36 @@ func (s *TransactionService) UpdateTx(ctx context.Context, updates models.Update
 	if user == "" {
 		return nil, errors.New("user not found")
 	}
-	tx, err := s.TransactionRepo.GetTransaction(ctx, updates.ID, updates.UserID)
+	tx, err := s.accessibleTransaction(ctx, updates.ID, updates.UserID, models.RoleEditor)
 	if err != nil {
-		log.Println(err)
 		return nil, err
 	}
 	if tx == nil {
 		return nil, errors.New("transaction is not found")
 	}
+	if tx.IsTransfer() {
+		return nil, status.Error(codes.FailedPrecondition, "transfer entries cannot be edited, delete the transfer and create a new one")
+	}
+	if tx.IsSettlement() {
+		return nil, status.Error(codes.FailedPrecondition, "settlement entries belong to their group and cannot be edited")
+	}
 	updatedTx := models.Transaction{
-		ID:     updates.ID,
-		UserID: updates.UserID,
+		ID:       updates.ID,
+		UserID:   tx.UserID,
+		Kind:     tx.Kind,
+		Tags:     tx.Tags,
+		Note:     tx.Note,
+		PayeeID:  tx.PayeeID,
+		LedgerID: tx.LedgerID,
+	}
+	if updates.Note != nil {
+		updatedTx.Note = *updates.Note
 	}
 	if updates.Name != nil {
 		updatedTx.Name = *updates.Name
+		if updatedTx.Name != tx.Name {
+			updatedTx.PayeeID = s.linkPayee(ctx, tx.UserID, updatedTx.Name)
+		}
 	} else {
 		updatedTx.Name = tx.Name
 	}
@@ -206,8 +339,26 @@ func (s *TransactionService) UpdateTx(ctx context.Context, updates models.Update
 	}
 	if updates.Category != nil {
 		updatedTx.Category = *updates.Category
+		updatedTx.CategorySetBy = models.CategoryByUser
 	} else {
 		updatedTx.Category = tx.Category
+		updatedTx.CategorySetBy = tx.CategorySetBy
+	}
+	if updates.Splits != nil {
+		updatedTx.Splits = *updates.Splits
+	} else {
+		updatedTx.Splits = tx.Splits
+	}
+	if !splitsMatchCost(updatedTx.Cost, updatedTx.Splits) {
+		return nil, splitSumViolation()
+	}
+	if updates.AccountID != nil {
+		updatedTx.AccountID = *updates.AccountID
+		if err := s.checkAccount(ctx, updatedTx.AccountID, tx.UserID); err != nil {
+			return nil, err
+		}
+	} else {
+		updatedTx.AccountID = tx.AccountID
 	}
 	updatedTx.Date, err = s.parseDateTime(tx, updates)
 
@@ -216,7 +367,8 @@ func (s *TransactionService) UpdateTx(ctx context.Context, updates models.Update
 		log.Println(err)
 		return nil, err
 	}
-	newTx, err := s.TransactionRepo.GetTransaction(ctx, updates.ID, updates.UserID)
+	s.learn(ctx, tx, &updatedTx)
+	newTx, err := s.TransactionRepo.GetTransaction(ctx, updates.ID, tx.UserID)
 	if err != nil {
 		log.Println(err)
 		return nil, err
@@ -232,20 +384,238 @@ func (s *TransactionService) parseDateTime(tx *models.Transaction, updates model
 	}
 	if updates.Date != nil {
 		date, err = time.Parse(Dateformat, *updates.Date)
-		if err != nil{
+		if err != nil {
 			return time.Time{}, err
 		}
-	}else {
+	} else {
 		date = tx.Date
 	}
 	if updates.Time != nil {
 		times, err = time.Parse(TimeFormat, *updates.Time)
-		if err != nil{
+		if err != nil {
 			return time.Time{}, err
 		}
-	}else {
+	} else {
 		times = tx.Date
-	} 
+	}
 	dateResp := time.Date(date.Year(), date.Month(), date.Day(), times.Hour(), times.Minute(), times.Second(), 0, time.Now().UTC().Location())
 	return dateResp, nil
 }
+
+func (s *TransactionService) GetSpendingSummary(ctx context.Context, userID string, timeframe models.CreateTimeFrame) (_ []models.CategoryTotal, err error) {
+	ctx, span := tracer.Start(ctx, "TransactionService.GetSpendingSummary", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.UserKey{UserID: userID}, timeframe); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, userID); err != nil {
+		return nil, err
+	}
+	tf, err := parseTimeFrame(timeframe)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	totals, err := s.TransactionRepo.GetCategoryTotals(ctx, userID, tf)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return totals, nil
+}
+
+func (s *TransactionService) GetCashFlowStatement(ctx context.Context, req models.CashFlowRequest, timeframe models.CreateTimeFrame) (_ *models.CashFlowStatement, err error) {
+	ctx, span := tracer.Start(ctx, "TransactionService.GetCashFlowStatement", trace.WithAttributes(attribute.String("user.id", req.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(req, timeframe); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, req.UserID); err != nil {
+		return nil, err
+	}
+	tf, err := parseTimeFrame(timeframe)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	opening, err := s.openingBalance(ctx, req, tf.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.TransactionRepo.GetCashFlow(ctx, req.UserID, req.AccountID, tf)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	statement := &models.CashFlowStatement{
+		AccountID:      req.AccountID,
+		OpeningBalance: opening,
+		ClosingBalance: opening,
+		Entries:        entries,
+	}
+	for i := range entries {
+		entries[i].Balance += opening
+		if entries[i].Amount > 0 {
+			statement.Inflow += entries[i].Amount
+		} else {
+			statement.Outflow -= entries[i].Amount
+		}
+		statement.ClosingBalance = entries[i].Balance
+	}
+	return statement, nil
+}
+
+// openingBalance uses the caller's starting balance when given, otherwise
+// the account's balance just before the period starts.
+func (s *TransactionService) openingBalance(ctx context.Context, req models.CashFlowRequest, start time.Time) (float64, error) {
+	if req.OpeningBalance != nil {
+		return *req.OpeningBalance, nil
+	}
+	if req.AccountID == "" {
+		return 0, nil
+	}
+	account, err := s.Accounts.GetAccount(ctx, req.AccountID, req.UserID)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	if account == nil {
+		return 0, errAccountNotFound
+	}
+	flow, err := s.TransactionRepo.GetAccountFlow(ctx, req.UserID, req.AccountID, start)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	return account.OpeningBalance + flow, nil
+}
+
+func authorizeUser(ctx context.Context, access AccessPolicy, users UserService, userID string) error {
+	if err := access.CheckAccess(ctx, userID); err != nil {
+		return err
+	}
+	user, _, err := users.GetUser(ctx, userID)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	if user == "" {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// accessibleTransaction loads a transaction the user may act on with role.
+// Authors always reach their own transactions; other members of the
+// transaction's ledger need at least role there. It returns nil when the
+// user may not see the transaction at all.
+func (s *TransactionService) accessibleTransaction(ctx context.Context, txID, userID, role string) (*models.Transaction, error) {
+	tx, err := s.TransactionRepo.GetTransactionByID(ctx, txID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if tx == nil || tx.UserID == userID {
+		return tx, nil
+	}
+	if tx.LedgerID == "" {
+		return nil, nil
+	}
+	if err := s.Ledgers.CheckLedgerRole(ctx, tx.LedgerID, userID, role); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (s *TransactionService) checkAccount(ctx context.Context, accountID, userID string) error {
+	if accountID == "" {
+		return nil
+	}
+	account, err := s.Accounts.GetAccount(ctx, accountID, userID)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	if account == nil {
+		return &ValidationError{Violations: []FieldViolation{{Field: "accountId", Description: "account not found"}}}
+	}
+	return nil
+}
+
+func parseTimeFrame(timeframe models.CreateTimeFrame) (models.TimeFrame, error) {
+	var tf models.TimeFrame
+	if timeframe.StartDate == "" {
+		tf.StartDate = time.Unix(0, 0)
+	} else {
+		date, err := time.Parse(Dateformat, timeframe.StartDate)
+		if err != nil {
+			return tf, err
+		}
+		tf.StartDate = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 1, date.Location())
+	}
+	if timeframe.EndDate == "" {
+		tf.EndDate = time.Now().AddDate(10000, 0, 0)
+	} else {
+		date, err := time.Parse(Dateformat, timeframe.EndDate)
+		if err != nil {
+			return tf, err
+		}
+		tf.EndDate = time.Date(date.Year(), date.Month(), date.Day(), 23, 59, 59, 9999999, date.Location())
+	}
+	return tf, nil
+}
+
+// applyRules adds the tags of the user's matching rules and, when no category
+// was given, picks one from the splits, the rules or falls back to NoCategory.
+// Rules are best effort: failing to load them does not fail the transaction.
+func (s *TransactionService) applyRules(ctx context.Context, tx *models.Transaction) {
+	var result rules.Result
+	userRules, err := s.Rules.GetRules(ctx, tx.UserID)
+	if err == nil {
+		var engine *rules.Engine
+		if engine, err = rules.New(userRules); err == nil {
+			result = engine.Apply(*tx)
+		}
+	}
+	if err != nil {
+		log.Println(err)
+	}
+	tx.Tags = rules.MergeTags(tx.Tags, result.Tags)
+	if tx.Category != "" {
+		tx.CategorySetBy = models.CategoryByUser
+		return
+	}
+	switch {
+	case len(tx.Splits) > 0:
+		tx.Category = dominantCategory(tx.Splits)
+		tx.CategorySetBy = models.CategoryByUser
+	case result.Category != "":
+		tx.Category = result.Category
+		tx.CategorySetBy = models.CategoryByRule
+	default:
+		tx.Category = NoCategory
+	}
+}
+
+func dominantCategory(splits []models.Split) string {
+	category, amount := NoCategory, 0.0
+	for _, split := range splits {
+		if split.Amount > amount {
+			category, amount = split.Category, split.Amount
+		}
+	}
+	return category
+}
+
+// linkPayee resolves the payee for a transaction name. A failure only
+// leaves the transaction unlinked, so it is logged.
+func (s *TransactionService) linkPayee(ctx context.Context, userID, name string) string {
+	payeeID, err := s.Payees.LinkPayee(ctx, userID, name)
+	if err != nil {
+		log.Println(err)
+	}
+	return payeeID
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const checkingID = "6650a1f1c2a4b5e6f7a8b901"
+
+type accountsByID map[string]models.Account
+
+func (a accountsByID) GetAccount(_ context.Context, accountID, userID string) (*models.Account, error) {
+	account, ok := a[accountID]
+	if !ok || account.UserID != userID {
+		return nil, nil
+	}
+	return &account, nil
+}
+
+func newTxFixture(txs ...models.Transaction) (*TransactionService, *memTxRepo) {
+	repo := &memTxRepo{txs: txs}
+	accounts := accountsByID{checkingID: {ID: checkingID, UserID: "alice", Currency: "USD", OpeningBalance: 100}}
+	srv := NewTransactionService(repo, accounts, nil, nil, nil, nil, nil, nil, knownUsers{"alice"}, allowAll{}, AnomalyConfig{}, TransactionQuota{})
+	return srv, repo
+}
+
+func TestGetCashFlowStatement(t *testing.T) {
+	ctx := context.Background()
+	at := func(day, hour int) time.Time { return time.Date(2024, 3, day, hour, 0, 0, 0, time.UTC) }
+	srv, _ := newTxFixture(
+		models.Transaction{ID: "b", UserID: "alice", AccountID: checkingID, Cost: 30, Kind: models.KindExpense, Date: at(2, 9)},
+		models.Transaction{ID: "a", UserID: "alice", AccountID: checkingID, Cost: 20, Kind: models.KindTransferIn, Date: at(2, 9)},
+		models.Transaction{ID: "c", UserID: "alice", AccountID: checkingID, Cost: 10, Date: at(1, 9)},
+		models.Transaction{ID: "d", UserID: "alice", AccountID: checkingID, Cost: 5, Kind: models.KindExpense, Date: at(5, 9)},
+		models.Transaction{ID: "e", UserID: "alice", Cost: 1, Kind: models.KindExpense, Date: at(3, 9)},
+	)
+
+	statement, err := srv.GetCashFlowStatement(ctx, models.CashFlowRequest{UserID: "alice", AccountID: checkingID},
+		models.CreateTimeFrame{StartDate: "2024-03-02", EndDate: "2024-03-04"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statement.OpeningBalance != 90 || statement.ClosingBalance != 80 {
+		t.Errorf("opening %v closing %v, want 90 and 80", statement.OpeningBalance, statement.ClosingBalance)
+	}
+	if statement.Inflow != 20 || statement.Outflow != 30 {
+		t.Errorf("inflow %v outflow %v, want 20 and 30", statement.Inflow, statement.Outflow)
+	}
+	var ids []string
+	var balances []float64
+	for _, e := range statement.Entries {
+		ids = append(ids, e.ID)
+		balances = append(balances, e.Balance)
+	}
+	if len(ids) != 2 || ids[0] != "a" || ids[1] != "b" || balances[0] != 110 || balances[1] != 80 {
+		t.Errorf("entries %v with balances %v, want [a b] with [110 80]", ids, balances)
+	}
+
+	opening := -15.0
+	statement, err = srv.GetCashFlowStatement(ctx, models.CashFlowRequest{UserID: "alice", OpeningBalance: &opening},
+		models.CreateTimeFrame{StartDate: "2024-03-03", EndDate: "2024-03-03"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statement.OpeningBalance != -15 || statement.ClosingBalance != -16 || len(statement.Entries) != 1 {
+		t.Errorf("given opening balance: got %+v", statement)
+	}
+
+	_, err = srv.GetCashFlowStatement(ctx, models.CashFlowRequest{UserID: "alice", AccountID: "6650a1f1c2a4b5e6f7a8b9ff"}, models.CreateTimeFrame{})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("unknown account: got %v, want NotFound", err)
+	}
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	categoryPattern = regexp.MustCompile(`^[\p{L}\p{N}][\p{L}\p{N} _&-]*$`)
+	// userIDPattern accepts whatever opaque ids the user service hands out,
+	// not only ObjectIDs.
+	userIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.:@-]{0,63}$`)
+)
+
+const centTolerance = 0.005
+
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Field + ": " + v.Description
+	}
+	return "invalid request: " + strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) GRPCStatus() *status.Status {
+	st := status.New(codes.InvalidArgument, e.Error())
+	br := &errdetails.BadRequest{}
+	for _, v := range e.Violations {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+	detailed, err := st.WithDetails(br)
+	if err != nil {
+		return st
+	}
+	return detailed
+}
+
+type Validator struct {
+	validate *validator.Validate
+}
+
+func NewValidator() *Validator {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "" || name == "-" {
+			return fld.Name
+		}
+		return name
+	})
+	v.RegisterValidation("category", func(fl validator.FieldLevel) bool {
+		return categoryPattern.MatchString(fl.Field().String())
+	})
+	v.RegisterValidation("userid", func(fl validator.FieldLevel) bool {
+		return userIDPattern.MatchString(fl.Field().String())
+	})
+	v.RegisterStructValidation(validateTimeFrame, models.CreateTimeFrame{})
+	v.RegisterStructValidation(validateCreateSplits, models.CreateTransaction{})
+	return &Validator{validate: v}
+}
+
+func validateTimeFrame(sl validator.StructLevel) {
+	tf := sl.Current().Interface().(models.CreateTimeFrame)
+	if tf.StartDate == "" || tf.EndDate == "" {
+		return
+	}
+	start, err := time.Parse(Dateformat, tf.StartDate)
+	if err != nil {
+		return
+	}
+	end, err := time.Parse(Dateformat, tf.EndDate)
+	if err != nil {
+		return
+	}
+	if end.Before(start) {
+		sl.ReportError(tf.EndDate, "endDate", "EndDate", "gtefield", "startDate")
+	}
+}
+
+func validateCreateSplits(sl validator.StructLevel) {
+	tx := sl.Current().Interface().(models.CreateTransaction)
+	if !splitsMatchCost(tx.Cost, tx.Splits) {
+		sl.ReportError(tx.Splits, "splits", "Splits", "splitsum", "")
+	}
+}
+
+func splitsMatchCost(cost float64, splits []models.Split) bool {
+	if len(splits) == 0 {
+		return true
+	}
+	var sum float64
+	for _, split := range splits {
+		sum += split.Amount
+	}
+	// Create requests carry cost as a float32, which above about 131072
+	// cannot hold every cent, so a sum rounding to the same float32 matches
+	// too.
+	return cents(sum) == cents(cost) || float32(sum) == float32(cost)
+}
+
+func cents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+func splitSumViolation() error {
+	return &ValidationError{Violations: []FieldViolation{{Field: "splits", Description: describeTag("splitsum")}}}
+}
+
+func (v *Validator) Struct(values ...interface{}) error {
+	var violations []FieldViolation
+	for _, value := range values {
+		err := v.validate.Struct(value)
+		if err == nil {
+			continue
+		}
+		verrs, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return err
+		}
+		for _, fe := range verrs {
+			violations = append(violations, FieldViolation{
+				Field:       fieldPath(fe),
+				Description: describe(fe),
+			})
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: violations}
+}
+
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return fe.Field()
+}
+
+func describe(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "mongodb":
+		return "must be a valid id"
+	case "userid":
+		return "must be a valid user id"
+	case "max":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at most %s characters long", fe.Param())
+		}
+		return "must be at most " + fe.Param()
+	case "min":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at least %s characters long", fe.Param())
+		}
+		return "must be at least " + fe.Param()
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "gte":
+		return "must be greater than or equal to " + fe.Param()
+	case "lte":
+		return "must be less than or equal to " + fe.Param()
+	case "datetime":
+		return "must match format " + fe.Param()
+	case "category":
+		return "may contain only letters, digits, spaces, '_', '&' and '-'"
+	case "gtefield":
+		return "must not be before " + fe.Param()
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "len":
+		return fmt.Sprintf("must be %s characters long", fe.Param())
+	case "hexadecimal":
+		return "must be a hexadecimal string"
+	case "http_url":
+		return "must be an http or https URL"
+	case "unique":
+		return "must not contain duplicates"
+	case "required_without":
+		return "is required when " + fieldName(fe.Param()) + " is empty"
+	case "excluded_with":
+		return "must be empty when " + fieldName(fe.Param()) + " is set"
+	case "ne":
+		return "must not be " + fe.Param()
+	}
+	return describeTag(fe.Tag())
+}
+
+// fieldName turns a Go field name used as a validation parameter into the
+// json name clients see.
+func fieldName(name string) string {
+	if name == "" {
+		return name
+	}
+	if strings.HasSuffix(name, "ID") {
+		name = strings.TrimSuffix(name, "ID") + "Id"
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+func describeTag(tag string) string {
+	switch tag {
+	case "splitsum":
+		return "amounts must add up to cost"
+	}
+	return "failed on '" + tag + "' rule"
+}
+
+func fieldIndex(field string, i int, sub string) string {
+	return fmt.Sprintf("%s[%d].%s", field, i, sub)
+}
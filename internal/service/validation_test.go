@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func violations(t *testing.T, err error) map[string]string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	fields := make(map[string]string, len(verr.Violations))
+	for _, v := range verr.Violations {
+		fields[v.Field] = v.Description
+	}
+	return fields
+}
+
+func TestValidateCreateTransaction(t *testing.T) {
+	date := "2024-03-01T10:00:00"
+	badDate := "2024-03-01"
+	tests := []struct {
+		name string
+		tx   models.CreateTransaction
+		want map[string]string
+	}{
+		{
+			name: "valid",
+			tx:   models.CreateTransaction{UserID: "6650a1f1c2a4b5e6f7a8b9c0", Name: "Coffee", Cost: 3.5, Category: "food & drinks", Date: &date},
+		},
+		{
+			name: "zero cost",
+			tx:   models.CreateTransaction{UserID: "user-42", Name: "Free sample"},
+		},
+		{
+			name: "opaque user id",
+			tx:   models.CreateTransaction{UserID: "auth0|abc", Name: "Coffee", Cost: 1},
+			want: map[string]string{"userId": "must be a valid user id"},
+		},
+		{
+			name: "missing fields",
+			tx:   models.CreateTransaction{Cost: -1},
+			want: map[string]string{
+				"userId": "is required",
+				"name":   "is required",
+				"cost":   "must be greater than or equal to 0",
+			},
+		},
+		{
+			name: "bad category and date",
+			tx:   models.CreateTransaction{UserID: "u1", Name: "Coffee", Cost: 1, Category: "food/drinks", Date: &badDate},
+			want: map[string]string{
+				"category": "may contain only letters, digits, spaces, '_', '&' and '-'",
+				"date":     "must match format 2006-01-02T15:04:05",
+			},
+		},
+		{
+			name: "splits must add up",
+			tx: models.CreateTransaction{UserID: "u1", Name: "Groceries", Cost: 10, Splits: []models.Split{
+				{Category: "food", Amount: 6},
+				{Category: "home", Amount: 3},
+			}},
+			want: map[string]string{"splits": "amounts must add up to cost"},
+		},
+		{
+			name: "split line violations",
+			tx: models.CreateTransaction{UserID: "u1", Name: "Groceries", Cost: 10, Splits: []models.Split{
+				{Amount: 10},
+			}},
+			want: map[string]string{"splits[0].category": "is required"},
+		},
+	}
+	v := NewValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := violations(t, v.Struct(tt.tx))
+			if len(got) != len(tt.want) {
+				t.Fatalf("violations = %v, want %v", got, tt.want)
+			}
+			for field, desc := range tt.want {
+				if got[field] != desc {
+					t.Errorf("%s: got %q, want %q", field, got[field], desc)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateTimeFrame(t *testing.T) {
+	v := NewValidator()
+	if err := v.Struct(models.CreateTimeFrame{StartDate: "2024-03-01", EndDate: "2024-03-01"}); err != nil {
+		t.Fatalf("same day: %v", err)
+	}
+	got := violations(t, v.Struct(models.CreateTimeFrame{StartDate: "2024-03-02", EndDate: "2024-03-01"}))
+	if got["endDate"] != "must not be before startDate" {
+		t.Fatalf("violations = %v", got)
+	}
+}
+
+func TestSplitsMatchCost(t *testing.T) {
+	tests := []struct {
+		name   string
+		cost   float64
+		splits []float64
+		want   bool
+	}{
+		{"no splits", 12.5, nil, true},
+		{"exact", 10, []float64{6, 4}, true},
+		{"float noise", 0.3, []float64{0.1, 0.2}, true},
+		{"off by a cent", 10, []float64{6, 3.99}, false},
+		{"over", 10, []float64{6, 5}, false},
+		{"large float32 cost", float64(float32(250000.01)), []float64{200000, 50000.01}, true},
+		{"large cost off by a unit", float64(float32(250000.01)), []float64{200000, 50001}, false},
+		{"sub-cent amounts", 1, []float64{0.333, 0.333, 0.334}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			splits := make([]models.Split, len(tt.splits))
+			for i, amount := range tt.splits {
+				splits[i] = models.Split{Category: "c", Amount: amount}
+			}
+			if got := splitsMatchCost(tt.cost, splits); got != tt.want {
+				t.Fatalf("splitsMatchCost(%v, %v) = %v, want %v", tt.cost, tt.splits, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidationErrorStatus(t *testing.T) {
+	err := &ValidationError{Violations: []FieldViolation{{Field: "name", Description: "is required"}}}
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.InvalidArgument {
+		t.Fatalf("status = %v", st)
+	}
+	if len(st.Details()) != 1 {
+		t.Fatalf("details = %v", st.Details())
+	}
+	br, ok := st.Details()[0].(*errdetails.BadRequest)
+	if !ok || br.FieldViolations[0].Field != "name" {
+		t.Fatalf("detail = %v", st.Details()[0])
+	}
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type WebhookRepository interface {
+	AddWebhook(ctx context.Context, webhook models.Webhook) (string, error)
+	GetWebhook(ctx context.Context, webhookID, userID string) (*models.Webhook, error)
+	GetWebhooks(ctx context.Context, userID string) ([]models.Webhook, error)
+	DeleteWebhook(ctx context.Context, webhookID, userID string) error
+	GetDeliveries(ctx context.Context, filter models.DeliveryFilter) ([]models.WebhookDelivery, error)
+	RetryDelivery(ctx context.Context, deliveryID, userID string) (bool, error)
+}
+
+// WebhookSecrets encrypts webhook secrets before they are stored.
+type WebhookSecrets interface {
+	Seal(secret string) (string, error)
+}
+
+// WebhookTargets refuses webhook URLs the service must not call.
+type WebhookTargets interface {
+	CheckURL(ctx context.Context, rawURL string) error
+}
+
+type WebhookService struct {
+	WebhookRepo WebhookRepository
+	// Secrets is nil when webhooks are disabled.
+	Secrets  WebhookSecrets
+	Targets  WebhookTargets
+	User     UserService
+	Access   AccessPolicy
+	validate *Validator
+}
+
+func NewWebhookService(webhookRepo WebhookRepository, secrets WebhookSecrets, targets WebhookTargets, user UserService, access AccessPolicy) *WebhookService {
+	return &WebhookService{WebhookRepo: webhookRepo, Secrets: secrets, Targets: targets,
+		User: user, Access: access, validate: NewValidator()}
+}
+
+const maxWebhooksPerUser = 10
+
+var errWebhookNotFound = status.Error(codes.NotFound, "webhook is not found")
+
+// CreateWebhook stores the webhook with its secret encrypted. When the caller
+// does not supply a secret one is generated and returned, this once, in the
+// webhook's Secret.
+func (s *WebhookService) CreateWebhook(ctx context.Context, create models.CreateWebhook) (_ *models.Webhook, err error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.CreateWebhook", trace.WithAttributes(attribute.String("user.id", create.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(create); err != nil {
+		return nil, err
+	}
+	if s.Secrets == nil {
+		return nil, status.Error(codes.FailedPrecondition, "webhooks are disabled on this server")
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, create.UserID); err != nil {
+		return nil, err
+	}
+	if err := s.Targets.CheckURL(ctx, create.URL); err != nil {
+		return nil, &ValidationError{Violations: []FieldViolation{{Field: "url", Description: err.Error()}}}
+	}
+	existing, err := s.WebhookRepo.GetWebhooks(ctx, create.UserID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if len(existing) >= maxWebhooksPerUser {
+		return nil, status.Errorf(codes.FailedPrecondition, "user already has %d webhooks", maxWebhooksPerUser)
+	}
+	webhook := models.Webhook{
+		UserID:     create.UserID,
+		URL:        create.URL,
+		EventTypes: create.EventTypes,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if webhook.EventTypes == nil {
+		webhook.EventTypes = []string{}
+	}
+	secret := create.Secret
+	if secret == "" {
+		raw := make([]byte, 32)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		secret = hex.EncodeToString(raw)
+		webhook.Secret = secret
+	}
+	webhook.SealedSecret, err = s.Secrets.Seal(secret)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	webhook.ID, err = s.WebhookRepo.AddWebhook(ctx, webhook)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (s *WebhookService) GetWebhooks(ctx context.Context, userID string) (_ []models.Webhook, err error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.GetWebhooks", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.UserKey{UserID: userID}); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, userID); err != nil {
+		return nil, err
+	}
+	webhooks, err := s.WebhookRepo.GetWebhooks(ctx, userID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, webhookID, userID string) (err error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.DeleteWebhook", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.getWebhook(ctx, webhookID, userID); err != nil {
+		return err
+	}
+	if err := s.WebhookRepo.DeleteWebhook(ctx, webhookID, userID); err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (s *WebhookService) GetDeliveries(ctx context.Context, filter models.DeliveryFilter) (_ []models.WebhookDelivery, err error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.GetDeliveries", trace.WithAttributes(attribute.String("user.id", filter.UserID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(filter); err != nil {
+		return nil, err
+	}
+	if err := s.getWebhook(ctx, filter.WebhookID, filter.UserID); err != nil {
+		return nil, err
+	}
+	if filter.Limit == 0 {
+		filter.Limit = 50
+	}
+	deliveries, err := s.WebhookRepo.GetDeliveries(ctx, filter)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (s *WebhookService) RetryDelivery(ctx context.Context, deliveryID, userID string) (err error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.RetryDelivery", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { endSpan(span, err) }()
+	if err := s.validate.Struct(models.DeliveryKey{ID: deliveryID, UserID: userID}); err != nil {
+		return err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, userID); err != nil {
+		return err
+	}
+	ok, err := s.WebhookRepo.RetryDelivery(ctx, deliveryID, userID)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	if !ok {
+		return status.Error(codes.NotFound, "dead delivery is not found")
+	}
+	return nil
+}
+
+func (s *WebhookService) getWebhook(ctx context.Context, webhookID, userID string) error {
+	if err := s.validate.Struct(models.WebhookKey{ID: webhookID, UserID: userID}); err != nil {
+		return err
+	}
+	if err := authorizeUser(ctx, s.Access, s.User, userID); err != nil {
+		return err
+	}
+	webhook, err := s.WebhookRepo.GetWebhook(ctx, webhookID, userID)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	if webhook == nil {
+		return errWebhookNotFound
+	}
+	return nil
+}
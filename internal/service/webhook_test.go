@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type memWebhookRepo struct {
+	WebhookRepository
+	webhooks []models.Webhook
+}
+
+func (r *memWebhookRepo) AddWebhook(_ context.Context, webhook models.Webhook) (string, error) {
+	webhook.ID = "6650a1f1c2a4b5e6f7a8b9c1"
+	r.webhooks = append(r.webhooks, webhook)
+	return webhook.ID, nil
+}
+
+func (r *memWebhookRepo) GetWebhooks(context.Context, string) ([]models.Webhook, error) {
+	return r.webhooks, nil
+}
+
+type reverseSecrets struct{}
+
+func (reverseSecrets) Seal(secret string) (string, error) {
+	sealed := []rune(secret)
+	for i, j := 0, len(sealed)-1; i < j; i, j = i+1, j-1 {
+		sealed[i], sealed[j] = sealed[j], sealed[i]
+	}
+	return "sealed:" + string(sealed), nil
+}
+
+type refuseTargets map[string]bool
+
+func (t refuseTargets) CheckURL(_ context.Context, rawURL string) error {
+	if t[rawURL] {
+		return errors.New("must not point at a private address")
+	}
+	return nil
+}
+
+func TestCreateWebhook(t *testing.T) {
+	ctx := context.Background()
+	repo := &memWebhookRepo{}
+	srv := NewWebhookService(repo, reverseSecrets{}, refuseTargets{"http://10.0.0.1/hook": true}, knownUsers{"alice"}, allowAll{})
+
+	generated, err := srv.CreateWebhook(ctx, models.CreateWebhook{UserID: "alice", URL: "https://example.com/hook"})
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+	if len(generated.Secret) != 64 {
+		t.Errorf("generated secret %q, want 64 hex characters", generated.Secret)
+	}
+	if stored := repo.webhooks[0]; stored.SealedSecret == "" || stored.SealedSecret == generated.Secret {
+		t.Errorf("stored secret %q, want it sealed", stored.SealedSecret)
+	}
+
+	supplied, err := srv.CreateWebhook(ctx, models.CreateWebhook{UserID: "alice", URL: "https://example.com/hook", Secret: "my-own-secret-value"})
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+	if supplied.Secret != "" {
+		t.Errorf("a supplied secret must not be echoed back, got %q", supplied.Secret)
+	}
+	if got := repo.webhooks[1].SealedSecret; got != "sealed:eulav-terces-nwo-ym" {
+		t.Errorf("stored %q, want the supplied secret sealed", got)
+	}
+
+	_, err = srv.CreateWebhook(ctx, models.CreateWebhook{UserID: "alice", URL: "http://10.0.0.1/hook"})
+	if got := violations(t, err); got["url"] != "must not point at a private address" {
+		t.Errorf("private target: got %v", got)
+	}
+
+	srv.Secrets = nil
+	if _, err := srv.CreateWebhook(ctx, models.CreateWebhook{UserID: "alice", URL: "https://example.com/hook"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("without a secret key: got %v, want FailedPrecondition", err)
+	}
+}
@@ -0,0 +1,38 @@
+package statement
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+// FileSink writes statements to <dir>/<userID>/<document name>.
+type FileSink struct {
+	dir string
+}
+
+func NewFileSink(dir string) *FileSink {
+	return &FileSink{dir: dir}
+}
+
+func (f *FileSink) Deliver(ctx context.Context, userID string, doc models.Document) error {
+	dir := filepath.Join(f.dir, filepath.Base(userID))
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".statement-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(doc.Data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, filepath.Base(doc.Name)))
+}
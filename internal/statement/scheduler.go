@@ -0,0 +1,97 @@
+package statement
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+// Sink delivers a rendered statement to its user.
+type Sink interface {
+	Deliver(ctx context.Context, userID string, doc models.Document) error
+}
+
+type Generator interface {
+	RenderStatement(ctx context.Context, userID string, month time.Time, format string) (models.Document, error)
+}
+
+// Store lists who needs a statement and remembers who got one, so a
+// restarted scheduler does not deliver twice.
+type Store interface {
+	GetStatementUsers(ctx context.Context, dateFrame models.TimeFrame) ([]string, error)
+	IsStatementDelivered(ctx context.Context, userID, period string) (bool, error)
+	MarkStatementDelivered(ctx context.Context, userID, period, format string) error
+}
+
+type SchedulerConfig struct {
+	Interval time.Duration
+	Format   string
+	// Day of the month from which the previous month's statements are sent.
+	Day int
+}
+
+type Scheduler struct {
+	generator Generator
+	store     Store
+	sink      Sink
+	cfg       SchedulerConfig
+}
+
+func NewScheduler(generator Generator, store Store, sink Sink, cfg SchedulerConfig) *Scheduler {
+	return &Scheduler{generator: generator, store: store, sink: sink, cfg: cfg}
+}
+
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		s.runOnce(ctx, time.Now().UTC())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce sends last month's statement to every user who had transactions
+// in it and has not received one yet. Failures are retried on the next run.
+func (s *Scheduler) runOnce(ctx context.Context, now time.Time) {
+	if now.Day() < s.cfg.Day {
+		return
+	}
+	start := time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, time.UTC)
+	period := start.Format("2006-01")
+	users, err := s.store.GetStatementUsers(ctx, models.TimeFrame{StartDate: start, EndDate: start.AddDate(0, 1, 0)})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	for _, userID := range users {
+		if ctx.Err() != nil {
+			return
+		}
+		delivered, err := s.store.IsStatementDelivered(ctx, userID, period)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		if delivered {
+			continue
+		}
+		doc, err := s.generator.RenderStatement(ctx, userID, start, s.cfg.Format)
+		if err != nil {
+			log.Printf("statement %s for user %s: %v", period, userID, err)
+			continue
+		}
+		if err := s.sink.Deliver(ctx, userID, doc); err != nil {
+			log.Printf("deliver statement %s to user %s: %v", period, userID, err)
+			continue
+		}
+		if err := s.store.MarkStatementDelivered(ctx, userID, period, s.cfg.Format); err != nil {
+			log.Println(err)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package suggest
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+// minCostDocs is how many transactions a category needs before its cost
+// distribution is trusted.
+const minCostDocs = 3
+
+// Tokenize lowercases name and splits it into words, dropping one-letter
+// words and pure numbers such as card or terminal numbers.
+func Tokenize(name string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := fields[:0]
+	for _, f := range fields {
+		if len([]rune(f)) < 2 || strings.IndexFunc(f, unicode.IsLetter) < 0 {
+			continue
+		}
+		tokens = append(tokens, f)
+	}
+	return tokens
+}
+
+// Learnable reports whether a transaction should be part of the model.
+func Learnable(tx *models.Transaction) bool {
+	return tx != nil && tx.IsSpending() && tx.Category != ""
+}
+
+func Train(userID string, txs []models.Transaction) *models.CategoryModel {
+	model := &models.CategoryModel{UserID: userID, Categories: map[string]models.CategoryStats{}}
+	for i := range txs {
+		if !Learnable(&txs[i]) {
+			continue
+		}
+		tx := txs[i]
+		stats := model.Categories[tx.Category]
+		if stats.Words == nil {
+			stats.Words = map[string]int{}
+		}
+		for _, token := range Tokenize(tx.Name) {
+			stats.Words[token]++
+			stats.Tokens++
+		}
+		stats.Docs++
+		if tx.Cost > 0 {
+			stats.LogCost += math.Log(tx.Cost)
+			stats.LogCostSq += math.Log(tx.Cost) * math.Log(tx.Cost)
+		}
+		model.Categories[tx.Category] = stats
+		model.Docs++
+	}
+	return model
+}
+
+// Increments returns the counter changes that adding (sign 1) or removing
+// (sign -1) a transaction makes to a stored model, keyed by document path.
+func Increments(tx models.Transaction, sign int) map[string]float64 {
+	prefix := "categories." + tx.Category + "."
+	inc := map[string]float64{
+		"docs":          float64(sign),
+		prefix + "docs": float64(sign),
+	}
+	for _, token := range Tokenize(tx.Name) {
+		inc[prefix+"words."+token] += float64(sign)
+		inc[prefix+"tokens"] += float64(sign)
+	}
+	if tx.Cost > 0 {
+		inc[prefix+"log_cost"] = float64(sign) * math.Log(tx.Cost)
+		inc[prefix+"log_cost_sq"] = float64(sign) * math.Log(tx.Cost) * math.Log(tx.Cost)
+	}
+	return inc
+}
+
+// Rank scores every category with multinomial naive Bayes over the name
+// tokens, with add-one smoothing, plus a log-normal likelihood of the cost
+// when the category has enough history. Scores are normalised into
+// confidences that sum to one.
+func Rank(model *models.CategoryModel, name string, cost float64, limit int) []models.CategorySuggestion {
+	if model == nil || model.Docs <= 0 {
+		return []models.CategorySuggestion{}
+	}
+	vocabulary := map[string]bool{}
+	for _, stats := range model.Categories {
+		for word, n := range stats.Words {
+			if n > 0 {
+				vocabulary[word] = true
+			}
+		}
+	}
+	tokens := Tokenize(name)
+	type score struct {
+		category string
+		logP     float64
+	}
+	var scores []score
+	for category, stats := range model.Categories {
+		if stats.Docs <= 0 {
+			continue
+		}
+		logP := math.Log(float64(stats.Docs) / float64(model.Docs))
+		for _, token := range tokens {
+			logP += math.Log(float64(max(stats.Words[token], 0)+1) / float64(max(stats.Tokens, 0)+len(vocabulary)+1))
+		}
+		if cost > 0 && stats.Docs >= minCostDocs {
+			logP += logNormal(math.Log(cost), stats)
+		}
+		scores = append(scores, score{category: category, logP: logP})
+	}
+	if len(scores) == 0 {
+		return []models.CategorySuggestion{}
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].logP != scores[j].logP {
+			return scores[i].logP > scores[j].logP
+		}
+		return scores[i].category < scores[j].category
+	})
+	var total float64
+	for _, s := range scores {
+		total += math.Exp(s.logP - scores[0].logP)
+	}
+	if limit <= 0 || limit > len(scores) {
+		limit = len(scores)
+	}
+	suggestions := make([]models.CategorySuggestion, limit)
+	for i := range suggestions {
+		suggestions[i] = models.CategorySuggestion{
+			Category:   scores[i].category,
+			Confidence: math.Exp(scores[i].logP-scores[0].logP) / total,
+		}
+	}
+	return suggestions
+}
+
+func logNormal(x float64, stats models.CategoryStats) float64 {
+	n := float64(stats.Docs)
+	mean := stats.LogCost / n
+	// a floor on the variance keeps categories with near-constant costs
+	// from dominating every other signal.
+	variance := math.Max(stats.LogCostSq/n-mean*mean, 0.25)
+	return -0.5*math.Log(2*math.Pi*variance) - (x-mean)*(x-mean)/(2*variance)
+}
@@ -0,0 +1,85 @@
+package suggest
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("POS 1234 SILPO-Kyiv #22 a Кава")
+	if want := []string{"pos", "silpo", "kyiv", "кава"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func history() []models.Transaction {
+	return []models.Transaction{
+		{Name: "Silpo groceries", Category: "food", Cost: 40},
+		{Name: "Silpo", Category: "food", Cost: 55},
+		{Name: "ATB market", Category: "food", Cost: 30},
+		{Name: "Uber ride", Category: "transport", Cost: 8},
+		{Name: "Uber", Category: "transport", Cost: 12},
+		{Name: "Salary", Cost: 1000, Kind: models.KindTransferIn},
+	}
+}
+
+func TestIncrementsMatchTrain(t *testing.T) {
+	txs := history()
+	trained := Train("alice", txs)
+	if trained.Docs != 5 {
+		t.Fatalf("trained on %d transactions, want 5 spending ones", trained.Docs)
+	}
+
+	// applying increments one by one must produce the same counters.
+	counters := map[string]float64{}
+	for i := range txs {
+		if !Learnable(&txs[i]) {
+			continue
+		}
+		for path, v := range Increments(txs[i], 1) {
+			counters[path] += v
+		}
+	}
+	if counters["docs"] != float64(trained.Docs) {
+		t.Errorf("docs: %v vs %d", counters["docs"], trained.Docs)
+	}
+	for category, stats := range trained.Categories {
+		prefix := "categories." + category + "."
+		if counters[prefix+"docs"] != float64(stats.Docs) || counters[prefix+"tokens"] != float64(stats.Tokens) {
+			t.Errorf("%s: increments %v, trained %+v", category, counters, stats)
+		}
+		if math.Abs(counters[prefix+"log_cost"]-stats.LogCost) > 1e-9 {
+			t.Errorf("%s log_cost: %v vs %v", category, counters[prefix+"log_cost"], stats.LogCost)
+		}
+		for word, n := range stats.Words {
+			if counters[prefix+"words."+word] != float64(n) {
+				t.Errorf("%s word %q: %v vs %d", category, word, counters[prefix+"words."+word], n)
+			}
+		}
+	}
+
+	removed := Increments(txs[0], -1)
+	if removed["docs"] != -1 || removed["categories.food.words.silpo"] != -1 {
+		t.Errorf("removal increments: %v", removed)
+	}
+}
+
+func TestRank(t *testing.T) {
+	model := Train("alice", history())
+	got := Rank(model, "SILPO #88", 45, 0)
+	if len(got) != 2 || got[0].Category != "food" {
+		t.Fatalf("got %+v, want food first", got)
+	}
+	if sum := got[0].Confidence + got[1].Confidence; math.Abs(sum-1) > 1e-9 || got[0].Confidence <= got[1].Confidence {
+		t.Errorf("confidences %+v, want them to sum to one with food ahead", got)
+	}
+	if got := Rank(model, "uber", 10, 1); len(got) != 1 || got[0].Category != "transport" {
+		t.Errorf("got %+v, want only transport", got)
+	}
+	if got := Rank(nil, "uber", 10, 3); len(got) != 0 {
+		t.Errorf("no model: got %+v", got)
+	}
+}
@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/events"
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+type Store interface {
+	GetWebhooks(ctx context.Context, userID string) ([]models.Webhook, error)
+	GetWebhook(ctx context.Context, webhookID, userID string) (*models.Webhook, error)
+	EnqueueDelivery(ctx context.Context, delivery models.WebhookDelivery) error
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, deliveryID string, responseCode int) error
+	MarkFailed(ctx context.Context, deliveryID string, responseCode int, cause string, nextAttempt *time.Time) error
+}
+
+// Dispatcher is an events.EventPublisher that turns each outbox event into
+// one queued delivery per subscribed webhook of the event's user.
+type Dispatcher struct {
+	store Store
+}
+
+func NewDispatcher(store Store) *Dispatcher {
+	return &Dispatcher{store: store}
+}
+
+func (d *Dispatcher) Publish(ctx context.Context, event models.Event) error {
+	webhooks, err := d.store.GetWebhooks(ctx, event.UserID)
+	if err != nil {
+		return err
+	}
+	var payload []byte
+	for _, w := range webhooks {
+		if !w.Subscribed(event.Type) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = events.Encode(event); err != nil {
+				return err
+			}
+		}
+		now := time.Now().UTC()
+		err := d.store.EnqueueDelivery(ctx, models.WebhookDelivery{
+			WebhookID:     w.ID,
+			UserID:        w.UserID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       payload,
+			Status:        models.DeliveryPending,
+			CreatedAt:     now,
+			NextAttemptAt: now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Dispatcher) Close() error {
+	return nil
+}
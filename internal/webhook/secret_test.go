@@ -0,0 +1,27 @@
+package webhook
+
+import "testing"
+
+func TestSecretBox(t *testing.T) {
+	box := newTestSecretBox(t)
+	sealed, err := box.Seal("s3cret-s3cret-s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sealed == "s3cret-s3cret-s3cret" {
+		t.Fatal("secret stored in plaintext")
+	}
+	again, _ := box.Seal("s3cret-s3cret-s3cret")
+	if again == sealed {
+		t.Error("sealing twice should use different nonces")
+	}
+	if secret, err := box.Open(sealed); err != nil || secret != "s3cret-s3cret-s3cret" {
+		t.Errorf("Open = %q, %v", secret, err)
+	}
+	if _, err := newTestSecretBox(t).Open(sealed); err == nil {
+		t.Error("opening with another key should fail")
+	}
+	if _, err := NewSecretBox("c2hvcnQ="); err == nil {
+		t.Error("a short key should be rejected")
+	}
+}
@@ -0,0 +1,30 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+const (
+	HeaderSignature = "X-MoneyKeeper-Signature"
+	HeaderTimestamp = "X-MoneyKeeper-Timestamp"
+	HeaderEvent     = "X-MoneyKeeper-Event"
+	HeaderDelivery  = "X-MoneyKeeper-Delivery"
+)
+
+// Sign returns the value of the signature header: the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the webhook secret. Receivers should
+// recompute it and reject stale timestamps.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+type WorkerConfig struct {
+	Interval    time.Duration
+	BatchSize   int
+	Timeout     time.Duration
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// Worker sends queued deliveries. A delivery succeeds on any 2xx response;
+// otherwise it is retried with exponential backoff until MaxAttempts, after
+// which it becomes a dead letter.
+type Worker struct {
+	store   Store
+	secrets *SecretBox
+	client  *http.Client
+	cfg     WorkerConfig
+}
+
+func NewWorker(store Store, secrets *SecretBox, cfg WorkerConfig) *Worker {
+	return &Worker{store: store, secrets: secrets, client: NewHTTPClient(cfg.Timeout), cfg: cfg}
+}
+
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		for w.deliverBatch(ctx) == w.cfg.BatchSize {
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) deliverBatch(ctx context.Context) int {
+	// the lease has to outlive every request of the batch.
+	lease := 2*w.cfg.Timeout + time.Minute
+	deliveries, err := w.store.ClaimDueDeliveries(ctx, time.Now().UTC(), lease, w.cfg.BatchSize)
+	if err != nil {
+		log.Println(err)
+	}
+	var wg sync.WaitGroup
+	for _, d := range deliveries {
+		wg.Add(1)
+		go func(d models.WebhookDelivery) {
+			defer wg.Done()
+			w.deliver(ctx, d)
+		}(d)
+	}
+	wg.Wait()
+	return len(deliveries)
+}
+
+func (w *Worker) deliver(ctx context.Context, d models.WebhookDelivery) {
+	webhook, err := w.store.GetWebhook(ctx, d.WebhookID, "")
+	if err != nil {
+		log.Println(err)
+		w.fail(ctx, d, 0, "loading the webhook failed", false)
+		return
+	}
+	if webhook == nil {
+		w.fail(ctx, d, 0, "webhook was deleted", true)
+		return
+	}
+	secret, err := w.secrets.Open(webhook.SealedSecret)
+	if err != nil {
+		w.fail(ctx, d, 0, err.Error(), true)
+		return
+	}
+	code, err := w.send(ctx, webhook.URL, secret, d)
+	if err != nil {
+		w.fail(ctx, d, code, err.Error(), false)
+		return
+	}
+	if err := w.store.MarkDelivered(ctx, d.ID, code); err != nil {
+		log.Println(err)
+	}
+}
+
+func (w *Worker) send(ctx context.Context, url, secret string, d models.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "MoneyKeeper-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, d.EventType)
+	req.Header.Set(HeaderDelivery, d.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(secret, timestamp, d.Payload))
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+func (w *Worker) fail(ctx context.Context, d models.WebhookDelivery, code int, cause string, permanent bool) {
+	var next *time.Time
+	if attempt := d.Attempts + 1; !permanent && attempt < w.cfg.MaxAttempts {
+		at := time.Now().UTC().Add(w.backoff(attempt))
+		next = &at
+	}
+	if err := w.store.MarkFailed(ctx, d.ID, code, cause, next); err != nil {
+		log.Println(err)
+	}
+}
+
+// backoff returns BaseBackoff doubled for every attempt already made, capped
+// at MaxBackoff.
+func (w *Worker) backoff(attempt int) time.Duration {
+	delay := w.cfg.BaseBackoff
+	for i := 1; i < attempt && delay < w.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, w.cfg.MaxBackoff)
+}
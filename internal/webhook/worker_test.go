@@ -0,0 +1,159 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/justIGreK/MoneyKeeper-Transaction/internal/models"
+)
+
+func newTestSecretBox(t *testing.T) *SecretBox {
+	t.Helper()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	box, err := NewSecretBox(base64.StdEncoding.EncodeToString(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return box
+}
+
+type outcome struct {
+	delivered bool
+	code      int
+	cause     string
+	next      *time.Time
+}
+
+// memStore serves one webhook and records what the worker reports.
+type memStore struct {
+	Store
+	mu         sync.Mutex
+	webhook    *models.Webhook
+	webhookErr error
+	outcomes   map[string]outcome
+}
+
+func (s *memStore) GetWebhook(context.Context, string, string) (*models.Webhook, error) {
+	return s.webhook, s.webhookErr
+}
+
+func (s *memStore) MarkDelivered(_ context.Context, id string, code int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.outcomes[id] = outcome{delivered: true, code: code}
+	return nil
+}
+
+func (s *memStore) MarkFailed(_ context.Context, id string, code int, cause string, next *time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.outcomes[id] = outcome{code: code, cause: cause, next: next}
+	return nil
+}
+
+func newTestWorker(t *testing.T, handler http.HandlerFunc) (*Worker, *memStore, string) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	box := newTestSecretBox(t)
+	sealed, err := box.Seal("s3cret-s3cret-s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &memStore{
+		webhook:  &models.Webhook{ID: "w1", URL: srv.URL, SealedSecret: sealed},
+		outcomes: map[string]outcome{},
+	}
+	w := NewWorker(store, box, WorkerConfig{Timeout: time.Second, MaxAttempts: 3, BaseBackoff: time.Minute, MaxBackoff: time.Hour})
+	// httptest listens on loopback, which the production client refuses.
+	w.client = srv.Client()
+	return w, store, "s3cret-s3cret-s3cret"
+}
+
+func TestDeliverSignsAndMarksDelivered(t *testing.T) {
+	var got http.Header
+	var body []byte
+	w, store, secret := newTestWorker(t, func(rw http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		rw.WriteHeader(http.StatusAccepted)
+	})
+	payload := []byte(`{"type":"transaction.created"}`)
+	w.deliver(context.Background(), models.WebhookDelivery{ID: "d1", WebhookID: "w1", EventType: "transaction.created", Payload: payload})
+
+	if o := store.outcomes["d1"]; !o.delivered || o.code != http.StatusAccepted {
+		t.Fatalf("outcome %+v, want delivered with 202", o)
+	}
+	if string(body) != string(payload) {
+		t.Errorf("body %q, want %q", body, payload)
+	}
+	timestamp, err := strconv.ParseInt(got.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		t.Fatalf("timestamp header: %v", err)
+	}
+	if !Verify(secret, timestamp, payload, got.Get(HeaderSignature)) {
+		t.Errorf("signature %q does not verify", got.Get(HeaderSignature))
+	}
+	if got.Get(HeaderDelivery) != "d1" || got.Get(HeaderEvent) != "transaction.created" {
+		t.Errorf("headers %v", got)
+	}
+}
+
+func TestDeliverRetriesServerErrors(t *testing.T) {
+	w, store, _ := newTestWorker(t, func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusBadGateway)
+	})
+	ctx := context.Background()
+
+	w.deliver(ctx, models.WebhookDelivery{ID: "d1", WebhookID: "w1", Attempts: 1})
+	o := store.outcomes["d1"]
+	if o.delivered || o.code != http.StatusBadGateway || o.next == nil {
+		t.Fatalf("outcome %+v, want a retry after 502", o)
+	}
+	if wait := time.Until(*o.next); wait < 119*time.Second || wait > 2*time.Minute {
+		t.Errorf("second retry in %v, want two minutes", wait)
+	}
+
+	w.deliver(ctx, models.WebhookDelivery{ID: "d2", WebhookID: "w1", Attempts: 2})
+	if o := store.outcomes["d2"]; o.next != nil {
+		t.Errorf("outcome %+v, want a dead letter after the last attempt", o)
+	}
+}
+
+func TestDeliverRecordsAttemptWhenWebhookLookupFails(t *testing.T) {
+	w, store, _ := newTestWorker(t, func(rw http.ResponseWriter, r *http.Request) {
+		t.Error("nothing should be sent")
+	})
+	store.webhook, store.webhookErr = nil, errors.New("connection reset")
+	w.deliver(context.Background(), models.WebhookDelivery{ID: "d1", WebhookID: "w1"})
+	if o := store.outcomes["d1"]; o.next == nil || o.delivered {
+		t.Errorf("outcome %+v, want a scheduled retry", o)
+	}
+
+	store.webhookErr = nil
+	w.deliver(context.Background(), models.WebhookDelivery{ID: "d2", WebhookID: "w1"})
+	if o := store.outcomes["d2"]; o.next != nil || o.cause != "webhook was deleted" {
+		t.Errorf("outcome %+v, want a dead letter for a deleted webhook", o)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	w := &Worker{cfg: WorkerConfig{BaseBackoff: time.Second, MaxBackoff: 5 * time.Second}}
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second} {
+		if got := w.backoff(attempt); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package client
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrUserNotFound = status.Error(codes.NotFound, "user is not found")
+	ErrCircuitOpen  = status.Error(codes.Unavailable, "user service is unavailable")
+	// ErrEmptyUser is returned when the user service answers without a user
+	// or an error. It is not cached and does not mean the user is gone.
+	ErrEmptyUser = status.Error(codes.Internal, "user service returned no user")
+)
+
+type UserGetter interface {
+	GetUser(ctx context.Context, id string) (string, string, error)
+}
+
+type CacheConfig struct {
+	Size             int
+	TTL              time.Duration
+	NegativeTTL      time.Duration
+	CallTimeout      time.Duration
+	MaxRetries       int
+	BaseBackoff      time.Duration
+	MaxBackoff       time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+func DefaultCacheConfig() CacheConfig {
+	return CacheConfig{
+		Size:             10000,
+		TTL:              10 * time.Minute,
+		NegativeTTL:      30 * time.Second,
+		CallTimeout:      2 * time.Second,
+		MaxRetries:       2,
+		BaseBackoff:      100 * time.Millisecond,
+		MaxBackoff:       time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  15 * time.Second,
+	}
+}
+
+type CacheStats struct {
+	Hits         uint64
+	NegativeHits uint64
+	Misses       uint64
+	Errors       uint64
+	BreakerState string
+}
+
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits + s.NegativeHits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits+s.NegativeHits) / float64(total)
+}
+
+type cacheEntry struct {
+	key       string
+	id        string
+	name      string
+	found     bool
+	expiresAt time.Time
+}
+
+type CachedUserClient struct {
+	next    UserGetter
+	cfg     CacheConfig
+	breaker *circuitBreaker
+
+	mu    sync.Mutex
+	order *list.List
+	items map[string]*list.Element
+
+	hits         atomic.Uint64
+	negativeHits atomic.Uint64
+	misses       atomic.Uint64
+	errors       atomic.Uint64
+}
+
+func NewCachedUserClient(next UserGetter, cfg CacheConfig) *CachedUserClient {
+	return &CachedUserClient{
+		next:    next,
+		cfg:     cfg,
+		breaker: newCircuitBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+		order:   list.New(),
+		items:   make(map[string]*list.Element),
+	}
+}
+
+func (c *CachedUserClient) GetUser(ctx context.Context, id string) (string, string, error) {
+	if entry, ok := c.lookup(id); ok {
+		if !entry.found {
+			c.negativeHits.Add(1)
+			return "", "", ErrUserNotFound
+		}
+		c.hits.Add(1)
+		return entry.id, entry.name, nil
+	}
+	c.misses.Add(1)
+
+	if !c.breaker.allow() {
+		c.errors.Add(1)
+		return "", "", ErrCircuitOpen
+	}
+	userID, name, err := c.callWithRetry(ctx, id)
+	switch {
+	case err == nil && userID != "":
+		c.breaker.success()
+		c.store(id, cacheEntry{id: userID, name: name, found: true}, c.cfg.TTL)
+		return userID, name, nil
+	case err == nil:
+		c.breaker.success()
+		err = ErrEmptyUser
+	case isNotFound(err):
+		c.breaker.success()
+		c.store(id, cacheEntry{}, c.cfg.NegativeTTL)
+		return "", "", ErrUserNotFound
+	case ctx.Err() != nil:
+		// The caller gave up; that says nothing about the user service.
+		c.breaker.release()
+	case isRetryable(err):
+		c.breaker.failure()
+	default:
+		c.breaker.success()
+	}
+	c.errors.Add(1)
+	return "", "", err
+}
+
+func (c *CachedUserClient) Stats() CacheStats {
+	return CacheStats{
+		Hits:         c.hits.Load(),
+		NegativeHits: c.negativeHits.Load(),
+		Misses:       c.misses.Load(),
+		Errors:       c.errors.Load(),
+		BreakerState: c.breaker.state(),
+	}
+}
+
+func (c *CachedUserClient) callWithRetry(ctx context.Context, id string) (string, string, error) {
+	backoff := c.cfg.BaseBackoff
+	for attempt := 0; ; attempt++ {
+		callCtx, cancel := context.WithTimeout(ctx, c.cfg.CallTimeout)
+		userID, name, err := c.next.GetUser(callCtx, id)
+		cancel()
+		if err == nil || !isRetryable(err) || attempt >= c.cfg.MaxRetries || ctx.Err() != nil {
+			return userID, name, err
+		}
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		select {
+		case <-ctx.Done():
+			return "", "", err
+		case <-time.After(wait):
+		}
+		backoff *= 2
+		if backoff > c.cfg.MaxBackoff {
+			backoff = c.cfg.MaxBackoff
+		}
+	}
+}
+
+func (c *CachedUserClient) lookup(id string) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[id]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(el)
+		delete(c.items, id)
+		return cacheEntry{}, false
+	}
+	c.order.MoveToFront(el)
+	return *entry, true
+}
+
+func (c *CachedUserClient) store(key string, entry cacheEntry, ttl time.Duration) {
+	if ttl <= 0 || c.cfg.Size <= 0 {
+		return
+	}
+	entry.key = key
+	entry.expiresAt = time.Now().Add(ttl)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		el.Value = &entry
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.order.PushFront(&entry)
+	for c.order.Len() > c.cfg.Size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func isNotFound(err error) bool {
+	st, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	// MoneyKeeper-User reports missing users as a plain "not found" error.
+	return st.Code() == codes.NotFound || (st.Code() == codes.Unknown && st.Message() == "not found")
+}
+
+func isRetryable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	}
+	return false
+}
+
+const (
+	breakerClosed   = "closed"
+	breakerOpen     = "open"
+	breakerHalfOpen = "half-open"
+)
+
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	current   string
+	openedAt  time.Time
+	probing   bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown, current: breakerClosed}
+}
+
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.current {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.current = breakerHalfOpen
+		b.probing = true
+		return true
+	case breakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+	}
+	return true
+}
+
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.probing = false
+	b.current = breakerClosed
+}
+
+// release gives back a probe without judging the service.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+func (b *circuitBreaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.probing = false
+	if b.current == breakerHalfOpen || (b.threshold > 0 && b.failures >= b.threshold) {
+		b.current = breakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+func (b *circuitBreaker) state() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.current
+}
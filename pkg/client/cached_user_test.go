@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type stubUsers struct {
+	calls int
+	get   func(ctx context.Context, id string) (string, string, error)
+}
+
+func (s *stubUsers) GetUser(ctx context.Context, id string) (string, string, error) {
+	s.calls++
+	return s.get(ctx, id)
+}
+
+func testConfig() CacheConfig {
+	cfg := DefaultCacheConfig()
+	cfg.BaseBackoff = time.Millisecond
+	cfg.MaxBackoff = time.Millisecond
+	cfg.BreakerThreshold = 2
+	cfg.BreakerCooldown = time.Hour
+	return cfg
+}
+
+func TestCachedUserClientCaches(t *testing.T) {
+	next := &stubUsers{get: func(ctx context.Context, id string) (string, string, error) {
+		if id == "missing" {
+			return "", "", status.Error(codes.NotFound, "not found")
+		}
+		return id, "Name " + id, nil
+	}}
+	c := NewCachedUserClient(next, testConfig())
+	for i := 0; i < 3; i++ {
+		if id, name, err := c.GetUser(context.Background(), "u1"); err != nil || id != "u1" || name != "Name u1" {
+			t.Fatalf("GetUser = %q, %q, %v", id, name, err)
+		}
+		if _, _, err := c.GetUser(context.Background(), "missing"); !errors.Is(err, ErrUserNotFound) {
+			t.Fatalf("GetUser(missing) = %v", err)
+		}
+	}
+	if next.calls != 2 {
+		t.Fatalf("calls = %d, want 2", next.calls)
+	}
+	stats := c.Stats()
+	if stats.Hits != 2 || stats.NegativeHits != 2 || stats.Misses != 2 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
+
+func TestCachedUserClientRetriesAndOpensBreaker(t *testing.T) {
+	next := &stubUsers{get: func(ctx context.Context, id string) (string, string, error) {
+		return "", "", status.Error(codes.Unavailable, "down")
+	}}
+	cfg := testConfig()
+	c := NewCachedUserClient(next, cfg)
+	for i := 0; i < cfg.BreakerThreshold; i++ {
+		if _, _, err := c.GetUser(context.Background(), "u1"); status.Code(err) != codes.Unavailable {
+			t.Fatalf("GetUser = %v", err)
+		}
+	}
+	if next.calls != cfg.BreakerThreshold*(cfg.MaxRetries+1) {
+		t.Fatalf("calls = %d", next.calls)
+	}
+	if _, _, err := c.GetUser(context.Background(), "u1"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("GetUser with open breaker = %v", err)
+	}
+}
+
+func TestCachedUserClientIgnoresCallerDeadline(t *testing.T) {
+	next := &stubUsers{get: func(ctx context.Context, id string) (string, string, error) {
+		<-ctx.Done()
+		return "", "", status.FromContextError(ctx.Err()).Err()
+	}}
+	c := NewCachedUserClient(next, testConfig())
+	for i := 0; i < 5; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		_, _, err := c.GetUser(ctx, "u1")
+		cancel()
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+	}
+	if state := c.Stats().BreakerState; state != breakerClosed {
+		t.Fatalf("breaker = %s, want closed", state)
+	}
+}
+
+func TestCachedUserClientEmptyReplyIsNotNotFound(t *testing.T) {
+	next := &stubUsers{get: func(ctx context.Context, id string) (string, string, error) {
+		return "", "", nil
+	}}
+	c := NewCachedUserClient(next, testConfig())
+	for i := 0; i < 2; i++ {
+		_, _, err := c.GetUser(context.Background(), "u1")
+		if !errors.Is(err, ErrEmptyUser) || errors.Is(err, ErrUserNotFound) {
+			t.Fatalf("GetUser = %v, want ErrEmptyUser", err)
+		}
+	}
+	if next.calls != 2 {
+		t.Fatalf("calls = %d, want 2: empty replies must not be cached", next.calls)
+	}
+}
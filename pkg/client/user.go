@@ -2,7 +2,6 @@ package client
 
 import (
 	"context"
-	"errors"
 
 	user "github.com/justIGreK/MoneyKeeper-User/pkg/go/user"
 	"google.golang.org/grpc"
@@ -13,8 +12,9 @@ type UserClient struct {
 	client user.UserServiceClient
 }
 
-func NewUserClient(serviceAddress string) (*UserClient, error) {
-	conn, err := grpc.NewClient(serviceAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+func NewUserClient(serviceAddress string, opts ...grpc.DialOption) (*UserClient, error) {
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, opts...)
+	conn, err := grpc.NewClient(serviceAddress, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -38,8 +38,8 @@ func (uc *UserClient) GetUser(ctx context.Context, id string) (string, string, e
 	if err != nil {
 		return "", "", err
 	}
-	if req == nil {
-		return "", "", errors.New("user is not found")
+	if res == nil {
+		return "", "", ErrEmptyUser
 	}
 	return res.Id, res.Name, nil
 }
@@ -0,0 +1,293 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: transaction/goal.proto
+
+package transaction
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	GoalService_CreateGoal_FullMethodName      = "/transaction.GoalService/CreateGoal"
+	GoalService_GetGoal_FullMethodName         = "/transaction.GoalService/GetGoal"
+	GoalService_GetGoalList_FullMethodName     = "/transaction.GoalService/GetGoalList"
+	GoalService_UpdateGoal_FullMethodName      = "/transaction.GoalService/UpdateGoal"
+	GoalService_DeleteGoal_FullMethodName      = "/transaction.GoalService/DeleteGoal"
+	GoalService_AddContribution_FullMethodName = "/transaction.GoalService/AddContribution"
+)
+
+// GoalServiceClient is the client API for GoalService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type GoalServiceClient interface {
+	CreateGoal(ctx context.Context, in *CreateGoalRequest, opts ...grpc.CallOption) (*CreateGoalResponse, error)
+	GetGoal(ctx context.Context, in *GetGoalRequest, opts ...grpc.CallOption) (*GetGoalResponse, error)
+	GetGoalList(ctx context.Context, in *GetGoalListRequest, opts ...grpc.CallOption) (*GetGoalListResponse, error)
+	UpdateGoal(ctx context.Context, in *UpdateGoalRequest, opts ...grpc.CallOption) (*GetGoalResponse, error)
+	DeleteGoal(ctx context.Context, in *GetGoalRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	AddContribution(ctx context.Context, in *AddContributionRequest, opts ...grpc.CallOption) (*AddContributionResponse, error)
+}
+
+type goalServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGoalServiceClient(cc grpc.ClientConnInterface) GoalServiceClient {
+	return &goalServiceClient{cc}
+}
+
+func (c *goalServiceClient) CreateGoal(ctx context.Context, in *CreateGoalRequest, opts ...grpc.CallOption) (*CreateGoalResponse, error) {
+	out := new(CreateGoalResponse)
+	err := c.cc.Invoke(ctx, GoalService_CreateGoal_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goalServiceClient) GetGoal(ctx context.Context, in *GetGoalRequest, opts ...grpc.CallOption) (*GetGoalResponse, error) {
+	out := new(GetGoalResponse)
+	err := c.cc.Invoke(ctx, GoalService_GetGoal_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goalServiceClient) GetGoalList(ctx context.Context, in *GetGoalListRequest, opts ...grpc.CallOption) (*GetGoalListResponse, error) {
+	out := new(GetGoalListResponse)
+	err := c.cc.Invoke(ctx, GoalService_GetGoalList_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goalServiceClient) UpdateGoal(ctx context.Context, in *UpdateGoalRequest, opts ...grpc.CallOption) (*GetGoalResponse, error) {
+	out := new(GetGoalResponse)
+	err := c.cc.Invoke(ctx, GoalService_UpdateGoal_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goalServiceClient) DeleteGoal(ctx context.Context, in *GetGoalRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, GoalService_DeleteGoal_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goalServiceClient) AddContribution(ctx context.Context, in *AddContributionRequest, opts ...grpc.CallOption) (*AddContributionResponse, error) {
+	out := new(AddContributionResponse)
+	err := c.cc.Invoke(ctx, GoalService_AddContribution_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GoalServiceServer is the server API for GoalService service.
+// All implementations should embed UnimplementedGoalServiceServer
+// for forward compatibility
+type GoalServiceServer interface {
+	CreateGoal(context.Context, *CreateGoalRequest) (*CreateGoalResponse, error)
+	GetGoal(context.Context, *GetGoalRequest) (*GetGoalResponse, error)
+	GetGoalList(context.Context, *GetGoalListRequest) (*GetGoalListResponse, error)
+	UpdateGoal(context.Context, *UpdateGoalRequest) (*GetGoalResponse, error)
+	DeleteGoal(context.Context, *GetGoalRequest) (*emptypb.Empty, error)
+	AddContribution(context.Context, *AddContributionRequest) (*AddContributionResponse, error)
+}
+
+// UnimplementedGoalServiceServer should be embedded to have forward compatible implementations.
+type UnimplementedGoalServiceServer struct {
+}
+
+func (UnimplementedGoalServiceServer) CreateGoal(context.Context, *CreateGoalRequest) (*CreateGoalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGoal not implemented")
+}
+func (UnimplementedGoalServiceServer) GetGoal(context.Context, *GetGoalRequest) (*GetGoalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGoal not implemented")
+}
+func (UnimplementedGoalServiceServer) GetGoalList(context.Context, *GetGoalListRequest) (*GetGoalListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGoalList not implemented")
+}
+func (UnimplementedGoalServiceServer) UpdateGoal(context.Context, *UpdateGoalRequest) (*GetGoalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateGoal not implemented")
+}
+func (UnimplementedGoalServiceServer) DeleteGoal(context.Context, *GetGoalRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteGoal not implemented")
+}
+func (UnimplementedGoalServiceServer) AddContribution(context.Context, *AddContributionRequest) (*AddContributionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddContribution not implemented")
+}
+
+// UnsafeGoalServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GoalServiceServer will
+// result in compilation errors.
+type UnsafeGoalServiceServer interface {
+	mustEmbedUnimplementedGoalServiceServer()
+}
+
+func RegisterGoalServiceServer(s grpc.ServiceRegistrar, srv GoalServiceServer) {
+	s.RegisterService(&GoalService_ServiceDesc, srv)
+}
+
+func _GoalService_CreateGoal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateGoalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoalServiceServer).CreateGoal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoalService_CreateGoal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoalServiceServer).CreateGoal(ctx, req.(*CreateGoalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoalService_GetGoal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGoalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoalServiceServer).GetGoal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoalService_GetGoal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoalServiceServer).GetGoal(ctx, req.(*GetGoalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoalService_GetGoalList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGoalListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoalServiceServer).GetGoalList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoalService_GetGoalList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoalServiceServer).GetGoalList(ctx, req.(*GetGoalListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoalService_UpdateGoal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateGoalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoalServiceServer).UpdateGoal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoalService_UpdateGoal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoalServiceServer).UpdateGoal(ctx, req.(*UpdateGoalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoalService_DeleteGoal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGoalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoalServiceServer).DeleteGoal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoalService_DeleteGoal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoalServiceServer).DeleteGoal(ctx, req.(*GetGoalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoalService_AddContribution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddContributionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoalServiceServer).AddContribution(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoalService_AddContribution_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoalServiceServer).AddContribution(ctx, req.(*AddContributionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// GoalService_ServiceDesc is the grpc.ServiceDesc for GoalService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GoalService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "transaction.GoalService",
+	HandlerType: (*GoalServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateGoal",
+			Handler:    _GoalService_CreateGoal_Handler,
+		},
+		{
+			MethodName: "GetGoal",
+			Handler:    _GoalService_GetGoal_Handler,
+		},
+		{
+			MethodName: "GetGoalList",
+			Handler:    _GoalService_GetGoalList_Handler,
+		},
+		{
+			MethodName: "UpdateGoal",
+			Handler:    _GoalService_UpdateGoal_Handler,
+		},
+		{
+			MethodName: "DeleteGoal",
+			Handler:    _GoalService_DeleteGoal_Handler,
+		},
+		{
+			MethodName: "AddContribution",
+			Handler:    _GoalService_AddContribution_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "transaction/goal.proto",
+}
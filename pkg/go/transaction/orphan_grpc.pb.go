@@ -0,0 +1,107 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: transaction/orphan.proto
+
+package transaction
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	OrphanService_ReconcileOrphans_FullMethodName = "/transaction.OrphanService/ReconcileOrphans"
+)
+
+// OrphanServiceClient is the client API for OrphanService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type OrphanServiceClient interface {
+	ReconcileOrphans(ctx context.Context, in *ReconcileOrphansRequest, opts ...grpc.CallOption) (*OrphanReport, error)
+}
+
+type orphanServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOrphanServiceClient(cc grpc.ClientConnInterface) OrphanServiceClient {
+	return &orphanServiceClient{cc}
+}
+
+func (c *orphanServiceClient) ReconcileOrphans(ctx context.Context, in *ReconcileOrphansRequest, opts ...grpc.CallOption) (*OrphanReport, error) {
+	out := new(OrphanReport)
+	err := c.cc.Invoke(ctx, OrphanService_ReconcileOrphans_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OrphanServiceServer is the server API for OrphanService service.
+// All implementations should embed UnimplementedOrphanServiceServer
+// for forward compatibility
+type OrphanServiceServer interface {
+	ReconcileOrphans(context.Context, *ReconcileOrphansRequest) (*OrphanReport, error)
+}
+
+// UnimplementedOrphanServiceServer should be embedded to have forward compatible implementations.
+type UnimplementedOrphanServiceServer struct {
+}
+
+func (UnimplementedOrphanServiceServer) ReconcileOrphans(context.Context, *ReconcileOrphansRequest) (*OrphanReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReconcileOrphans not implemented")
+}
+
+// UnsafeOrphanServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OrphanServiceServer will
+// result in compilation errors.
+type UnsafeOrphanServiceServer interface {
+	mustEmbedUnimplementedOrphanServiceServer()
+}
+
+func RegisterOrphanServiceServer(s grpc.ServiceRegistrar, srv OrphanServiceServer) {
+	s.RegisterService(&OrphanService_ServiceDesc, srv)
+}
+
+func _OrphanService_ReconcileOrphans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReconcileOrphansRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrphanServiceServer).ReconcileOrphans(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrphanService_ReconcileOrphans_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrphanServiceServer).ReconcileOrphans(ctx, req.(*ReconcileOrphansRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OrphanService_ServiceDesc is the grpc.ServiceDesc for OrphanService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OrphanService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "transaction.OrphanService",
+	HandlerType: (*OrphanServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ReconcileOrphans",
+			Handler:    _OrphanService_ReconcileOrphans_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "transaction/orphan.proto",
+}
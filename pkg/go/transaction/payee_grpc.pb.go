@@ -0,0 +1,181 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: transaction/payee.proto
+
+package transaction
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	PayeeService_ListPayees_FullMethodName      = "/transaction.PayeeService/ListPayees"
+	PayeeService_MergePayees_FullMethodName     = "/transaction.PayeeService/MergePayees"
+	PayeeService_GetPayeeSummary_FullMethodName = "/transaction.PayeeService/GetPayeeSummary"
+)
+
+// PayeeServiceClient is the client API for PayeeService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PayeeServiceClient interface {
+	ListPayees(ctx context.Context, in *ListPayeesRequest, opts ...grpc.CallOption) (*ListPayeesResponse, error)
+	MergePayees(ctx context.Context, in *MergePayeesRequest, opts ...grpc.CallOption) (*MergePayeesResponse, error)
+	GetPayeeSummary(ctx context.Context, in *GetPayeeSummaryRequest, opts ...grpc.CallOption) (*GetPayeeSummaryResponse, error)
+}
+
+type payeeServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPayeeServiceClient(cc grpc.ClientConnInterface) PayeeServiceClient {
+	return &payeeServiceClient{cc}
+}
+
+func (c *payeeServiceClient) ListPayees(ctx context.Context, in *ListPayeesRequest, opts ...grpc.CallOption) (*ListPayeesResponse, error) {
+	out := new(ListPayeesResponse)
+	err := c.cc.Invoke(ctx, PayeeService_ListPayees_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payeeServiceClient) MergePayees(ctx context.Context, in *MergePayeesRequest, opts ...grpc.CallOption) (*MergePayeesResponse, error) {
+	out := new(MergePayeesResponse)
+	err := c.cc.Invoke(ctx, PayeeService_MergePayees_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payeeServiceClient) GetPayeeSummary(ctx context.Context, in *GetPayeeSummaryRequest, opts ...grpc.CallOption) (*GetPayeeSummaryResponse, error) {
+	out := new(GetPayeeSummaryResponse)
+	err := c.cc.Invoke(ctx, PayeeService_GetPayeeSummary_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PayeeServiceServer is the server API for PayeeService service.
+// All implementations should embed UnimplementedPayeeServiceServer
+// for forward compatibility
+type PayeeServiceServer interface {
+	ListPayees(context.Context, *ListPayeesRequest) (*ListPayeesResponse, error)
+	MergePayees(context.Context, *MergePayeesRequest) (*MergePayeesResponse, error)
+	GetPayeeSummary(context.Context, *GetPayeeSummaryRequest) (*GetPayeeSummaryResponse, error)
+}
+
+// UnimplementedPayeeServiceServer should be embedded to have forward compatible implementations.
+type UnimplementedPayeeServiceServer struct {
+}
+
+func (UnimplementedPayeeServiceServer) ListPayees(context.Context, *ListPayeesRequest) (*ListPayeesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPayees not implemented")
+}
+func (UnimplementedPayeeServiceServer) MergePayees(context.Context, *MergePayeesRequest) (*MergePayeesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergePayees not implemented")
+}
+func (UnimplementedPayeeServiceServer) GetPayeeSummary(context.Context, *GetPayeeSummaryRequest) (*GetPayeeSummaryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPayeeSummary not implemented")
+}
+
+// UnsafePayeeServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PayeeServiceServer will
+// result in compilation errors.
+type UnsafePayeeServiceServer interface {
+	mustEmbedUnimplementedPayeeServiceServer()
+}
+
+func RegisterPayeeServiceServer(s grpc.ServiceRegistrar, srv PayeeServiceServer) {
+	s.RegisterService(&PayeeService_ServiceDesc, srv)
+}
+
+func _PayeeService_ListPayees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPayeesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayeeServiceServer).ListPayees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PayeeService_ListPayees_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayeeServiceServer).ListPayees(ctx, req.(*ListPayeesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayeeService_MergePayees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergePayeesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayeeServiceServer).MergePayees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PayeeService_MergePayees_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayeeServiceServer).MergePayees(ctx, req.(*MergePayeesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayeeService_GetPayeeSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPayeeSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayeeServiceServer).GetPayeeSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PayeeService_GetPayeeSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayeeServiceServer).GetPayeeSummary(ctx, req.(*GetPayeeSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PayeeService_ServiceDesc is the grpc.ServiceDesc for PayeeService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PayeeService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "transaction.PayeeService",
+	HandlerType: (*PayeeServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListPayees",
+			Handler:    _PayeeService_ListPayees_Handler,
+		},
+		{
+			MethodName: "MergePayees",
+			Handler:    _PayeeService_MergePayees_Handler,
+		},
+		{
+			MethodName: "GetPayeeSummary",
+			Handler:    _PayeeService_GetPayeeSummary_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "transaction/payee.proto",
+}
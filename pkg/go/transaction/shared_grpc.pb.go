@@ -0,0 +1,329 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: transaction/shared.proto
+
+package transaction
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	SharedExpenseService_CreateGroup_FullMethodName         = "/transaction.SharedExpenseService/CreateGroup"
+	SharedExpenseService_GetGroup_FullMethodName            = "/transaction.SharedExpenseService/GetGroup"
+	SharedExpenseService_AddGroupMember_FullMethodName      = "/transaction.SharedExpenseService/AddGroupMember"
+	SharedExpenseService_CreateSharedExpense_FullMethodName = "/transaction.SharedExpenseService/CreateSharedExpense"
+	SharedExpenseService_GetSharedExpenses_FullMethodName   = "/transaction.SharedExpenseService/GetSharedExpenses"
+	SharedExpenseService_GetBalances_FullMethodName         = "/transaction.SharedExpenseService/GetBalances"
+	SharedExpenseService_SettleUp_FullMethodName            = "/transaction.SharedExpenseService/SettleUp"
+)
+
+// SharedExpenseServiceClient is the client API for SharedExpenseService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SharedExpenseServiceClient interface {
+	CreateGroup(ctx context.Context, in *CreateGroupRequest, opts ...grpc.CallOption) (*GroupResponse, error)
+	GetGroup(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*GroupResponse, error)
+	AddGroupMember(ctx context.Context, in *AddGroupMemberRequest, opts ...grpc.CallOption) (*GroupResponse, error)
+	CreateSharedExpense(ctx context.Context, in *CreateSharedExpenseRequest, opts ...grpc.CallOption) (*CreateSharedExpenseResponse, error)
+	GetSharedExpenses(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*GetSharedExpensesResponse, error)
+	GetBalances(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*GetBalancesResponse, error)
+	SettleUp(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*SettleUpResponse, error)
+}
+
+type sharedExpenseServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSharedExpenseServiceClient(cc grpc.ClientConnInterface) SharedExpenseServiceClient {
+	return &sharedExpenseServiceClient{cc}
+}
+
+func (c *sharedExpenseServiceClient) CreateGroup(ctx context.Context, in *CreateGroupRequest, opts ...grpc.CallOption) (*GroupResponse, error) {
+	out := new(GroupResponse)
+	err := c.cc.Invoke(ctx, SharedExpenseService_CreateGroup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sharedExpenseServiceClient) GetGroup(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*GroupResponse, error) {
+	out := new(GroupResponse)
+	err := c.cc.Invoke(ctx, SharedExpenseService_GetGroup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sharedExpenseServiceClient) AddGroupMember(ctx context.Context, in *AddGroupMemberRequest, opts ...grpc.CallOption) (*GroupResponse, error) {
+	out := new(GroupResponse)
+	err := c.cc.Invoke(ctx, SharedExpenseService_AddGroupMember_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sharedExpenseServiceClient) CreateSharedExpense(ctx context.Context, in *CreateSharedExpenseRequest, opts ...grpc.CallOption) (*CreateSharedExpenseResponse, error) {
+	out := new(CreateSharedExpenseResponse)
+	err := c.cc.Invoke(ctx, SharedExpenseService_CreateSharedExpense_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sharedExpenseServiceClient) GetSharedExpenses(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*GetSharedExpensesResponse, error) {
+	out := new(GetSharedExpensesResponse)
+	err := c.cc.Invoke(ctx, SharedExpenseService_GetSharedExpenses_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sharedExpenseServiceClient) GetBalances(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*GetBalancesResponse, error) {
+	out := new(GetBalancesResponse)
+	err := c.cc.Invoke(ctx, SharedExpenseService_GetBalances_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sharedExpenseServiceClient) SettleUp(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*SettleUpResponse, error) {
+	out := new(SettleUpResponse)
+	err := c.cc.Invoke(ctx, SharedExpenseService_SettleUp_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SharedExpenseServiceServer is the server API for SharedExpenseService service.
+// All implementations should embed UnimplementedSharedExpenseServiceServer
+// for forward compatibility
+type SharedExpenseServiceServer interface {
+	CreateGroup(context.Context, *CreateGroupRequest) (*GroupResponse, error)
+	GetGroup(context.Context, *GroupRequest) (*GroupResponse, error)
+	AddGroupMember(context.Context, *AddGroupMemberRequest) (*GroupResponse, error)
+	CreateSharedExpense(context.Context, *CreateSharedExpenseRequest) (*CreateSharedExpenseResponse, error)
+	GetSharedExpenses(context.Context, *GroupRequest) (*GetSharedExpensesResponse, error)
+	GetBalances(context.Context, *GroupRequest) (*GetBalancesResponse, error)
+	SettleUp(context.Context, *GroupRequest) (*SettleUpResponse, error)
+}
+
+// UnimplementedSharedExpenseServiceServer should be embedded to have forward compatible implementations.
+type UnimplementedSharedExpenseServiceServer struct {
+}
+
+func (UnimplementedSharedExpenseServiceServer) CreateGroup(context.Context, *CreateGroupRequest) (*GroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGroup not implemented")
+}
+func (UnimplementedSharedExpenseServiceServer) GetGroup(context.Context, *GroupRequest) (*GroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGroup not implemented")
+}
+func (UnimplementedSharedExpenseServiceServer) AddGroupMember(context.Context, *AddGroupMemberRequest) (*GroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddGroupMember not implemented")
+}
+func (UnimplementedSharedExpenseServiceServer) CreateSharedExpense(context.Context, *CreateSharedExpenseRequest) (*CreateSharedExpenseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSharedExpense not implemented")
+}
+func (UnimplementedSharedExpenseServiceServer) GetSharedExpenses(context.Context, *GroupRequest) (*GetSharedExpensesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSharedExpenses not implemented")
+}
+func (UnimplementedSharedExpenseServiceServer) GetBalances(context.Context, *GroupRequest) (*GetBalancesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalances not implemented")
+}
+func (UnimplementedSharedExpenseServiceServer) SettleUp(context.Context, *GroupRequest) (*SettleUpResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SettleUp not implemented")
+}
+
+// UnsafeSharedExpenseServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SharedExpenseServiceServer will
+// result in compilation errors.
+type UnsafeSharedExpenseServiceServer interface {
+	mustEmbedUnimplementedSharedExpenseServiceServer()
+}
+
+func RegisterSharedExpenseServiceServer(s grpc.ServiceRegistrar, srv SharedExpenseServiceServer) {
+	s.RegisterService(&SharedExpenseService_ServiceDesc, srv)
+}
+
+func _SharedExpenseService_CreateGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SharedExpenseServiceServer).CreateGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SharedExpenseService_CreateGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SharedExpenseServiceServer).CreateGroup(ctx, req.(*CreateGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SharedExpenseService_GetGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SharedExpenseServiceServer).GetGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SharedExpenseService_GetGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SharedExpenseServiceServer).GetGroup(ctx, req.(*GroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SharedExpenseService_AddGroupMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddGroupMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SharedExpenseServiceServer).AddGroupMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SharedExpenseService_AddGroupMember_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SharedExpenseServiceServer).AddGroupMember(ctx, req.(*AddGroupMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SharedExpenseService_CreateSharedExpense_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSharedExpenseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SharedExpenseServiceServer).CreateSharedExpense(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SharedExpenseService_CreateSharedExpense_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SharedExpenseServiceServer).CreateSharedExpense(ctx, req.(*CreateSharedExpenseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SharedExpenseService_GetSharedExpenses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SharedExpenseServiceServer).GetSharedExpenses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SharedExpenseService_GetSharedExpenses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SharedExpenseServiceServer).GetSharedExpenses(ctx, req.(*GroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SharedExpenseService_GetBalances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SharedExpenseServiceServer).GetBalances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SharedExpenseService_GetBalances_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SharedExpenseServiceServer).GetBalances(ctx, req.(*GroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SharedExpenseService_SettleUp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SharedExpenseServiceServer).SettleUp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SharedExpenseService_SettleUp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SharedExpenseServiceServer).SettleUp(ctx, req.(*GroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SharedExpenseService_ServiceDesc is the grpc.ServiceDesc for SharedExpenseService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SharedExpenseService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "transaction.SharedExpenseService",
+	HandlerType: (*SharedExpenseServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateGroup",
+			Handler:    _SharedExpenseService_CreateGroup_Handler,
+		},
+		{
+			MethodName: "GetGroup",
+			Handler:    _SharedExpenseService_GetGroup_Handler,
+		},
+		{
+			MethodName: "AddGroupMember",
+			Handler:    _SharedExpenseService_AddGroupMember_Handler,
+		},
+		{
+			MethodName: "CreateSharedExpense",
+			Handler:    _SharedExpenseService_CreateSharedExpense_Handler,
+		},
+		{
+			MethodName: "GetSharedExpenses",
+			Handler:    _SharedExpenseService_GetSharedExpenses_Handler,
+		},
+		{
+			MethodName: "GetBalances",
+			Handler:    _SharedExpenseService_GetBalances_Handler,
+		},
+		{
+			MethodName: "SettleUp",
+			Handler:    _SharedExpenseService_SettleUp_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "transaction/shared.proto",
+}
@@ -20,12 +20,19 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	TransactionService_CreateTransaction_FullMethodName  = "/transaction.TransactionService/CreateTransaction"
-	TransactionService_GetTransaction_FullMethodName     = "/transaction.TransactionService/GetTransaction"
-	TransactionService_UpdateTransaction_FullMethodName  = "/transaction.TransactionService/UpdateTransaction"
-	TransactionService_DeleteTransaction_FullMethodName  = "/transaction.TransactionService/DeleteTransaction"
-	TransactionService_GetTransactionList_FullMethodName = "/transaction.TransactionService/GetTransactionList"
-	TransactionService_GetTXByTimeFrame_FullMethodName   = "/transaction.TransactionService/GetTXByTimeFrame"
+	TransactionService_CreateTransaction_FullMethodName    = "/transaction.TransactionService/CreateTransaction"
+	TransactionService_GetTransaction_FullMethodName       = "/transaction.TransactionService/GetTransaction"
+	TransactionService_UpdateTransaction_FullMethodName    = "/transaction.TransactionService/UpdateTransaction"
+	TransactionService_DeleteTransaction_FullMethodName    = "/transaction.TransactionService/DeleteTransaction"
+	TransactionService_GetTransactionList_FullMethodName   = "/transaction.TransactionService/GetTransactionList"
+	TransactionService_GetTXByTimeFrame_FullMethodName     = "/transaction.TransactionService/GetTXByTimeFrame"
+	TransactionService_GetSpendingSummary_FullMethodName   = "/transaction.TransactionService/GetSpendingSummary"
+	TransactionService_GetCashFlowStatement_FullMethodName = "/transaction.TransactionService/GetCashFlowStatement"
+	TransactionService_SuggestCategory_FullMethodName      = "/transaction.TransactionService/SuggestCategory"
+	TransactionService_FindDuplicates_FullMethodName       = "/transaction.TransactionService/FindDuplicates"
+	TransactionService_ListAnomalies_FullMethodName        = "/transaction.TransactionService/ListAnomalies"
+	TransactionService_ForecastSpending_FullMethodName     = "/transaction.TransactionService/ForecastSpending"
+	TransactionService_MergeTransactions_FullMethodName    = "/transaction.TransactionService/MergeTransactions"
 )
 
 // TransactionServiceClient is the client API for TransactionService service.
@@ -38,6 +45,13 @@ type TransactionServiceClient interface {
 	DeleteTransaction(ctx context.Context, in *DeleteTransactionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	GetTransactionList(ctx context.Context, in *GetTransactionListRequest, opts ...grpc.CallOption) (*GetTransactionListResponse, error)
 	GetTXByTimeFrame(ctx context.Context, in *GetTXByTimeFrameRequest, opts ...grpc.CallOption) (*GetTransactionListResponse, error)
+	GetSpendingSummary(ctx context.Context, in *GetTXByTimeFrameRequest, opts ...grpc.CallOption) (*GetSpendingSummaryResponse, error)
+	GetCashFlowStatement(ctx context.Context, in *GetCashFlowStatementRequest, opts ...grpc.CallOption) (*GetCashFlowStatementResponse, error)
+	SuggestCategory(ctx context.Context, in *SuggestCategoryRequest, opts ...grpc.CallOption) (*SuggestCategoryResponse, error)
+	FindDuplicates(ctx context.Context, in *FindDuplicatesRequest, opts ...grpc.CallOption) (*FindDuplicatesResponse, error)
+	ListAnomalies(ctx context.Context, in *ListAnomaliesRequest, opts ...grpc.CallOption) (*GetTransactionListResponse, error)
+	ForecastSpending(ctx context.Context, in *ForecastSpendingRequest, opts ...grpc.CallOption) (*ForecastSpendingResponse, error)
+	MergeTransactions(ctx context.Context, in *MergeTransactionsRequest, opts ...grpc.CallOption) (*GetTransactionResponse, error)
 }
 
 type transactionServiceClient struct {
@@ -102,6 +116,69 @@ func (c *transactionServiceClient) GetTXByTimeFrame(ctx context.Context, in *Get
 	return out, nil
 }
 
+func (c *transactionServiceClient) GetSpendingSummary(ctx context.Context, in *GetTXByTimeFrameRequest, opts ...grpc.CallOption) (*GetSpendingSummaryResponse, error) {
+	out := new(GetSpendingSummaryResponse)
+	err := c.cc.Invoke(ctx, TransactionService_GetSpendingSummary_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactionServiceClient) GetCashFlowStatement(ctx context.Context, in *GetCashFlowStatementRequest, opts ...grpc.CallOption) (*GetCashFlowStatementResponse, error) {
+	out := new(GetCashFlowStatementResponse)
+	err := c.cc.Invoke(ctx, TransactionService_GetCashFlowStatement_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactionServiceClient) SuggestCategory(ctx context.Context, in *SuggestCategoryRequest, opts ...grpc.CallOption) (*SuggestCategoryResponse, error) {
+	out := new(SuggestCategoryResponse)
+	err := c.cc.Invoke(ctx, TransactionService_SuggestCategory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactionServiceClient) FindDuplicates(ctx context.Context, in *FindDuplicatesRequest, opts ...grpc.CallOption) (*FindDuplicatesResponse, error) {
+	out := new(FindDuplicatesResponse)
+	err := c.cc.Invoke(ctx, TransactionService_FindDuplicates_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactionServiceClient) ListAnomalies(ctx context.Context, in *ListAnomaliesRequest, opts ...grpc.CallOption) (*GetTransactionListResponse, error) {
+	out := new(GetTransactionListResponse)
+	err := c.cc.Invoke(ctx, TransactionService_ListAnomalies_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactionServiceClient) ForecastSpending(ctx context.Context, in *ForecastSpendingRequest, opts ...grpc.CallOption) (*ForecastSpendingResponse, error) {
+	out := new(ForecastSpendingResponse)
+	err := c.cc.Invoke(ctx, TransactionService_ForecastSpending_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactionServiceClient) MergeTransactions(ctx context.Context, in *MergeTransactionsRequest, opts ...grpc.CallOption) (*GetTransactionResponse, error) {
+	out := new(GetTransactionResponse)
+	err := c.cc.Invoke(ctx, TransactionService_MergeTransactions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TransactionServiceServer is the server API for TransactionService service.
 // All implementations should embed UnimplementedTransactionServiceServer
 // for forward compatibility
@@ -112,6 +189,13 @@ type TransactionServiceServer interface {
 	DeleteTransaction(context.Context, *DeleteTransactionRequest) (*emptypb.Empty, error)
 	GetTransactionList(context.Context, *GetTransactionListRequest) (*GetTransactionListResponse, error)
 	GetTXByTimeFrame(context.Context, *GetTXByTimeFrameRequest) (*GetTransactionListResponse, error)
+	GetSpendingSummary(context.Context, *GetTXByTimeFrameRequest) (*GetSpendingSummaryResponse, error)
+	GetCashFlowStatement(context.Context, *GetCashFlowStatementRequest) (*GetCashFlowStatementResponse, error)
+	SuggestCategory(context.Context, *SuggestCategoryRequest) (*SuggestCategoryResponse, error)
+	FindDuplicates(context.Context, *FindDuplicatesRequest) (*FindDuplicatesResponse, error)
+	ListAnomalies(context.Context, *ListAnomaliesRequest) (*GetTransactionListResponse, error)
+	ForecastSpending(context.Context, *ForecastSpendingRequest) (*ForecastSpendingResponse, error)
+	MergeTransactions(context.Context, *MergeTransactionsRequest) (*GetTransactionResponse, error)
 }
 
 // UnimplementedTransactionServiceServer should be embedded to have forward compatible implementations.
@@ -136,6 +220,27 @@ func (UnimplementedTransactionServiceServer) GetTransactionList(context.Context,
 func (UnimplementedTransactionServiceServer) GetTXByTimeFrame(context.Context, *GetTXByTimeFrameRequest) (*GetTransactionListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetTXByTimeFrame not implemented")
 }
+func (UnimplementedTransactionServiceServer) GetSpendingSummary(context.Context, *GetTXByTimeFrameRequest) (*GetSpendingSummaryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSpendingSummary not implemented")
+}
+func (UnimplementedTransactionServiceServer) GetCashFlowStatement(context.Context, *GetCashFlowStatementRequest) (*GetCashFlowStatementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCashFlowStatement not implemented")
+}
+func (UnimplementedTransactionServiceServer) SuggestCategory(context.Context, *SuggestCategoryRequest) (*SuggestCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuggestCategory not implemented")
+}
+func (UnimplementedTransactionServiceServer) FindDuplicates(context.Context, *FindDuplicatesRequest) (*FindDuplicatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindDuplicates not implemented")
+}
+func (UnimplementedTransactionServiceServer) ListAnomalies(context.Context, *ListAnomaliesRequest) (*GetTransactionListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAnomalies not implemented")
+}
+func (UnimplementedTransactionServiceServer) ForecastSpending(context.Context, *ForecastSpendingRequest) (*ForecastSpendingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForecastSpending not implemented")
+}
+func (UnimplementedTransactionServiceServer) MergeTransactions(context.Context, *MergeTransactionsRequest) (*GetTransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergeTransactions not implemented")
+}
 
 // UnsafeTransactionServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to TransactionServiceServer will
@@ -256,6 +361,132 @@ func _TransactionService_GetTXByTimeFrame_Handler(srv interface{}, ctx context.C
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TransactionService_GetSpendingSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTXByTimeFrameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionServiceServer).GetSpendingSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransactionService_GetSpendingSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionServiceServer).GetSpendingSummary(ctx, req.(*GetTXByTimeFrameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransactionService_GetCashFlowStatement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCashFlowStatementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionServiceServer).GetCashFlowStatement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransactionService_GetCashFlowStatement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionServiceServer).GetCashFlowStatement(ctx, req.(*GetCashFlowStatementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransactionService_SuggestCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionServiceServer).SuggestCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransactionService_SuggestCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionServiceServer).SuggestCategory(ctx, req.(*SuggestCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransactionService_FindDuplicates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindDuplicatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionServiceServer).FindDuplicates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransactionService_FindDuplicates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionServiceServer).FindDuplicates(ctx, req.(*FindDuplicatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransactionService_ListAnomalies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAnomaliesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionServiceServer).ListAnomalies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransactionService_ListAnomalies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionServiceServer).ListAnomalies(ctx, req.(*ListAnomaliesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransactionService_ForecastSpending_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForecastSpendingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionServiceServer).ForecastSpending(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransactionService_ForecastSpending_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionServiceServer).ForecastSpending(ctx, req.(*ForecastSpendingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransactionService_MergeTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionServiceServer).MergeTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransactionService_MergeTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionServiceServer).MergeTransactions(ctx, req.(*MergeTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TransactionService_ServiceDesc is the grpc.ServiceDesc for TransactionService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -287,6 +518,34 @@ var TransactionService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetTXByTimeFrame",
 			Handler:    _TransactionService_GetTXByTimeFrame_Handler,
 		},
+		{
+			MethodName: "GetSpendingSummary",
+			Handler:    _TransactionService_GetSpendingSummary_Handler,
+		},
+		{
+			MethodName: "GetCashFlowStatement",
+			Handler:    _TransactionService_GetCashFlowStatement_Handler,
+		},
+		{
+			MethodName: "SuggestCategory",
+			Handler:    _TransactionService_SuggestCategory_Handler,
+		},
+		{
+			MethodName: "FindDuplicates",
+			Handler:    _TransactionService_FindDuplicates_Handler,
+		},
+		{
+			MethodName: "ListAnomalies",
+			Handler:    _TransactionService_ListAnomalies_Handler,
+		},
+		{
+			MethodName: "ForecastSpending",
+			Handler:    _TransactionService_ForecastSpending_Handler,
+		},
+		{
+			MethodName: "MergeTransactions",
+			Handler:    _TransactionService_MergeTransactions_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "transaction/transaction.proto",